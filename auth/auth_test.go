@@ -0,0 +1,177 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vitalyisaev2/buildgraph/config"
+)
+
+func request(header, value string) *http.Request {
+	r, _ := http.NewRequest("GET", "/projects", nil)
+	if header != "" {
+		r.Header.Set(header, value)
+	}
+	return r
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, key interface{}, kid string, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	result, err := token.SignedString(key)
+	assert.NoError(t, err)
+	return result
+}
+
+func TestPrincipal(t *testing.T) {
+	p := &Principal{
+		Name: "bot",
+		Grants: []*config.RoleGrant{
+			{Role: config.RoleOperator, Namespaces: []string{"namespace1"}},
+			{Role: config.RoleViewer, Namespaces: []string{config.AllNamespaces}},
+		},
+	}
+	assert.True(t, p.Can(config.RoleViewer, "namespace2"))
+	assert.True(t, p.Can(config.RoleViewer, config.AllNamespaces))
+	assert.True(t, p.Can(config.RoleOperator, "namespace1"))
+	assert.False(t, p.Can(config.RoleOperator, "namespace2"))
+	assert.False(t, p.Can(config.RoleOperator, config.AllNamespaces))
+	assert.True(t, p.CanAny(config.RoleOperator))
+	assert.False(t, p.CanAny(config.RoleAdmin))
+
+	assert.True(t, anonymous.Can(config.RoleAdmin, config.AllNamespaces))
+	assert.False(t, (&Principal{Name: "nobody"}).CanAny(config.RoleViewer))
+}
+
+func TestAPIKeys(t *testing.T) {
+	a, err := NewAuthenticator(&config.AuthConfig{
+		APIKeys: []*config.APIKeyConfig{
+			{Name: "bot", Key: "key1", Roles: []*config.RoleGrant{{Role: config.RoleViewer, Namespaces: []string{"namespace1"}}}},
+		},
+	})
+	assert.NoError(t, err)
+	assert.True(t, a.Enabled())
+
+	p, err := a.Authenticate(request(APIKeyHeader, "key1"))
+	assert.NoError(t, err)
+	assert.Equal(t, "bot", p.Name)
+	p, err = a.Authenticate(request("Authorization", "Bearer key1"))
+	assert.NoError(t, err)
+	assert.Equal(t, "bot", p.Name)
+
+	_, err = a.Authenticate(request(APIKeyHeader, "key2"))
+	assert.Equal(t, ErrInvalidCredentials, err)
+	_, err = a.Authenticate(request("Authorization", "Bearer key2"))
+	assert.Equal(t, ErrInvalidCredentials, err)
+	_, err = a.Authenticate(request("", ""))
+	assert.Equal(t, ErrNoCredentials, err)
+
+	a, err = NewAuthenticator(nil)
+	assert.NoError(t, err)
+	assert.False(t, a.Enabled())
+	p, err = a.Authenticate(request("", ""))
+	assert.NoError(t, err)
+	assert.Equal(t, anonymous, p)
+}
+
+func TestHMACTokens(t *testing.T) {
+	grants := []*config.RoleGrant{{Role: config.RoleAdmin, Namespaces: []string{config.AllNamespaces}}}
+	a, err := NewAuthenticator(&config.AuthConfig{
+		JWT: &config.JWTConfig{
+			HMACSecret:   "secret",
+			Issuer:       "idp",
+			SubjectClaim: "email",
+			Subjects:     map[string][]*config.RoleGrant{"alice@example.com": grants},
+		},
+	})
+	assert.NoError(t, err)
+
+	expires := time.Now().Add(time.Hour).Unix()
+	token := sign(t, jwt.SigningMethodHS256, []byte("secret"), "", jwt.MapClaims{
+		"iss": "idp", "email": "alice@example.com", "exp": expires,
+	})
+	p, err := a.Authenticate(request("Authorization", "Bearer "+token))
+	assert.NoError(t, err)
+	assert.Equal(t, "alice@example.com", p.Name)
+	assert.Equal(t, grants, p.Grants)
+
+	// unknown subjects have no roles
+	token = sign(t, jwt.SigningMethodHS256, []byte("secret"), "", jwt.MapClaims{
+		"iss": "idp", "email": "bob@example.com", "exp": expires,
+	})
+	p, err = a.Authenticate(request("Authorization", "Bearer "+token))
+	assert.NoError(t, err)
+	assert.Empty(t, p.Grants)
+
+	invalid := []jwt.MapClaims{
+		{"iss": "other", "email": "alice@example.com", "exp": expires},
+		{"iss": "idp", "email": "alice@example.com", "exp": time.Now().Add(-time.Hour).Unix()},
+		{"iss": "idp", "email": "alice@example.com"},
+		{"iss": "idp", "exp": expires},
+	}
+	for _, claims := range invalid {
+		token = sign(t, jwt.SigningMethodHS256, []byte("secret"), "", claims)
+		_, err = a.Authenticate(request("Authorization", "Bearer "+token))
+		assert.Error(t, err)
+	}
+
+	token = sign(t, jwt.SigningMethodHS256, []byte("other"), "", jwt.MapClaims{
+		"iss": "idp", "email": "alice@example.com", "exp": expires,
+	})
+	_, err = a.Authenticate(request("Authorization", "Bearer "+token))
+	assert.Error(t, err)
+}
+
+func TestJWKSTokens(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	encode := func(data []byte) string { return base64.RawURLEncoding.EncodeToString(data) }
+	jwks, err := json.Marshal(map[string]interface{}{
+		"keys": []map[string]string{
+			{"kty": "EC", "kid": "key1", "crv": "P-256", "x": encode(key.X.Bytes()), "y": encode(key.Y.Bytes())},
+			{"kty": "RSA", "kid": "enc", "use": "enc", "n": "AQAB", "e": "AQAB"},
+		},
+	})
+	assert.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	assert.NoError(t, ioutil.WriteFile(path, jwks, 0600))
+
+	a, err := NewAuthenticator(&config.AuthConfig{
+		JWT: &config.JWTConfig{JWKSFile: path, SubjectClaim: "sub"},
+	})
+	assert.NoError(t, err)
+
+	claims := jwt.MapClaims{"sub": "alice", "exp": time.Now().Add(time.Hour).Unix()}
+	token := sign(t, jwt.SigningMethodES256, key, "key1", claims)
+	p, err := a.Authenticate(request("Authorization", "Bearer "+token))
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", p.Name)
+
+	// key ID may be omitted, since there is a single signing key
+	token = sign(t, jwt.SigningMethodES256, key, "", claims)
+	_, err = a.Authenticate(request("Authorization", "Bearer "+token))
+	assert.NoError(t, err)
+
+	token = sign(t, jwt.SigningMethodES256, key, "key2", claims)
+	_, err = a.Authenticate(request("Authorization", "Bearer "+token))
+	assert.Error(t, err)
+
+	// HMAC tokens are not accepted without HMAC secret
+	token = sign(t, jwt.SigningMethodHS256, []byte("secret"), "", claims)
+	_, err = a.Authenticate(request("Authorization", "Bearer "+token))
+	assert.Error(t, err)
+}
@@ -0,0 +1,105 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/vitalyisaev2/buildgraph/config"
+)
+
+// APIKeyHeader carries static API key
+const APIKeyHeader = "X-API-Key"
+
+var (
+	// ErrNoCredentials is returned when request carries neither API key nor bearer token
+	ErrNoCredentials = errors.New("API key or bearer token is required")
+	// ErrInvalidCredentials is returned when API key or token is not accepted
+	ErrInvalidCredentials = errors.New("invalid API key or bearer token")
+)
+
+// Authenticator identifies API clients by request credentials
+type Authenticator interface {
+	// Enabled returns false if every request is allowed to do everything
+	Enabled() bool
+	// Authenticate returns client who sent the request
+	Authenticate(r *http.Request) (*Principal, error)
+}
+
+var _ Authenticator = (*defaultAuthenticator)(nil)
+
+type defaultAuthenticator struct {
+	keys []*config.APIKeyConfig
+	jwt  *jwtValidator // nil if bearer tokens are not configured
+}
+
+func (a *defaultAuthenticator) Enabled() bool { return true }
+
+func (a *defaultAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	if key := r.Header.Get(APIKeyHeader); key != "" {
+		if p := a.matchKey(key); p != nil {
+			return p, nil
+		}
+		return nil, ErrInvalidCredentials
+	}
+
+	token := bearerToken(r)
+	if token == "" {
+		return nil, ErrNoCredentials
+	}
+	// API keys may be sent as bearer tokens as well
+	if p := a.matchKey(token); p != nil {
+		return p, nil
+	}
+	if a.jwt == nil {
+		return nil, ErrInvalidCredentials
+	}
+	return a.jwt.validate(token)
+}
+
+// matchKey compares key with every configured one in constant time
+func (a *defaultAuthenticator) matchKey(key string) *Principal {
+	var result *Principal
+	for _, k := range a.keys {
+		if subtle.ConstantTimeCompare([]byte(k.Key.Value()), []byte(key)) == 1 {
+			result = &Principal{Name: k.Name, Grants: k.Roles}
+		}
+	}
+	return result
+}
+
+func bearerToken(r *http.Request) string {
+	const prefix = "bearer "
+	header := r.Header.Get("Authorization")
+	if len(header) <= len(prefix) || !strings.EqualFold(header[:len(prefix)], prefix) {
+		return ""
+	}
+	return strings.TrimSpace(header[len(prefix):])
+}
+
+var _ Authenticator = disabledAuthenticator{}
+
+// disabledAuthenticator lets anyone do everything
+type disabledAuthenticator struct{}
+
+func (disabledAuthenticator) Enabled() bool { return false }
+
+func (disabledAuthenticator) Authenticate(*http.Request) (*Principal, error) { return anonymous, nil }
+
+// NewAuthenticator builds Authenticator from config;
+// authentication is disabled if config is nil
+func NewAuthenticator(cfg *config.AuthConfig) (Authenticator, error) {
+	if cfg == nil {
+		return disabledAuthenticator{}, nil
+	}
+
+	a := &defaultAuthenticator{keys: cfg.APIKeys}
+	if cfg.JWT != nil {
+		var err error
+		if a.jwt, err = newJWTValidator(cfg.JWT); err != nil {
+			return nil, err
+		}
+	}
+	return a, nil
+}
@@ -0,0 +1,91 @@
+package auth
+
+import (
+	"crypto"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/vitalyisaev2/buildgraph/config"
+)
+
+// allowed difference between clocks of the server and identity provider
+const clockSkew = 30 * time.Second
+
+var (
+	hmacMethods       = []string{"HS256", "HS384", "HS512"}
+	asymmetricMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+)
+
+// jwtValidator checks signature and claims of bearer tokens
+type jwtValidator struct {
+	cfg    *config.JWTConfig
+	keys   map[string]crypto.PublicKey // JWKS keys by ID
+	parser *jwt.Parser
+}
+
+func (v *jwtValidator) validate(token string) (*Principal, error) {
+	claims := jwt.MapClaims{}
+	if _, err := v.parser.ParseWithClaims(token, claims, v.key); err != nil {
+		return nil, fmt.Errorf("%v: %v", ErrInvalidCredentials, err)
+	}
+
+	subject, _ := claims[v.cfg.SubjectClaim].(string)
+	if subject == "" {
+		return nil, fmt.Errorf("%v: claim '%s' is missing", ErrInvalidCredentials, v.cfg.SubjectClaim)
+	}
+
+	// valid tokens of unknown subjects are authenticated, but have no roles
+	return &Principal{Name: subject, Grants: v.cfg.Subjects[subject]}, nil
+}
+
+// key returns key the token should be verified with
+func (v *jwtValidator) key(token *jwt.Token) (interface{}, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok {
+		return []byte(v.cfg.HMACSecret.Value()), nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	if key, exists := v.keys[kid]; exists {
+		return key, nil
+	}
+	// key ID may be omitted if there is a single key
+	if kid == "" && len(v.keys) == 1 {
+		for _, key := range v.keys {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown key '%s'", kid)
+}
+
+func newJWTValidator(cfg *config.JWTConfig) (*jwtValidator, error) {
+	v := &jwtValidator{cfg: cfg}
+
+	var methods []string
+	if cfg.HMACSecret != "" {
+		methods = append(methods, hmacMethods...)
+	}
+	if cfg.JWKSFile != "" {
+		var err error
+		if v.keys, err = loadJWKS(cfg.JWKSFile); err != nil {
+			return nil, err
+		}
+		methods = append(methods, asymmetricMethods...)
+	}
+
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(methods),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(clockSkew),
+	}
+	if cfg.Issuer != "" {
+		options = append(options, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		options = append(options, jwt.WithAudience(cfg.Audience))
+	}
+	v.parser = jwt.NewParser(options...)
+
+	return v, nil
+}
@@ -0,0 +1,66 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/vitalyisaev2/buildgraph/config"
+)
+
+// roles ordered by permissions they give
+var roleRanks = map[string]int{
+	config.RoleViewer:   1,
+	config.RoleOperator: 2,
+	config.RoleAdmin:    3,
+}
+
+// Principal is an authenticated API client
+type Principal struct {
+	Name   string // empty if authentication is disabled
+	Grants []*config.RoleGrant
+}
+
+// anonymous is allowed to do everything when authentication is disabled
+var anonymous = &Principal{
+	Grants: []*config.RoleGrant{
+		{Role: config.RoleAdmin, Namespaces: []string{config.AllNamespaces}},
+	},
+}
+
+// Can reports whether principal has role (or a higher one) within namespace;
+// for config.AllNamespaces the role has to be granted for every namespace
+func (p *Principal) Can(role, namespace string) bool {
+	for _, g := range p.Grants {
+		if roleRanks[g.Role] < roleRanks[role] {
+			continue
+		}
+		for _, ns := range g.Namespaces {
+			if ns == config.AllNamespaces || ns == namespace {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// CanAny reports whether principal has role within at least one namespace
+func (p *Principal) CanAny(role string) bool {
+	for _, g := range p.Grants {
+		if roleRanks[g.Role] >= roleRanks[role] && len(g.Namespaces) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+type contextKey struct{}
+
+// NewContext returns context carrying principal
+func NewContext(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, contextKey{}, p)
+}
+
+// FromContext returns principal kept in context, nil if there is none
+func FromContext(ctx context.Context) *Principal {
+	p, _ := ctx.Value(contextKey{}).(*Principal)
+	return p
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"syscall"
 
 	"github.com/sirupsen/logrus"
 	"github.com/vitalyisaev2/buildgraph/config"
@@ -13,6 +14,12 @@ import (
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		if cmd := findCommand(os.Args[1]); cmd != nil {
+			os.Exit(cmd.run(os.Args[2:]))
+		}
+	}
+
 	var path string
 	flag.StringVar(&path, "c", "", "path to config")
 	flag.Parse()
@@ -37,8 +44,8 @@ func makeLogger() *logrus.Logger {
 
 func run(logger *logrus.Logger, cfg *config.Config) {
 	var (
-		stopChan = make(chan os.Signal)
-		errChan  = make(chan error)
+		sigChan = make(chan os.Signal, 1)
+		errChan = make(chan error)
 	)
 
 	logger.Info("starting subsystems")
@@ -48,15 +55,31 @@ func run(logger *logrus.Logger, cfg *config.Config) {
 	}
 
 	logger.Info("starting webserver")
-	ws := webserver.NewWebServer(services, cfg.Webserver, errChan)
+	ws, err := webserver.NewWebServer(services, cfg.Webserver, errChan)
+	if err != nil {
+		services.Stop()
+		logger.WithError(err).Fatal("webserver initialization error")
+	}
 
-	signal.Notify(stopChan)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
 
-	select {
-	case <-stopChan:
-		logger.Info("interruption signal has been received")
-	case err := <-errChan:
-		logger.WithError(err).Error("fatal error")
+loop:
+	for {
+		select {
+		case sig := <-sigChan:
+			if sig == syscall.SIGHUP {
+				logger.Info("reload signal has been received")
+				if _, err := services.ReloadConfig(); err != nil {
+					logger.WithError(err).Error("failed to reload config")
+				}
+				continue
+			}
+			logger.Info("interruption signal has been received")
+			break loop
+		case err := <-errChan:
+			logger.WithError(err).Error("fatal error")
+			break loop
+		}
 	}
 
 	ws.Stop()
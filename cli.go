@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/vitalyisaev2/buildgraph/config"
+	"github.com/vitalyisaev2/buildgraph/vcs/gitlab"
+)
+
+// command is a buildgraph subcommand; the server is started
+// if no subcommand is provided
+type command struct {
+	name  string
+	usage string
+	run   func(args []string) int
+}
+
+var commands []*command
+
+func init() {
+	commands = []*command{
+		{"validate", "check config without starting the server", validateConfig},
+		{"print", "print effective config with includes and environment variables resolved", printConfig},
+		{"schema", "print JSON Schema of config file", printSchema},
+		{"discover", "discover project relations from dependency manifests", discover},
+		{"replay", "send webhook requests exported from the request log to an instance", replay},
+		{"gitlab-token", "obtain Gitlab personal access token with password read from stdin", gitlabToken},
+		{"help", "print this message", help},
+	}
+}
+
+func findCommand(name string) *command {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd
+		}
+	}
+	return nil
+}
+
+func help(args []string) int {
+	fmt.Fprintf(os.Stderr, "Usage:\n  buildgraph -c <config>\t\tstart the server\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  buildgraph %s [flags]\t%s\n", cmd.name, cmd.usage)
+	}
+	return 0
+}
+
+// loadConfig parses the common '-c' flag and reads config
+func loadConfig(name string, args []string) (*config.Config, error) {
+	var path string
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	flags.StringVar(&path, "c", "", "path to config")
+	flags.Parse(args)
+	return config.NewConfig(path)
+}
+
+// validateConfig checks config without starting the server or connecting to the storage
+func validateConfig(args []string) int {
+	cfg, err := loadConfig("validate", args)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	fmt.Printf("%s: config is valid\n", cfg.Path())
+	return 0
+}
+
+// printConfig dumps effective config with secrets redacted
+func printConfig(args []string) int {
+	cfg, err := loadConfig("print", args)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	// included files have already been merged
+	if cfg.Projects != nil {
+		projects := cfg.Projects.Redacted()
+		projects.Include = nil
+		cfg.Projects = projects
+	}
+
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	os.Stdout.Write(data)
+	return 0
+}
+
+func printSchema(args []string) int {
+	data, err := config.JSONSchema()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	fmt.Println(string(data))
+	return 0
+}
+
+// gitlabToken obtains personal access token once, so that
+// the server doesn't need Gitlab user credentials
+func gitlabToken(args []string) int {
+	var cfg config.GitlabConfig
+	var caFile, user string
+	var lifetime time.Duration
+
+	flags := flag.NewFlagSet("gitlab-token", flag.ExitOnError)
+	flags.StringVar(&cfg.Endpoint, "endpoint", "", "Gitlab URL, e.g. https://gitlab.example.com")
+	flags.StringVar(&caFile, "ca-file", "", "CA certificates of Gitlab server, if not trusted by system")
+	flags.StringVar(&user, "user", "", "Gitlab user login")
+	flags.DurationVar(&lifetime, "lifetime", 365*24*time.Hour, "token lifetime")
+	flags.Parse(args)
+	if cfg.Endpoint == "" || user == "" {
+		flags.Usage()
+		return 1
+	}
+	if caFile != "" {
+		cfg.TLS = &config.TLSClientConfig{CAFile: caFile}
+	}
+
+	password, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && password == "" {
+		fmt.Fprintln(os.Stderr, "failed to read password:", err)
+		return 1
+	}
+
+	token, err := gitlab.NewToken(&cfg, user, strings.TrimRight(password, "\r\n"), lifetime)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	fmt.Println(token)
+	return 0
+}
@@ -0,0 +1,15 @@
+package common
+
+import "context"
+
+// HealthChecker is implemented by services able to tell
+// whether they are ready to serve requests
+type HealthChecker interface {
+	// CheckHealth returns error describing why service is not ready
+	CheckHealth(ctx context.Context) error
+}
+
+// HealthCheckerFunc adapts ordinary function to HealthChecker
+type HealthCheckerFunc func(ctx context.Context) error
+
+func (f HealthCheckerFunc) CheckHealth(ctx context.Context) error { return f(ctx) }
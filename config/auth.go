@@ -0,0 +1,115 @@
+package config
+
+import "fmt"
+
+// Roles of API clients; every role includes permissions of the previous one
+const (
+	RoleViewer   = "viewer"   // reads projects, graph, events and the event stream
+	RoleOperator = "operator" // changes projects and relations
+	RoleAdmin    = "admin"    // reloads config and reads audit trail
+)
+
+// AllNamespaces is used in role grants to cover every namespace
+const AllNamespaces = "*"
+
+// defaultSubjectClaim identifies JWT subject if other claim is not configured
+const defaultSubjectClaim = "sub"
+
+// AuthConfig describes authentication of API clients; the API is open
+// when this section is omitted. Webhooks are always authenticated
+// with VCS specific settings (see VCSConfig)
+type AuthConfig struct {
+	APIKeys []*APIKeyConfig `yaml:"api_keys,omitempty"` // static keys of services and bots
+	JWT     *JWTConfig      `yaml:"jwt,omitempty"`      // bearer tokens issued by identity provider
+}
+
+// APIKeyConfig describes client sending static key in X-API-Key header
+// or as a bearer token
+type APIKeyConfig struct {
+	Name  string       `yaml:"name,omitempty"` // client name used in audit trail
+	Key   Secret       `yaml:"key,omitempty"`
+	Roles []*RoleGrant `yaml:"roles,omitempty"`
+}
+
+// JWTConfig describes validation of bearer tokens; tokens are signed
+// either with HMAC secret or with keys from JWKS file
+type JWTConfig struct {
+	HMACSecret   Secret                  `yaml:"hmac_secret,omitempty"`   // HS256, HS384 and HS512
+	JWKSFile     string                  `yaml:"jwks_file,omitempty"`     // RSA, ECDSA and Ed25519 public keys
+	Issuer       string                  `yaml:"issuer,omitempty"`        // expected 'iss' claim (not checked if omitted)
+	Audience     string                  `yaml:"audience,omitempty"`      // expected 'aud' claim (not checked if omitted)
+	SubjectClaim string                  `yaml:"subject_claim,omitempty"` // claim identifying client ('sub' by default)
+	Subjects     map[string][]*RoleGrant `yaml:"subjects,omitempty"`      // roles of clients keyed by subject
+}
+
+// RoleGrant gives role within the listed namespaces ('*' means every namespace)
+type RoleGrant struct {
+	Role       string   `yaml:"role,omitempty"`
+	Namespaces []string `yaml:"namespaces,omitempty"`
+}
+
+func (c *AuthConfig) validate() error {
+	if len(c.APIKeys) == 0 && c.JWT == nil {
+		return fmt.Errorf("AuthConfig requires either api_keys or jwt")
+	}
+
+	names := make(map[string]bool, len(c.APIKeys))
+	keys := make(map[Secret]bool, len(c.APIKeys))
+	for _, k := range c.APIKeys {
+		if k.Name == "" || k.Key == "" {
+			return fmt.Errorf("AuthConfig.APIKeys: both name and key are required")
+		}
+		if names[k.Name] {
+			return fmt.Errorf("AuthConfig.APIKeys: duplicate name '%s'", k.Name)
+		}
+		if keys[k.Key] {
+			return fmt.Errorf("AuthConfig.APIKeys: key of '%s' is not unique", k.Name)
+		}
+		names[k.Name], keys[k.Key] = true, true
+		if err := validateGrants(k.Roles); err != nil {
+			return fmt.Errorf("AuthConfig.APIKeys: client '%s': %v", k.Name, err)
+		}
+	}
+
+	if c.JWT != nil {
+		if err := c.JWT.validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *JWTConfig) validate() error {
+	if c.HMACSecret == "" && c.JWKSFile == "" {
+		return fmt.Errorf("JWTConfig requires either hmac_secret or jwks_file")
+	}
+	if err := checkFilesExist(c.JWKSFile); err != nil {
+		return err
+	}
+	if c.SubjectClaim == "" {
+		c.SubjectClaim = defaultSubjectClaim
+	}
+	for subject, grants := range c.Subjects {
+		if err := validateGrants(grants); err != nil {
+			return fmt.Errorf("JWTConfig.Subjects: subject '%s': %v", subject, err)
+		}
+	}
+	return nil
+}
+
+func validateGrants(grants []*RoleGrant) error {
+	if len(grants) == 0 {
+		return fmt.Errorf("no roles granted")
+	}
+	for _, g := range grants {
+		switch g.Role {
+		case RoleViewer, RoleOperator, RoleAdmin:
+		default:
+			return fmt.Errorf("unknown role '%s'", g.Role)
+		}
+		if len(g.Namespaces) == 0 {
+			return fmt.Errorf("no namespaces for role '%s' (use '%s' for all of them)", g.Role, AllNamespaces)
+		}
+	}
+	return nil
+}
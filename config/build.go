@@ -0,0 +1,179 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+)
+
+const (
+	branchRefPrefix = "refs/heads/"
+
+	// path pattern suffix matching everything within directory
+	recursiveSuffix = "/**"
+)
+
+// BuildSpec describes what should be done when the project needs rebuilding;
+// either Command or Trigger must be set
+type BuildSpec struct {
+	Command  string            `yaml:"command,omitempty" json:"command,omitempty"`   // shell command executed within project checkout
+	Trigger  *TriggerSpec      `yaml:"trigger,omitempty" json:"trigger,omitempty"`   // CI pipeline triggered instead of command
+	WorkDir  string            `yaml:"workdir,omitempty" json:"workdir,omitempty"`   // command working directory relative to project checkout
+	Env      map[string]string `yaml:"env,omitempty" json:"env,omitempty"`           // extra environment variables of command or pipeline
+	Timeout  time.Duration     `yaml:"timeout,omitempty" json:"timeout,omitempty"`   // build time limit (no limit if omitted)
+	Branches []string          `yaml:"branches,omitempty" json:"branches,omitempty"` // patterns of branches that cause rebuild (any branch if omitted)
+	Paths    *PathFilter       `yaml:"paths,omitempty" json:"paths,omitempty"`       // changed files that cause rebuild (any file if omitted)
+	Retry    *RetryPolicy      `yaml:"retry,omitempty" json:"retry,omitempty"`       // failed build retry settings (no retries if omitted)
+}
+
+// TriggerSpec describes CI pipeline that should be triggered
+type TriggerSpec struct {
+	Project string `yaml:"project,omitempty" json:"project,omitempty"` // project path (namespace/name), the project itself if omitted
+	Ref     string `yaml:"ref,omitempty" json:"ref,omitempty"`         // branch or tag to run pipeline for, the pushed one if omitted
+}
+
+// PathFilter selects changed files that are relevant for the build;
+// patterns follow path.Match syntax, and 'dir/**' matches everything within 'dir'
+type PathFilter struct {
+	Include []string `yaml:"include,omitempty" json:"include,omitempty"` // any file is included if omitted
+	Exclude []string `yaml:"exclude,omitempty" json:"exclude,omitempty"`
+}
+
+// RetryPolicy describes how failed builds are retried
+type RetryPolicy struct {
+	Attempts int           `yaml:"attempts,omitempty" json:"attempts,omitempty"` // max number of retries
+	Backoff  time.Duration `yaml:"backoff,omitempty" json:"backoff,omitempty"`   // delay before the first retry, doubled on every next one
+}
+
+// redacted returns copy of spec with values of environment variables hidden
+func (b *BuildSpec) redacted() *BuildSpec {
+	if b == nil || len(b.Env) == 0 {
+		return b
+	}
+	result := *b
+	result.Env = make(map[string]string, len(b.Env))
+	for key, value := range b.Env {
+		result.Env[key] = Secret(value).String()
+	}
+	return &result
+}
+
+// restoreRedacted replaces redacted values of environment variables
+// with the values prev has for the same variables
+func (b *BuildSpec) restoreRedacted(prev *BuildSpec) {
+	if b == nil || prev == nil {
+		return
+	}
+	for key, value := range b.Env {
+		if original, exists := prev.Env[key]; exists && value == redacted {
+			b.Env[key] = original
+		}
+	}
+}
+
+func (b *BuildSpec) validate() error {
+	if (b.Command == "") == (b.Trigger == nil) {
+		return fmt.Errorf("either command or trigger must be specified")
+	}
+	if b.Trigger != nil && b.Trigger.Project != "" && !strings.Contains(b.Trigger.Project, "/") {
+		return fmt.Errorf("wrong trigger project value: %s", b.Trigger.Project)
+	}
+	if b.Trigger != nil && b.WorkDir != "" {
+		return fmt.Errorf("workdir makes no sense for trigger")
+	}
+	if workDir := path.Clean(b.WorkDir); path.IsAbs(workDir) || workDir == ".." || strings.HasPrefix(workDir, "../") {
+		return fmt.Errorf("workdir must be within project checkout: %s", b.WorkDir)
+	}
+	for key := range b.Env {
+		if key == "" || strings.ContainsAny(key, "= ") {
+			return fmt.Errorf("wrong environment variable name: '%s'", key)
+		}
+	}
+	if b.Timeout < 0 {
+		return fmt.Errorf("wrong timeout value: %v", b.Timeout)
+	}
+	for _, pattern := range b.Branches {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("wrong branch pattern '%s': %v", pattern, err)
+		}
+	}
+	if b.Paths != nil {
+		for _, pattern := range append(b.Paths.Include, b.Paths.Exclude...) {
+			if _, err := matchPath(pattern, ""); err != nil {
+				return fmt.Errorf("wrong path pattern '%s': %v", pattern, err)
+			}
+		}
+	}
+	if b.Retry != nil && (b.Retry.Attempts < 0 || b.Retry.Backoff < 0) {
+		return fmt.Errorf("wrong retry policy: %+v", *b.Retry)
+	}
+	return nil
+}
+
+// MatchBranch reports whether push to the given ref should cause rebuild
+func (b *BuildSpec) MatchBranch(ref string) bool {
+	if !strings.HasPrefix(ref, branchRefPrefix) {
+		return false
+	}
+	if len(b.Branches) == 0 {
+		return true
+	}
+
+	branch := strings.TrimPrefix(ref, branchRefPrefix)
+	for _, pattern := range b.Branches {
+		if matched, _ := path.Match(pattern, branch); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchPaths reports whether any of the changed files is relevant for the build
+func (b *BuildSpec) MatchPaths(paths []string) bool {
+	if b.Paths == nil {
+		return true
+	}
+	for _, p := range paths {
+		if b.Paths.match(p) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *PathFilter) match(p string) bool {
+	included := len(f.Include) == 0
+	for _, pattern := range f.Include {
+		if matched, _ := matchPath(pattern, p); matched {
+			included = true
+			break
+		}
+	}
+	if !included {
+		return false
+	}
+	for _, pattern := range f.Exclude {
+		if matched, _ := matchPath(pattern, p); matched {
+			return false
+		}
+	}
+	return true
+}
+
+// matchPath extends path.Match with support of recursive 'dir/**' patterns
+func matchPath(pattern, p string) (bool, error) {
+	if strings.HasSuffix(pattern, recursiveSuffix) {
+		dir := strings.TrimSuffix(pattern, recursiveSuffix)
+		if _, err := path.Match(dir, ""); err != nil {
+			return false, err
+		}
+		for d := path.Dir(p); d != "." && d != "/"; d = path.Dir(d) {
+			if matched, _ := path.Match(dir, d); matched {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+	return path.Match(pattern, p)
+}
@@ -2,16 +2,21 @@ package config
 
 import (
 	"fmt"
-	"io/ioutil"
-
-	yaml "gopkg.in/yaml.v2"
+	"path/filepath"
+	"reflect"
 )
 
 // Config top-level structure
 type Config struct {
-	Storage   *StorageConfig   `yaml:"storage"`
-	Webserver *WebserverConfig `yaml:"webserver"`
-	Projects  *ProjectsConfig  `yaml:"projects"`
+	Storage   *StorageConfig   `yaml:"storage,omitempty"`
+	Webserver *WebserverConfig `yaml:"webserver,omitempty"`
+	Projects  *ProjectsConfig  `yaml:"projects,omitempty"`
+	VCS       *VCSConfig       `yaml:"vcs,omitempty"`
+	Discovery *DiscoveryConfig `yaml:"discovery,omitempty"`
+	Inbox     *InboxConfig     `yaml:"inbox,omitempty"` // defaults are used if omitted
+
+	// path to the file config was read from
+	path string
 }
 
 func (c *Config) validate() error {
@@ -30,30 +35,92 @@ func (c *Config) validate() error {
 		return err
 	}
 
-	if c.Projects == nil {
-		return fmt.Errorf("Missing required section 'projects'")
+	// projects may be managed through the API only
+	if c.Projects != nil {
+		if err := c.Projects.validate(); err != nil {
+			return err
+		}
+	}
+
+	if c.VCS != nil {
+		if err := c.VCS.validate(); err != nil {
+			return err
+		}
+	}
+
+	if c.Discovery != nil {
+		if err := c.Discovery.validate(); err != nil {
+			return err
+		}
 	}
-	if err := c.Projects.validate(); err != nil {
+
+	if c.Inbox == nil {
+		c.Inbox = &InboxConfig{}
+	}
+	if err := c.Inbox.validate(); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func NewConfig(path string) (*Config, error) {
-	data, err := ioutil.ReadFile(path)
-	if err != nil {
-		return nil, err
+// Path returns the path to the file config was read from
+func (c *Config) Path() string { return c.path }
+
+// CheckReloadable ensures that the next version of config doesn't touch
+// the settings that cannot be changed without restart
+func (c *Config) CheckReloadable(next *Config) error {
+	if !reflect.DeepEqual(c.Storage, next.Storage) {
+		return fmt.Errorf("section 'storage' cannot be changed without restart")
 	}
+	if c.Webserver.Endpoint != next.Webserver.Endpoint {
+		return fmt.Errorf(
+			"setting 'webserver.endpoint' cannot be changed without restart: %s -> %s",
+			c.Webserver.Endpoint, next.Webserver.Endpoint,
+		)
+	}
+	// certificate files are re-read on change, but the files themselves cannot be replaced
+	if !reflect.DeepEqual(c.Webserver.TLS, next.Webserver.TLS) {
+		return fmt.Errorf("setting 'webserver.tls' cannot be changed without restart")
+	}
+	if !reflect.DeepEqual(c.Webserver.Timeouts, next.Webserver.Timeouts) {
+		return fmt.Errorf("setting 'webserver.timeouts' cannot be changed without restart")
+	}
+	if !reflect.DeepEqual(c.Webserver.Limits, next.Webserver.Limits) {
+		return fmt.Errorf("setting 'webserver.limits' cannot be changed without restart")
+	}
+	if !reflect.DeepEqual(c.Inbox, next.Inbox) {
+		return fmt.Errorf("section 'inbox' cannot be changed without restart")
+	}
+	// webhook settings are the only VCS settings that may be changed on the fly
+	if !reflect.DeepEqual(c.VCS.withoutWebhooks(), next.VCS.withoutWebhooks()) {
+		return fmt.Errorf("section 'vcs' (except webhook settings) cannot be changed without restart")
+	}
+	return nil
+}
 
+// NewConfig reads config from file; environment variables are substituted
+// in place of ${VAR} placeholders, and secrets may be read from files
+// referenced by keys with '_file' suffix (e.g. 'password_file');
+// project descriptions and relations may be split across several files
+// (see ProjectsConfig.Include)
+func NewConfig(path string) (*Config, error) {
 	var cfg Config
-	if err := yaml.Unmarshal(data, &cfg); err != nil {
+	if err := readYAML(path, &cfg); err != nil {
 		return nil, err
 	}
 
+	if cfg.Projects != nil {
+		cfg.Projects.SetOrigin(filepath.Clean(path))
+		if err := cfg.Projects.loadIncludes(filepath.Dir(path)); err != nil {
+			return nil, err
+		}
+	}
+
 	if err := cfg.validate(); err != nil {
 		return nil, err
 	}
 
+	cfg.path = path
 	return &cfg, nil
 }
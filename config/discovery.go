@@ -0,0 +1,17 @@
+package config
+
+import "fmt"
+
+// DiscoveryConfig describes settings of automatic dependency discovery
+type DiscoveryConfig struct {
+	// directory containing local checkouts of the projects;
+	// every project is expected at <root>/<namespace>/<name>
+	Root string `yaml:"root,omitempty"`
+}
+
+func (c *DiscoveryConfig) validate() error {
+	if c.Root == "" {
+		return fmt.Errorf("Wrong DiscoveryConfig.Root value: %s", c.Root)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// jsonDuration is written to JSON the same way as to YAML ("10m0s") instead
+// of nanoseconds; numbers are still read, since they used to be written
+type jsonDuration time.Duration
+
+func (d jsonDuration) MarshalJSON() ([]byte, error) { return json.Marshal(time.Duration(d).String()) }
+
+func (d *jsonDuration) UnmarshalJSON(data []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	switch v := value.(type) {
+	case float64:
+		*d = jsonDuration(v)
+	case string:
+		parsed, err := time.ParseDuration(v)
+		if err != nil {
+			return err
+		}
+		*d = jsonDuration(parsed)
+	default:
+		return fmt.Errorf("wrong duration value: %s", data)
+	}
+	return nil
+}
+
+type buildSpecJSON BuildSpec
+
+// MarshalJSON writes Timeout as duration string
+func (b BuildSpec) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&struct {
+		*buildSpecJSON
+		Timeout jsonDuration `json:"timeout,omitempty"`
+	}{(*buildSpecJSON)(&b), jsonDuration(b.Timeout)})
+}
+
+// UnmarshalJSON reads Timeout as either duration string or nanoseconds
+func (b *BuildSpec) UnmarshalJSON(data []byte) error {
+	v := struct {
+		*buildSpecJSON
+		Timeout jsonDuration `json:"timeout,omitempty"`
+	}{buildSpecJSON: (*buildSpecJSON)(b)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	b.Timeout = time.Duration(v.Timeout)
+	return nil
+}
+
+type retryPolicyJSON RetryPolicy
+
+// MarshalJSON writes Backoff as duration string
+func (r RetryPolicy) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&struct {
+		*retryPolicyJSON
+		Backoff jsonDuration `json:"backoff,omitempty"`
+	}{(*retryPolicyJSON)(&r), jsonDuration(r.Backoff)})
+}
+
+// UnmarshalJSON reads Backoff as either duration string or nanoseconds
+func (r *RetryPolicy) UnmarshalJSON(data []byte) error {
+	v := struct {
+		*retryPolicyJSON
+		Backoff jsonDuration `json:"backoff,omitempty"`
+	}{retryPolicyJSON: (*retryPolicyJSON)(r)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	r.Backoff = time.Duration(v.Backoff)
+	return nil
+}
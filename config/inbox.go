@@ -0,0 +1,75 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// Default settings of webhook deliveries processing
+const (
+	defaultInboxWorkers     = 4
+	defaultInboxMaxAttempts = 8
+	defaultInboxBackoff     = 5 * time.Second
+	defaultInboxMaxBackoff  = 10 * time.Minute
+	defaultInboxRetention   = 30 * 24 * time.Hour
+)
+
+// InboxConfig describes processing of webhook deliveries: deliveries
+// are saved into the database and acknowledged at once, then they are
+// processed by the pool of workers; failed deliveries are retried with
+// exponential backoff until they run out of attempts; raw webhook requests,
+// including rejected ones, are logged so that they could be replayed
+type InboxConfig struct {
+	Workers     int           `yaml:"workers,omitempty"`      // number of deliveries processed at once (4 by default)
+	MaxAttempts int           `yaml:"max_attempts,omitempty"` // attempts before delivery is dead (8 by default)
+	Backoff     time.Duration `yaml:"backoff,omitempty"`      // delay before the first retry, doubled every time (5s by default)
+	MaxBackoff  time.Duration `yaml:"max_backoff,omitempty"`  // upper bound of the delay (10m by default)
+	Retention   time.Duration `yaml:"retention,omitempty"`    // time webhook requests and payloads of done deliveries are kept (720h by default)
+}
+
+func (c *InboxConfig) validate() error {
+	if c.Workers < 0 {
+		return fmt.Errorf("Wrong InboxConfig.Workers value: %d", c.Workers)
+	}
+	if c.Workers == 0 {
+		c.Workers = defaultInboxWorkers
+	}
+	if c.MaxAttempts < 0 {
+		return fmt.Errorf("Wrong InboxConfig.MaxAttempts value: %d", c.MaxAttempts)
+	}
+	if c.MaxAttempts == 0 {
+		c.MaxAttempts = defaultInboxMaxAttempts
+	}
+	if c.Backoff < 0 {
+		return fmt.Errorf("Wrong InboxConfig.Backoff value: %v", c.Backoff)
+	}
+	if c.Backoff == 0 {
+		c.Backoff = defaultInboxBackoff
+	}
+	if c.MaxBackoff == 0 {
+		c.MaxBackoff = defaultInboxMaxBackoff
+	}
+	if c.MaxBackoff < c.Backoff {
+		return fmt.Errorf("Wrong InboxConfig.MaxBackoff value: %v is less than backoff", c.MaxBackoff)
+	}
+	if c.Retention < 0 {
+		return fmt.Errorf("Wrong InboxConfig.Retention value: %v", c.Retention)
+	}
+	if c.Retention == 0 {
+		c.Retention = defaultInboxRetention
+	}
+	return nil
+}
+
+// Delay returns the time to wait before the next attempt
+// to process delivery that has failed the given number of times
+func (c *InboxConfig) Delay(failures int) time.Duration {
+	delay := c.Backoff
+	for i := 1; i < failures && delay < c.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > c.MaxBackoff {
+		delay = c.MaxBackoff
+	}
+	return delay
+}
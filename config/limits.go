@@ -0,0 +1,130 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// Default settings of incoming connections
+const (
+	defaultReadHeaderTimeout = 10 * time.Second
+	defaultReadTimeout       = 30 * time.Second
+	defaultWriteTimeout      = 30 * time.Second
+	defaultIdleTimeout       = 2 * time.Minute
+
+	defaultMaxBodySize        = 1 << 20  // 1 MiB is enough for any API request
+	defaultMaxWebhookBodySize = 25 << 20 // Gitlab doesn't send payloads larger than 25 MiB
+)
+
+// Keys of rate limiters
+const (
+	RateLimitByIP       = "ip"       // client address
+	RateLimitByProvider = "provider" // VCS provider of authenticated deliveries, client address before that (webhook routes only)
+)
+
+// Names of route groups limits are applied to
+const (
+	RouteGroupAPI     = "api"
+	RouteGroupWebhook = "webhook"
+	RouteGroupAdmin   = "admin"
+	RouteGroupStream  = "stream"
+	RouteGroupProbe   = "probe"
+)
+
+var routeGroups = map[string]bool{
+	RouteGroupAPI:     true,
+	RouteGroupWebhook: true,
+	RouteGroupAdmin:   true,
+	RouteGroupStream:  true,
+	RouteGroupProbe:   true,
+}
+
+// TimeoutsConfig describes timeouts of incoming connections; read and write
+// timeouts are not applied to event streams
+type TimeoutsConfig struct {
+	ReadHeader time.Duration `yaml:"read_header,omitempty"` // reading request headers (10s by default)
+	Read       time.Duration `yaml:"read,omitempty"`        // reading the whole request (30s by default)
+	Write      time.Duration `yaml:"write,omitempty"`       // writing response (30s by default)
+	Idle       time.Duration `yaml:"idle,omitempty"`        // waiting for the next request on keep-alive connection (2m by default)
+}
+
+func (c *TimeoutsConfig) validate() error {
+	defaults := []struct {
+		value        *time.Duration
+		defaultValue time.Duration
+	}{
+		{&c.ReadHeader, defaultReadHeaderTimeout},
+		{&c.Read, defaultReadTimeout},
+		{&c.Write, defaultWriteTimeout},
+		{&c.Idle, defaultIdleTimeout},
+	}
+	for _, d := range defaults {
+		if *d.value < 0 {
+			return fmt.Errorf("Wrong TimeoutsConfig value: %v", *d.value)
+		}
+		if *d.value == 0 {
+			*d.value = d.defaultValue
+		}
+	}
+	return nil
+}
+
+// LimitsConfig protects server from misbehaving clients; settings
+// are keyed by route group (api, webhook, admin, stream, probe)
+type LimitsConfig struct {
+	// maximal request body size in bytes (25 MiB for webhooks and 1 MiB for the rest by default)
+	MaxBodySize map[string]int64 `yaml:"max_body_size,omitempty"`
+	// token bucket rate limiters (requests are not limited by default)
+	RateLimits map[string]*RateLimitConfig `yaml:"rate_limits,omitempty"`
+}
+
+// RateLimitConfig describes token bucket: it holds up to Burst tokens,
+// every request takes one, and tokens are added at Rate per second
+type RateLimitConfig struct {
+	Rate  float64 `yaml:"rate,omitempty"`
+	Burst int     `yaml:"burst,omitempty"`
+	Key   string  `yaml:"key,omitempty"` // 'ip' (default) or 'provider'
+}
+
+func (c *LimitsConfig) validate() error {
+	if c.MaxBodySize == nil {
+		c.MaxBodySize = make(map[string]int64)
+	}
+	for group, size := range c.MaxBodySize {
+		if !routeGroups[group] {
+			return fmt.Errorf("LimitsConfig.MaxBodySize: unknown route group '%s'", group)
+		}
+		if size <= 0 {
+			return fmt.Errorf("LimitsConfig.MaxBodySize: wrong value for '%s': %d", group, size)
+		}
+	}
+	if _, exists := c.MaxBodySize[RouteGroupWebhook]; !exists {
+		c.MaxBodySize[RouteGroupWebhook] = defaultMaxWebhookBodySize
+	}
+	for group := range routeGroups {
+		if _, exists := c.MaxBodySize[group]; !exists {
+			c.MaxBodySize[group] = defaultMaxBodySize
+		}
+	}
+
+	for group, limit := range c.RateLimits {
+		if !routeGroups[group] {
+			return fmt.Errorf("LimitsConfig.RateLimits: unknown route group '%s'", group)
+		}
+		if limit.Rate <= 0 || limit.Burst <= 0 {
+			return fmt.Errorf("LimitsConfig.RateLimits: rate and burst of '%s' must be positive", group)
+		}
+		switch limit.Key {
+		case "":
+			limit.Key = RateLimitByIP
+		case RateLimitByIP:
+		case RateLimitByProvider:
+			if group != RouteGroupWebhook {
+				return fmt.Errorf("LimitsConfig.RateLimits: '%s' can't be limited by provider", group)
+			}
+		default:
+			return fmt.Errorf("LimitsConfig.RateLimits: wrong key of '%s': %s", group, limit.Key)
+		}
+	}
+	return nil
+}
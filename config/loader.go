@@ -0,0 +1,238 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	// key of secret with this suffix is a reference to the file containing
+	// the value of the secret (e.g. password_file -> password)
+	fileReferenceSuffix = "_file"
+)
+
+var (
+	// matches both escaped dollar sign ($$) and ${VAR} / ${VAR:-default} placeholders
+	envPlaceholder = regexp.MustCompile(`\$\$|\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+)
+
+// readYAML reads YAML file and decodes it into out; before decoding
+// the document is processed in the following way:
+//  1. ${VAR} and ${VAR:-default} placeholders are replaced with the values of environment variables;
+//     substituted values of numeric and boolean options are typed as if they were written in the file;
+//  2. values of secrets referred by keys like 'password_file' are read from the files
+//     and assigned to 'password' keys (options like 'cert_file' are kept as is).
+func readYAML(path string, out interface{}) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var tree yaml.MapSlice
+	if err := yaml.Unmarshal(data, &tree); err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+
+	r := &resolver{dir: filepath.Dir(path)}
+	resolved, err := r.resolve(tree, "", reflect.TypeOf(out))
+	if err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+
+	if data, err = yaml.Marshal(resolved); err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+	if err := yaml.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+
+	return nil
+}
+
+// resolver walks through the parsed YAML document along with the type
+// it is decoded into, expands environment variables and reads secrets from files
+type resolver struct {
+	dir string // relative paths are resolved against this directory
+}
+
+// resolve processes node that is decoded into the value of type t
+// (nil if the type is unknown, e.g. for the keys that are ignored)
+func (r *resolver) resolve(node interface{}, location string, t reflect.Type) (interface{}, error) {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch value := node.(type) {
+	case string:
+		expanded, substituted, err := r.expand(value, location)
+		if err != nil || !substituted || t == nil || t.Kind() == reflect.String || t.Kind() == reflect.Interface {
+			return expanded, err
+		}
+		return retype(expanded), nil
+	case yaml.MapSlice:
+		return r.resolveMapping(value, location, t)
+	case []interface{}:
+		var elem reflect.Type
+		if t != nil && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+			elem = t.Elem()
+		}
+		result := make([]interface{}, 0, len(value))
+		for i, item := range value {
+			resolved, err := r.resolve(item, fmt.Sprintf("%s[%d]", location, i), elem)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, resolved)
+		}
+		return result, nil
+	default:
+		return node, nil
+	}
+}
+
+func (r *resolver) resolveMapping(mapping yaml.MapSlice, location string, t reflect.Type) (yaml.MapSlice, error) {
+	result := make(yaml.MapSlice, 0, len(mapping))
+	keys := make(map[string]bool, len(mapping))
+	for _, item := range mapping {
+		keys[fmt.Sprint(item.Key)] = true
+	}
+	fields := yamlFields(t)
+
+	for _, item := range mapping {
+		key := fmt.Sprint(item.Key)
+		itemLocation := strings.TrimPrefix(location+"."+key, ".")
+
+		target, reference := fileReference(fields, key)
+		var valueType reflect.Type
+		switch {
+		case reference:
+			valueType = reflect.TypeOf("")
+		case fields != nil:
+			valueType = fields[key].Type
+		case t != nil && t.Kind() == reflect.Map:
+			valueType = t.Elem()
+		}
+
+		value, err := r.resolve(item.Value, itemLocation, valueType)
+		if err != nil {
+			return nil, err
+		}
+
+		if !reference {
+			result = append(result, yaml.MapItem{Key: item.Key, Value: value})
+			continue
+		}
+
+		if keys[target] {
+			return nil, fmt.Errorf("'%s' and '%s' are mutually exclusive", itemLocation, target)
+		}
+		path, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("'%s' must be a path to file", itemLocation)
+		}
+		if value, err = r.readFile(path); err != nil {
+			return nil, fmt.Errorf("'%s': %v", itemLocation, err)
+		}
+		result = append(result, yaml.MapItem{Key: target, Value: value})
+	}
+
+	return result, nil
+}
+
+// expand replaces placeholders with the values of environment variables;
+// substituted is true if there was at least one variable
+func (r *resolver) expand(value, location string) (result string, substituted bool, err error) {
+	result = envPlaceholder.ReplaceAllStringFunc(value, func(placeholder string) string {
+		if placeholder == "$$" {
+			return "$"
+		}
+
+		substituted = true
+		match := envPlaceholder.FindStringSubmatch(placeholder)
+		if v, ok := os.LookupEnv(match[1]); ok {
+			return v
+		}
+		if match[2] != "" {
+			return match[3]
+		}
+		if err == nil {
+			err = fmt.Errorf("'%s': environment variable %s is not set", location, match[1])
+		}
+		return ""
+	})
+	return result, substituted, err
+}
+
+// retype resolves the type of substituted value as if the value was written
+// in the file, so that e.g. 'workers: ${WORKERS}' is decoded into integer
+func retype(value string) interface{} {
+	var typed interface{}
+	if err := yaml.Unmarshal([]byte(value), &typed); err != nil {
+		return value
+	}
+	switch typed.(type) {
+	case int, int64, uint64, float64, bool:
+		return typed
+	default:
+		return value
+	}
+}
+
+// yamlFields returns fields of struct keyed by their names in YAML document;
+// nil is returned if t is not a struct
+func yamlFields(t reflect.Type) map[string]reflect.StructField {
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	result := make(map[string]reflect.StructField, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue // unexported
+		}
+		name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		result[name] = field
+	}
+	return result
+}
+
+// fileReference returns the name of secret which value is read from the file
+// referred by the key; only secrets may be read from files, so options like
+// 'cert_file' that are paths themselves are not references
+func fileReference(fields map[string]reflect.StructField, key string) (string, bool) {
+	if !strings.HasSuffix(key, fileReferenceSuffix) {
+		return "", false
+	}
+	if _, exists := fields[key]; exists {
+		return "", false
+	}
+	target := strings.TrimSuffix(key, fileReferenceSuffix)
+	field, exists := fields[target]
+	return target, exists && field.Type == secretType
+}
+
+// readFile returns the contents of file without trailing newlines
+func (r *resolver) readFile(path string) (string, error) {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(r.dir, path)
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
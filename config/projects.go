@@ -2,46 +2,148 @@ package config
 
 import (
 	"fmt"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
 
 	"github.com/vitalyisaev2/buildgraph/graph"
 )
 
-// ProjectsConfig describes projects and their relations
+const (
+	// ImportSeed makes projects from config imported into the storage
+	// only if the storage has no projects yet
+	ImportSeed = "seed"
+	// ImportOverwrite makes projects from config replace the ones kept
+	// in the storage on every start and config reload
+	ImportOverwrite = "overwrite"
+)
+
+// ProjectsConfig describes projects and their relations; project registry
+// is kept in the storage, and this section is used to fill it
 type ProjectsConfig struct {
-	Descriptions []*Description      `yaml:"descriptions"`
-	Relations    map[string][]string `yaml:"relations"`
+	// glob patterns of files containing extra descriptions and relations;
+	// relative patterns are resolved against the directory of config file
+	Include      []string            `yaml:"include,omitempty" json:"-"`
+	Import       string              `yaml:"import,omitempty" json:"-"` // 'seed' (default) or 'overwrite'
+	Descriptions []*Description      `yaml:"descriptions,omitempty" json:"descriptions"`
+	Relations    map[string][]string `yaml:"relations,omitempty" json:"relations"`
+
+	// files relations were read from ("dependency -> dependent" -> path)
+	relationOrigins map[string]string
 }
 
 // Description contains basic information about the project
 type Description struct {
-	ID        string `yaml:"id"`        // project's unique identifier (used to describe project relations)
-	Namespace string `yaml:"namespace"` // namespace that project belongs to
-	Name      string `yaml:"name"`      // project's name
+	ID        string `yaml:"id,omitempty" json:"id"`               // project's unique identifier (used to describe project relations)
+	Namespace string `yaml:"namespace,omitempty" json:"namespace"` // namespace that project belongs to
+	Name      string `yaml:"name,omitempty" json:"name"`           // project's name
+
+	Build *BuildSpec `yaml:"build,omitempty" json:"build,omitempty"` // what to do when project needs rebuilding
+
+	// file description was read from
+	origin string
+}
+
+// Origin returns path to the file description was read from
+func (d *Description) Origin() string { return d.origin }
+
+// Redacted returns copy of description with values of build environment hidden
+func (d *Description) Redacted() *Description {
+	result := *d
+	result.Build = d.Build.redacted()
+	return &result
+}
+
+// RestoreRedacted puts values of build environment hidden by Redacted back
+// from the previous version of description, so that description read from
+// API could be saved without losing them
+func (d *Description) RestoreRedacted(prev *Description) {
+	d.Build.restoreRedacted(prev.Build)
+}
+
+// Validate checks description consistency
+func (d *Description) Validate() error {
+	if d.ID == "" || d.Name == "" || d.Namespace == "" {
+		return fmt.Errorf("invalid project description: %v (defined in %s)", d, d.origin)
+	}
+	if d.Build != nil {
+		if err := d.Build.validate(); err != nil {
+			return fmt.Errorf("invalid build spec of project %s (defined in %s): %v", d.ID, d.origin, err)
+		}
+	}
+	return nil
 }
 
 func (c *ProjectsConfig) validate() error {
+	switch c.Import {
+	case "":
+		c.Import = ImportSeed
+	case ImportSeed, ImportOverwrite:
+	default:
+		return fmt.Errorf("Wrong ProjectsConfig.Import value: %s", c.Import)
+	}
+
 	if len(c.Descriptions) == 0 {
 		return fmt.Errorf("empty project descriptions")
 	}
 
+	if len(c.Relations) == 0 {
+		return fmt.Errorf("empty project relations")
+	}
+
+	return c.Check()
+}
+
+// Check makes sure that descriptions are valid and unique,
+// and that relations link described projects without forming cycles
+func (c *ProjectsConfig) Check() error {
+	ids := make(map[string]*Description, len(c.Descriptions))
 	for _, d := range c.Descriptions {
-		if d.ID == "" || d.Name == "" || d.Namespace == "" {
-			return fmt.Errorf("invalid project description: %v", d)
+		if err := d.Validate(); err != nil {
+			return err
 		}
+		if prev, exists := ids[d.ID]; exists {
+			return fmt.Errorf(
+				"duplicate project ID '%s' (defined in %s and %s)",
+				d.ID, prev.origin, d.origin,
+			)
+		}
+		ids[d.ID] = d
 	}
 
-	if len(c.Relations) == 0 {
-		return fmt.Errorf("empty project relations")
+	// relations are stored along with references to both projects
+	parents := make([]string, 0, len(c.Relations))
+	for parent := range c.Relations {
+		parents = append(parents, parent)
+	}
+	sort.Strings(parents)
+	for _, parent := range parents {
+		for _, child := range c.Relations[parent] {
+			for _, id := range []string{parent, child} {
+				if _, exists := ids[id]; exists {
+					continue
+				}
+				link := formatLink(parent, child)
+				if origin, exists := c.relationOrigins[link]; exists {
+					return fmt.Errorf("relation '%s' refers to undescribed project '%s' (defined in %s)", link, id, origin)
+				}
+				return fmt.Errorf("relation '%s' refers to undescribed project '%s'", link, id)
+			}
+		}
 	}
 
-	g, err := graph.NewGraphFromAdjacencyMap(c.Relations)
+	g, err := c.Graph()
 	if err != nil {
 		return err
 	}
 
 	cyclic, cycle, err := g.Cyclic()
 	if cyclic {
-		return fmt.Errorf("project dependency graph contains cycle: %s", cycle.String())
+		return fmt.Errorf(
+			"project dependency graph contains cycle: %s(relations defined in %s)",
+			cycle.String(), strings.Join(c.cycleOrigins(cycle), ", "),
+		)
 	}
 	if err != nil {
 		return err
@@ -49,3 +151,218 @@ func (c *ProjectsConfig) validate() error {
 
 	return nil
 }
+
+// Graph builds project dependency graph; nodes of described projects
+// keep *Description as a value
+func (c *ProjectsConfig) Graph() (graph.Graph, error) {
+	g := graph.NewGraph()
+
+	for _, d := range c.Descriptions {
+		if _, err := g.CreateNode(d.ID, d); err != nil {
+			return nil, err
+		}
+	}
+
+	for parent, children := range c.Relations {
+		for _, name := range append([]string{parent}, children...) {
+			if n, _ := g.GetNode(name); n == nil {
+				if _, err := g.CreateNode(name, nil); err != nil {
+					return nil, err
+				}
+			}
+		}
+		for _, child := range children {
+			if err := g.Link(parent, child); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return g, nil
+}
+
+// Redacted returns copy of config with values of build environment hidden,
+// since they often hold credentials
+func (c *ProjectsConfig) Redacted() *ProjectsConfig {
+	result := *c
+	result.Descriptions = make([]*Description, 0, len(c.Descriptions))
+	for _, d := range c.Descriptions {
+		result.Descriptions = append(result.Descriptions, d.Redacted())
+	}
+	return &result
+}
+
+// SetOrigin marks descriptions and relations as read from the given source
+func (c *ProjectsConfig) SetOrigin(path string) {
+	for _, d := range c.Descriptions {
+		d.origin = path
+	}
+	c.relationOrigins = make(map[string]string)
+	for link := range c.links() {
+		c.relationOrigins[link] = path
+	}
+}
+
+// loadIncludes reads files matching include patterns and merges them into config
+func (c *ProjectsConfig) loadIncludes(dir string) error {
+	loaded := make(map[string]bool)
+
+	for _, pattern := range c.Include {
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(dir, pattern)
+		}
+
+		paths, err := filepath.Glob(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid include pattern '%s': %v", pattern, err)
+		}
+		if len(paths) == 0 {
+			return fmt.Errorf("include pattern '%s' matches no files", pattern)
+		}
+
+		for _, path := range paths {
+			if loaded[path] {
+				continue
+			}
+			loaded[path] = true
+
+			var fragment ProjectsConfig
+			if err := readYAML(path, &fragment); err != nil {
+				return err
+			}
+			if len(fragment.Include) != 0 {
+				return fmt.Errorf("%s: nested includes are not supported", path)
+			}
+			fragment.SetOrigin(path)
+			c.merge(&fragment)
+		}
+	}
+
+	return nil
+}
+
+// merge appends descriptions and relations of fragment
+func (c *ProjectsConfig) merge(fragment *ProjectsConfig) {
+	c.Descriptions = append(c.Descriptions, fragment.Descriptions...)
+
+	if c.Relations == nil {
+		c.Relations = make(map[string][]string)
+	}
+	if c.relationOrigins == nil {
+		c.relationOrigins = make(map[string]string)
+	}
+
+	existing := c.links()
+	for parent, children := range fragment.Relations {
+		for _, child := range children {
+			link := formatLink(parent, child)
+			if existing[link] {
+				continue
+			}
+			c.Relations[parent] = append(c.Relations[parent], child)
+			c.relationOrigins[link] = fragment.relationOrigins[link]
+		}
+	}
+}
+
+// cycleOrigins returns files containing relations that form the cycle
+func (c *ProjectsConfig) cycleOrigins(cycle graph.NodeList) []string {
+	var result []string
+	seen := make(map[string]bool)
+	for i := range cycle {
+		link := formatLink(cycle[i].Name(), cycle[(i+1)%len(cycle)].Name())
+		if origin, exists := c.relationOrigins[link]; exists && !seen[origin] {
+			seen[origin] = true
+			result = append(result, origin)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Diff returns the list of changes required to turn c into next
+func (c *ProjectsConfig) Diff(next *ProjectsConfig) *ProjectsDiff {
+	var diff ProjectsDiff
+
+	prevDescriptions := c.descriptionsByID()
+	nextDescriptions := next.descriptionsByID()
+	for id, d := range nextDescriptions {
+		prev, exists := prevDescriptions[id]
+		switch {
+		case !exists:
+			diff.Added = append(diff.Added, id)
+		case !prev.equal(d):
+			diff.Changed = append(diff.Changed, id)
+		}
+	}
+	for id := range prevDescriptions {
+		if _, exists := nextDescriptions[id]; !exists {
+			diff.Removed = append(diff.Removed, id)
+		}
+	}
+
+	prevLinks := c.links()
+	nextLinks := next.links()
+	for link := range nextLinks {
+		if !prevLinks[link] {
+			diff.LinksAdded = append(diff.LinksAdded, link)
+		}
+	}
+	for link := range prevLinks {
+		if !nextLinks[link] {
+			diff.LinksRemoved = append(diff.LinksRemoved, link)
+		}
+	}
+
+	diff.sort()
+	return &diff
+}
+
+// equal compares descriptions regardless of the files they were read from
+func (d *Description) equal(other *Description) bool {
+	a, b := *d, *other
+	a.origin, b.origin = "", ""
+	return reflect.DeepEqual(a, b)
+}
+
+func (c *ProjectsConfig) descriptionsByID() map[string]*Description {
+	result := make(map[string]*Description, len(c.Descriptions))
+	for _, d := range c.Descriptions {
+		result[d.ID] = d
+	}
+	return result
+}
+
+func (c *ProjectsConfig) links() map[string]bool {
+	result := make(map[string]bool)
+	for parent, children := range c.Relations {
+		for _, child := range children {
+			result[formatLink(parent, child)] = true
+		}
+	}
+	return result
+}
+
+func formatLink(parent, child string) string {
+	return fmt.Sprintf("%s -> %s", parent, child)
+}
+
+// ProjectsDiff describes changes between two versions of projects configuration
+type ProjectsDiff struct {
+	Added        []string `json:"added,omitempty"`         // IDs of new projects
+	Removed      []string `json:"removed,omitempty"`       // IDs of removed projects
+	Changed      []string `json:"changed,omitempty"`       // IDs of projects with modified descriptions
+	LinksAdded   []string `json:"links_added,omitempty"`   // new relations in the form of "dependency -> dependent"
+	LinksRemoved []string `json:"links_removed,omitempty"` // removed relations in the form of "dependency -> dependent"
+}
+
+// Empty returns true if there are no changes
+func (d *ProjectsDiff) Empty() bool {
+	return len(d.Added)+len(d.Removed)+len(d.Changed)+len(d.LinksAdded)+len(d.LinksRemoved) == 0
+}
+
+func (d *ProjectsDiff) sort() {
+	for _, s := range [][]string{d.Added, d.Removed, d.Changed, d.LinksAdded, d.LinksRemoved} {
+		sort.Strings(s)
+	}
+}
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"time"
+)
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	secretType   = reflect.TypeOf(Secret(""))
+)
+
+// placeholderPattern matches strings containing ${VAR} or ${VAR:-default}
+// placeholders; loader types them after substitution (see readYAML),
+// so they are accepted in place of numbers, booleans and durations
+const placeholderPattern = `\$\{[A-Za-z_][A-Za-z0-9_]*(:-[^}]*)?\}`
+
+// JSONSchema returns JSON Schema of config file, so that editors
+// could provide autocompletion and CI could check config changes
+func JSONSchema() ([]byte, error) {
+	schema := schemaOf(reflect.TypeOf(Config{}))
+	schema["$schema"] = "http://json-schema.org/draft-07/schema#"
+	schema["title"] = "buildgraph configuration"
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+type jsonSchema map[string]interface{}
+
+func schemaOf(t reflect.Type) jsonSchema {
+	if t == durationType {
+		return withPlaceholder(jsonSchema{
+			"type":    "string",
+			"pattern": `^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`,
+		}, "duration, e.g. 300ms, 10s or 1h30m")
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return schemaOf(t.Elem())
+	case reflect.Struct:
+		return structSchema(t)
+	case reflect.Slice:
+		return jsonSchema{"type": "array", "items": schemaOf(t.Elem())}
+	case reflect.Map:
+		return jsonSchema{"type": "object", "additionalProperties": schemaOf(t.Elem())}
+	case reflect.String:
+		return jsonSchema{"type": "string"}
+	case reflect.Bool:
+		return withPlaceholder(jsonSchema{"type": "boolean"}, "")
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return withPlaceholder(jsonSchema{"type": "integer"}, "")
+	case reflect.Float32, reflect.Float64:
+		return withPlaceholder(jsonSchema{"type": "number"}, "")
+	default:
+		return jsonSchema{}
+	}
+}
+
+// withPlaceholder allows environment variable placeholder in place of value
+func withPlaceholder(schema jsonSchema, description string) jsonSchema {
+	result := jsonSchema{
+		"oneOf": []interface{}{
+			schema,
+			jsonSchema{"type": "string", "pattern": placeholderPattern},
+		},
+	}
+	if description != "" {
+		result["description"] = description
+	}
+	return result
+}
+
+func structSchema(t reflect.Type) jsonSchema {
+	fields := yamlFields(t)
+	properties := make(map[string]interface{}, len(fields))
+
+	for name, field := range fields {
+		properties[name] = schemaOf(field.Type)
+
+		// the keys loader reads values of secrets from
+		reference := name + fileReferenceSuffix
+		if _, ok := fileReference(fields, reference); ok {
+			properties[reference] = jsonSchema{
+				"type":        "string",
+				"description": "path to the file containing " + name,
+			}
+		}
+	}
+
+	return jsonSchema{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+}
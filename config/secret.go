@@ -0,0 +1,29 @@
+package config
+
+import "encoding/json"
+
+const redacted = "<redacted>"
+
+// Secret is a string value (password, token, etc.) that should never
+// be exposed; it is redacted when printed, logged or serialized
+type Secret string
+
+// Value returns the actual value of a secret
+func (s Secret) Value() string { return string(s) }
+
+// String returns redacted value of a secret
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return redacted
+}
+
+// GoString makes secret redacted in %#v output as well
+func (s Secret) GoString() string { return s.String() }
+
+// MarshalYAML redacts secret in YAML output
+func (s Secret) MarshalYAML() (interface{}, error) { return s.String(), nil }
+
+// MarshalJSON redacts secret in JSON output
+func (s Secret) MarshalJSON() ([]byte, error) { return json.Marshal(s.String()) }
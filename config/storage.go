@@ -1,10 +1,14 @@
 package config
 
-import "fmt"
+import (
+	"fmt"
+	"net/url"
+	"os"
+)
 
 // StorageConfig describes various configurations of a storage layer
 type StorageConfig struct {
-	Postgres *PostgresConfig `yaml:"postgres"`
+	Postgres *PostgresConfig `yaml:"postgres,omitempty"`
 }
 
 func (c *StorageConfig) validate() error {
@@ -17,10 +21,12 @@ func (c *StorageConfig) validate() error {
 
 // PostgresConfig describes configuration of PostgreSQL client
 type PostgresConfig struct {
-	Endpoint string `yaml:"endpoint"`
-	User     string `yaml:"user"`
-	Password string `yaml:"password"`
-	Database string `yaml:"database"`
+	Endpoint string `yaml:"endpoint,omitempty"`
+	User     string `yaml:"user,omitempty"`
+	Password Secret `yaml:"password,omitempty"`
+	Database string `yaml:"database,omitempty"`
+
+	TLS *PostgresTLSConfig `yaml:"tls,omitempty"` // TLS is disabled if omitted
 }
 
 func (pc *PostgresConfig) validate() error {
@@ -30,16 +36,82 @@ func (pc *PostgresConfig) validate() error {
 	if pc.User == "" {
 		return fmt.Errorf("Wrong PostgresConfig.User value: %s", pc.User)
 	}
-	if pc.Password == "" {
-		return fmt.Errorf("Wrong PostgresConfig.Password value: %s", pc.Password)
-	}
 	if pc.Database == "" {
-		return fmt.Errorf("Wrong PostgresConfig.Database value: %s", pc.Password)
+		return fmt.Errorf("Wrong PostgresConfig.Database value: %s", pc.Database)
+	}
+	if pc.TLS != nil {
+		if err := pc.TLS.validate(); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
+// URL returns connection string; keep in mind that it contains password
 func (pc *PostgresConfig) URL() string {
-	// TODO: TLS
-	return fmt.Sprintf("postgres://%s:%s@%s/%s?sslmode=disable", pc.User, pc.Password, pc.Endpoint, pc.Database)
+	params := url.Values{}
+	if pc.TLS != nil {
+		pc.TLS.setParams(params)
+	} else {
+		params.Set("sslmode", "disable")
+	}
+
+	u := &url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(pc.User, pc.Password.Value()),
+		Host:     pc.Endpoint,
+		Path:     "/" + pc.Database,
+		RawQuery: params.Encode(),
+	}
+	return u.String()
+}
+
+// PostgresTLSConfig describes TLS settings of PostgreSQL connection;
+// the names of the settings match libpq connection parameters
+type PostgresTLSConfig struct {
+	SSLMode     string `yaml:"sslmode,omitempty"`     // disable, require, verify-ca or verify-full
+	SSLRootCert string `yaml:"sslrootcert,omitempty"` // CA certificate used to verify server certificate
+	SSLCert     string `yaml:"sslcert,omitempty"`     // client certificate
+	SSLKey      string `yaml:"sslkey,omitempty"`      // client private key
+}
+
+var postgresSSLModes = map[string]bool{
+	"disable":     true,
+	"require":     true,
+	"verify-ca":   true,
+	"verify-full": true,
+}
+
+func (c *PostgresTLSConfig) validate() error {
+	if !postgresSSLModes[c.SSLMode] {
+		return fmt.Errorf("Wrong PostgresTLSConfig.SSLMode value: %s", c.SSLMode)
+	}
+	if (c.SSLCert == "") != (c.SSLKey == "") {
+		return fmt.Errorf("PostgresTLSConfig requires both sslcert and sslkey")
+	}
+	if err := checkFilesExist(c.SSLRootCert, c.SSLCert, c.SSLKey); err != nil {
+		return err
+	}
+	if c.SSLKey != "" {
+		// PostgreSQL client refuses to use private keys accessible by others
+		info, err := os.Stat(c.SSLKey)
+		if err != nil {
+			return err
+		}
+		if info.Mode().Perm()&0077 != 0 {
+			return fmt.Errorf("%s has group or world access; permissions should be u=rw (0600) or less", c.SSLKey)
+		}
+	}
+	return nil
+}
+
+func (c *PostgresTLSConfig) setParams(params url.Values) {
+	params.Set("sslmode", c.SSLMode)
+	if c.SSLRootCert != "" {
+		params.Set("sslrootcert", c.SSLRootCert)
+	}
+	if c.SSLCert != "" {
+		params.Set("sslcert", c.SSLCert)
+		params.Set("sslkey", c.SSLKey)
+	}
 }
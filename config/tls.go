@@ -0,0 +1,126 @@
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"os"
+)
+
+// TLSClientConfig describes TLS settings of outgoing connections
+type TLSClientConfig struct {
+	CAFile             string `yaml:"ca_file,omitempty"`              // PEM encoded CA certificates used to verify server
+	CertFile           string `yaml:"cert_file,omitempty"`            // PEM encoded client certificate
+	KeyFile            string `yaml:"key_file,omitempty"`             // PEM encoded client private key
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify,omitempty"` // disables server certificate verification
+}
+
+func (c *TLSClientConfig) validate() error {
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		return fmt.Errorf("TLSClientConfig requires both cert_file and key_file")
+	}
+	return checkFilesExist(c.CAFile, c.CertFile, c.KeyFile)
+}
+
+// Build returns *tls.Config ready to use with HTTP clients
+func (c *TLSClientConfig) Build() (*tls.Config, error) {
+	result := &tls.Config{InsecureSkipVerify: c.InsecureSkipVerify}
+
+	if c.CAFile != "" {
+		data, err := ioutil.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		result.RootCAs = x509.NewCertPool()
+		if !result.RootCAs.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no certificates found in %s", c.CAFile)
+		}
+	}
+
+	if c.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		result.Certificates = []tls.Certificate{cert}
+	}
+
+	return result, nil
+}
+
+// TLS versions accepted in TLSServerConfig.MinVersion
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// default minimal TLS version of incoming connections
+const defaultTLSMinVersion = "1.2"
+
+// TLSServerConfig describes TLS settings of incoming connections;
+// certificate and key are re-read when the files change
+type TLSServerConfig struct {
+	CertFile     string `yaml:"cert_file,omitempty"`      // PEM encoded server certificate (with intermediates)
+	KeyFile      string `yaml:"key_file,omitempty"`       // PEM encoded server private key
+	ClientCAFile string `yaml:"client_ca_file,omitempty"` // CA certificates required from clients of admin routes
+	MinVersion   string `yaml:"min_version,omitempty"`    // '1.0', '1.1', '1.2' (default) or '1.3'
+}
+
+func (c *TLSServerConfig) validate() error {
+	if c.CertFile == "" || c.KeyFile == "" {
+		return fmt.Errorf("TLSServerConfig requires both cert_file and key_file")
+	}
+	if c.MinVersion == "" {
+		c.MinVersion = defaultTLSMinVersion
+	}
+	if _, exists := tlsVersions[c.MinVersion]; !exists {
+		return fmt.Errorf("Wrong TLSServerConfig.MinVersion value: %s", c.MinVersion)
+	}
+	return checkFilesExist(c.CertFile, c.KeyFile, c.ClientCAFile)
+}
+
+// Build returns *tls.Config of the listener; certificate
+// is provided by the given callback
+func (c *TLSServerConfig) Build(
+	getCertificate func(*tls.ClientHelloInfo) (*tls.Certificate, error),
+) (*tls.Config, error) {
+	result := &tls.Config{
+		MinVersion:     tlsVersions[c.MinVersion],
+		GetCertificate: getCertificate,
+	}
+
+	// certificates are verified if sent, but only admin routes demand them
+	if c.ClientCAFile != "" {
+		data, err := ioutil.ReadFile(c.ClientCAFile)
+		if err != nil {
+			return nil, err
+		}
+		result.ClientCAs = x509.NewCertPool()
+		if !result.ClientCAs.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no certificates found in %s", c.ClientCAFile)
+		}
+		result.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+
+	return result, nil
+}
+
+// checkFilesExist makes sure that every non-empty path points to a regular file
+func checkFilesExist(paths ...string) error {
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return fmt.Errorf("%s is not a regular file", path)
+		}
+	}
+	return nil
+}
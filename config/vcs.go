@@ -1,13 +1,193 @@
 package config
 
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// VCSConfig describes version control systems that post
+// notifications about repository events; every VCS has its own section,
+// which 'webhook' settings are the only ones that may be changed on reload
 type VCSConfig struct {
-	Gitlab *GitlabConfig
+	Gitlab    *GitlabConfig    `yaml:"gitlab,omitempty"`
+	Github    *SignedVCSConfig `yaml:"github,omitempty"`
+	Gitea     *SignedVCSConfig `yaml:"gitea,omitempty"`     // Gitea or Forgejo
+	Bitbucket *SignedVCSConfig `yaml:"bitbucket,omitempty"` // Bitbucket Server (Data Center)
+}
+
+// vcsSection is implemented by the sections of VCSConfig
+type vcsSection interface {
+	validate() error
+}
+
+func (c *VCSConfig) validate() error {
+	sections := reflect.ValueOf(c).Elem()
+	for i := 0; i < sections.NumField(); i++ {
+		if section := sections.Field(i); !section.IsNil() {
+			if err := section.Interface().(vcsSection).validate(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// withoutWebhooks returns copy of config with webhook settings of every VCS
+// omitted; sections that consist of webhook settings only are omitted entirely
+func (c *VCSConfig) withoutWebhooks() *VCSConfig {
+	if c == nil {
+		return c
+	}
+	result := *c
+	sections := reflect.ValueOf(&result).Elem()
+	for i := 0; i < sections.NumField(); i++ {
+		section := sections.Field(i)
+		if section.IsNil() {
+			continue
+		}
+		copied := reflect.New(section.Type().Elem()).Elem()
+		copied.Set(section.Elem())
+		if webhook := copied.FieldByName("Webhook"); webhook.IsValid() {
+			webhook.Set(reflect.Zero(webhook.Type()))
+		}
+		if copied.IsZero() {
+			section.Set(reflect.Zero(section.Type()))
+		} else {
+			section.Set(copied.Addr())
+		}
+	}
+	return &result
 }
 
-// GitlabConfig describes configuration of Github CI server,
-// that will post notifications about CI events
+// GitlabConfig describes configuration of Gitlab server,
+// that will post notifications about repository events
 type GitlabConfig struct {
-	Endpoint string
-	User     string
-	Password string
+	Endpoint string               `yaml:"endpoint,omitempty"` // Gitlab URL, e.g. https://gitlab.example.com
+	Token    Secret               `yaml:"token,omitempty"`    // API access token (see 'buildgraph gitlab-token')
+	Timeout  time.Duration        `yaml:"timeout,omitempty"`  // API request timeout
+	TLS      *TLSClientConfig     `yaml:"tls,omitempty"`      // TLS settings for API connections
+	Webhook  *GitlabWebhookConfig `yaml:"webhook,omitempty"`  // incoming notification settings
+}
+
+func (c *GitlabConfig) validate() error {
+	u, err := url.Parse(c.Endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("Wrong GitlabConfig.Endpoint value: %s", c.Endpoint)
+	}
+	if c.Token == "" {
+		return fmt.Errorf("GitlabConfig.Token is required (it can be obtained with 'buildgraph gitlab-token')")
+	}
+	if c.Timeout < 0 {
+		return fmt.Errorf("Wrong GitlabConfig.Timeout value: %v", c.Timeout)
+	}
+	if c.TLS != nil {
+		if err := c.TLS.validate(); err != nil {
+			return err
+		}
+	}
+	if c.Webhook != nil {
+		if err := c.Webhook.validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GitlabWebhookConfig describes settings of Gitlab webhooks;
+// if no tokens are configured, deliveries are not authenticated
+type GitlabWebhookConfig struct {
+	// Secret token that Gitlab puts into X-Gitlab-Token header
+	Token Secret `yaml:"token,omitempty"`
+	// Extra tokens accepted along with the main one (e.g. during rotation)
+	Tokens []Secret `yaml:"tokens,omitempty"`
+	// Tokens of particular projects keyed by path with namespace;
+	// they are used instead of the common ones for these projects
+	Projects map[string][]Secret `yaml:"projects,omitempty"`
+}
+
+func (c *GitlabWebhookConfig) validate() error {
+	for _, token := range c.Tokens {
+		if token == "" {
+			return fmt.Errorf("GitlabWebhookConfig.Tokens contains empty token")
+		}
+	}
+	for project, tokens := range c.Projects {
+		if parts := strings.Split(project, "/"); len(parts) < 2 || parts[0] == "" || parts[len(parts)-1] == "" {
+			return fmt.Errorf("Wrong GitlabWebhookConfig.Projects key: %s (expected 'namespace/name')", project)
+		}
+		if len(tokens) == 0 {
+			return fmt.Errorf("GitlabWebhookConfig.Projects: no tokens for project %s", project)
+		}
+		for _, token := range tokens {
+			if token == "" {
+				return fmt.Errorf("GitlabWebhookConfig.Projects: empty token for project %s", project)
+			}
+		}
+	}
+	return nil
+}
+
+// SignedVCSConfig describes configuration of VCS that signs
+// webhook payloads with HMAC (Github, Gitea, Bitbucket Server)
+type SignedVCSConfig struct {
+	Webhook *SignedWebhookConfig `yaml:"webhook,omitempty"` // incoming notification settings
+}
+
+func (c *SignedVCSConfig) validate() error {
+	if c.Webhook != nil {
+		return c.Webhook.validate()
+	}
+	return nil
+}
+
+// SignedWebhookConfig describes settings of webhooks, that are signed with
+// HMAC of the payload; if no secrets are configured, deliveries are rejected
+// unless unsigned ones are accepted explicitly
+type SignedWebhookConfig struct {
+	// Secret used to sign payloads
+	Secret Secret `yaml:"secret,omitempty"`
+	// Extra secrets accepted along with the main one (e.g. during rotation)
+	Secrets []Secret `yaml:"secrets,omitempty"`
+	// Accept deliveries without authentication (e.g. in test environment)
+	Insecure bool `yaml:"insecure,omitempty"`
+}
+
+func (c *SignedWebhookConfig) validate() error {
+	for _, secret := range c.Secrets {
+		if secret == "" {
+			return fmt.Errorf("SignedWebhookConfig.Secrets contains empty secret")
+		}
+	}
+	if c.Insecure && len(c.All()) != 0 {
+		return fmt.Errorf("SignedWebhookConfig.Insecure cannot be set along with secrets")
+	}
+	return nil
+}
+
+// Secrets returns all the secrets accepted from VCS (which may be nil)
+func (c *SignedVCSConfig) Secrets() []Secret {
+	if c == nil {
+		return nil
+	}
+	return c.Webhook.All()
+}
+
+// Insecure returns true if deliveries are accepted without authentication
+func (c *SignedVCSConfig) Insecure() bool {
+	return c != nil && c.Webhook != nil && c.Webhook.Insecure
+}
+
+// All returns all the accepted secrets
+func (c *SignedWebhookConfig) All() []Secret {
+	if c == nil {
+		return nil
+	}
+	var result []Secret
+	if c.Secret != "" {
+		result = append(result, c.Secret)
+	}
+	return append(result, c.Secrets...)
 }
@@ -3,12 +3,38 @@ package config
 import "fmt"
 
 type WebserverConfig struct {
-	Endpoint string `yaml:"endpoint"`
+	Endpoint string           `yaml:"endpoint,omitempty"`
+	TLS      *TLSServerConfig `yaml:"tls,omitempty"`      // plain HTTP if omitted
+	Timeouts *TimeoutsConfig  `yaml:"timeouts,omitempty"` // defaults are used if omitted
+	Limits   *LimitsConfig    `yaml:"limits,omitempty"`   // defaults are used if omitted
+	Auth     *AuthConfig      `yaml:"auth,omitempty"`     // API is open if omitted
 }
 
 func (c *WebserverConfig) validate() error {
 	if c.Endpoint == "" {
 		return fmt.Errorf("Wrong ServerConfig.Endpoint")
 	}
+	if c.TLS != nil {
+		if err := c.TLS.validate(); err != nil {
+			return err
+		}
+	}
+	if c.Timeouts == nil {
+		c.Timeouts = &TimeoutsConfig{}
+	}
+	if err := c.Timeouts.validate(); err != nil {
+		return err
+	}
+	if c.Limits == nil {
+		c.Limits = &LimitsConfig{}
+	}
+	if err := c.Limits.validate(); err != nil {
+		return err
+	}
+	if c.Auth != nil {
+		if err := c.Auth.validate(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/vitalyisaev2/buildgraph/config"
+	"github.com/vitalyisaev2/buildgraph/discovery"
+)
+
+const (
+	// exit code of 'discover -drift' when declared relations differ from discovered ones
+	exitCodeDrift = 2
+)
+
+// discover scans local checkouts of the projects and prints proposed relations;
+// in drift mode it reports the difference between declared and discovered relations
+func discover(args []string) int {
+	var (
+		path  string
+		drift bool
+	)
+
+	flags := flag.NewFlagSet("discover", flag.ExitOnError)
+	flags.StringVar(&path, "c", "", "path to config")
+	flags.BoolVar(&drift, "drift", false, fmt.Sprintf(
+		"report drift between declared and discovered relations (exit code %d if found)", exitCodeDrift,
+	))
+	flags.Parse(args)
+
+	cfg, err := config.NewConfig(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if cfg.Discovery == nil {
+		fmt.Fprintln(os.Stderr, "Missing required section 'discovery'")
+		return 1
+	}
+	if cfg.Projects == nil {
+		fmt.Fprintln(os.Stderr, "Missing required section 'projects'")
+		return 1
+	}
+
+	logger := makeLogger()
+	logger.Out = os.Stderr
+
+	scanner := discovery.NewScanner(logger, cfg.Discovery.Root)
+	result, err := scanner.Scan(cfg.Projects.Descriptions)
+	if err != nil {
+		logger.WithError(err).Error("discovery failed")
+		return 1
+	}
+
+	if !drift {
+		data, err := yaml.Marshal(map[string]interface{}{"relations": result.Relations})
+		if err != nil {
+			logger.WithError(err).Error("failed to render relations")
+			return 1
+		}
+		os.Stdout.Write(data)
+		return 0
+	}
+
+	report := discovery.NewDrift(cfg.Projects.Relations, result)
+	for _, link := range report.Missing {
+		fmt.Printf("missing: %s\n", link)
+	}
+	for _, link := range report.Stale {
+		fmt.Printf("stale: %s\n", link)
+	}
+	for _, conflict := range report.Conflicts {
+		fmt.Printf("conflict: %s\n", conflict)
+	}
+	if !report.Empty() {
+		return exitCodeDrift
+	}
+	return 0
+}
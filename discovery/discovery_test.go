@@ -0,0 +1,114 @@
+package discovery
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vitalyisaev2/buildgraph/config"
+)
+
+func parseFile(t *testing.T, p Parser, path string) *Manifest {
+	data, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	m, err := p.Parse(data)
+	assert.NoError(t, err)
+	return m
+}
+
+func TestParsers(t *testing.T) {
+	var m *Manifest
+
+	m = parseFile(t, &goModParser{}, "test/checkouts/namespace2/project1/go.mod")
+	assert.Equal(t, "example.com/namespace2/project1", m.Module)
+	assert.Equal(t, []string{"example.com/namespace1/project1"}, m.Requires)
+
+	m = parseFile(t, &goModParser{}, "test/checkouts/namespace3/project1/go.mod")
+	assert.Equal(t, "example.com/namespace3/project1", m.Module)
+	assert.Equal(t, []string{"example.com/namespace2/project1"}, m.Requires)
+
+	m = parseFile(t, &npmParser{}, "test/checkouts/namespace2/project2/package.json")
+	assert.Equal(t, "namespace2-project2", m.Module)
+	assert.Equal(t, []string{"@namespace1/project1-ui", "jest"}, m.Requires)
+
+	m = parseFile(t, &mavenParser{}, "test/checkouts/namespace3/project1/pom.xml")
+	assert.Equal(t, "com.example.namespace3:project1", m.Module)
+	assert.Equal(t, []string{
+		"com.example.namespace3:parent",
+		"com.example.namespace2:project2",
+		"junit:junit",
+	}, m.Requires)
+
+	_, err := (&goModParser{}).Parse([]byte("require example.com/x v1.0.0\n"))
+	assert.Error(t, err)
+}
+
+func TestScanner(t *testing.T) {
+	descriptions := []*config.Description{
+		{ID: "n1_p1", Namespace: "namespace1", Name: "project1"},
+		{ID: "n2_p1", Namespace: "namespace2", Name: "project1"},
+		{ID: "n2_p2", Namespace: "namespace2", Name: "project2"},
+		{ID: "n3_p1", Namespace: "namespace3", Name: "project1"},
+		{ID: "n4_p1", Namespace: "namespace4", Name: "project1"},
+	}
+
+	scanner := NewScanner(logrus.New(), "test/checkouts")
+	result, err := scanner.Scan(descriptions)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		"n1_p1": {"n2_p1", "n2_p2"},
+		"n2_p1": {"n3_p1"},
+		"n2_p2": {"n3_p1"},
+	}, result.Relations)
+	assert.NotContains(t, result.Modules, "left-pad")
+	assert.False(t, result.Scanned["n4_p1"])
+
+	// the same relations as discovered ones
+	drift := NewDrift(map[string][]string{
+		"n1_p1": {"n2_p1", "n2_p2"},
+		"n2_p1": {"n3_p1"},
+		"n2_p2": {"n3_p1"},
+	}, result)
+	assert.True(t, drift.Empty())
+
+	drift = NewDrift(map[string][]string{
+		"n1_p1": {"n2_p1", "n3_p1"},
+		"n2_p2": {"n3_p1", "n4_p1"},
+	}, result)
+	assert.False(t, drift.Empty())
+	assert.Equal(t, []string{"n1_p1 -> n2_p2", "n2_p1 -> n3_p1"}, drift.Missing)
+	// n2_p2 -> n4_p1 is not stale since n4_p1 has no checkout
+	assert.Equal(t, []string{"n1_p1 -> n3_p1"}, drift.Stale)
+}
+
+func TestScannerConflicts(t *testing.T) {
+	// namespace5/project1 keeps a copy of namespace2/project1 module
+	descriptions := []*config.Description{
+		{ID: "n1_p1", Namespace: "namespace1", Name: "project1"},
+		{ID: "n2_p1", Namespace: "namespace2", Name: "project1"},
+		{ID: "n3_p1", Namespace: "namespace3", Name: "project1"},
+		{ID: "n5_p1", Namespace: "namespace5", Name: "project1"},
+	}
+
+	logger := logrus.New()
+	logger.Out = ioutil.Discard
+	result, err := NewScanner(logger, "test/checkouts").Scan(descriptions)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{"example.com/namespace2/project1": {"n2_p1", "n5_p1"}}, result.Conflicts)
+	assert.NotContains(t, result.Modules, "example.com/namespace2/project1")
+	assert.Equal(t, "n5_p1", result.Modules["example.com/namespace5/project1"])
+	// the rest of relations are still discovered
+	assert.Equal(t, map[string][]string{"n1_p1": {"n2_p1", "n5_p1"}}, result.Relations)
+
+	drift := NewDrift(map[string][]string{
+		"n1_p1": {"n2_p1"},
+		"n2_p1": {"n3_p1"},
+	}, result)
+	assert.False(t, drift.Empty())
+	assert.Equal(t, []string{"n1_p1 -> n5_p1"}, drift.Missing)
+	// dependents of the conflicting module are unknown
+	assert.Empty(t, drift.Stale)
+	assert.Equal(t, []string{"example.com/namespace2/project1: n2_p1, n5_p1"}, drift.Conflicts)
+}
@@ -0,0 +1,73 @@
+package discovery
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Drift describes divergence between declared and discovered relations;
+// relations are represented in the form of "dependency -> dependent"
+type Drift struct {
+	Missing   []string // discovered, but not declared
+	Stale     []string // declared, but not discovered
+	Conflicts []string // modules declared by several projects, e.g. "module: project1, project2"
+}
+
+// Empty returns true if declared relations match discovered ones
+func (d *Drift) Empty() bool {
+	return len(d.Missing) == 0 && len(d.Stale) == 0 && len(d.Conflicts) == 0
+}
+
+// NewDrift compares declared relations with the discovered ones; declared relations
+// are considered stale only if both projects were scanned, since nothing
+// is known about dependencies of the projects without manifests; for the same
+// reason relations of projects declaring conflicting modules are never stale
+func NewDrift(declared map[string][]string, result *Result) *Drift {
+	var drift Drift
+
+	conflicting := make(map[string]bool)
+	for module, projects := range result.Conflicts {
+		drift.Conflicts = append(drift.Conflicts, fmt.Sprintf("%s: %s", module, strings.Join(projects, ", ")))
+		for _, projectID := range projects {
+			conflicting[projectID] = true
+		}
+	}
+
+	declaredLinks := linkSet(declared)
+	discoveredLinks := linkSet(result.Relations)
+
+	for link := range discoveredLinks {
+		if !declaredLinks[link] {
+			drift.Missing = append(drift.Missing, link.String())
+		}
+	}
+	for link := range declaredLinks {
+		if !discoveredLinks[link] && result.Scanned[link.dependency] && result.Scanned[link.dependent] &&
+			!conflicting[link.dependency] {
+			drift.Stale = append(drift.Stale, link.String())
+		}
+	}
+
+	sort.Strings(drift.Missing)
+	sort.Strings(drift.Stale)
+	sort.Strings(drift.Conflicts)
+	return &drift
+}
+
+type link struct {
+	dependency string
+	dependent  string
+}
+
+func (l link) String() string { return fmt.Sprintf("%s -> %s", l.dependency, l.dependent) }
+
+func linkSet(relations map[string][]string) map[link]bool {
+	result := make(map[link]bool)
+	for dependency, dependents := range relations {
+		for _, dependent := range dependents {
+			result[link{dependency, dependent}] = true
+		}
+	}
+	return result
+}
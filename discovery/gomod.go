@@ -0,0 +1,99 @@
+package discovery
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var _ Parser = (*goModParser)(nil)
+
+// goModParser handles Go modules; indirect dependencies are ignored
+// since they are represented by direct dependencies of other modules
+type goModParser struct{}
+
+func (p *goModParser) Name() string { return "go.mod" }
+
+func (p *goModParser) Match(filename string) bool { return filename == "go.mod" }
+
+func (p *goModParser) Parse(data []byte) (*Manifest, error) {
+	var (
+		result       Manifest
+		requireBlock bool
+		lineNumber   int
+	)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		indirect := strings.HasSuffix(line, "// indirect")
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		if requireBlock {
+			if fields[0] == ")" {
+				requireBlock = false
+				continue
+			}
+			if !indirect {
+				path, err := unquoteModulePath(fields[0])
+				if err != nil {
+					return nil, fmt.Errorf("go.mod:%d: %v", lineNumber, err)
+				}
+				result.Requires = append(result.Requires, path)
+			}
+			continue
+		}
+
+		switch fields[0] {
+		case "module":
+			if len(fields) != 2 {
+				return nil, fmt.Errorf("go.mod:%d: invalid module directive", lineNumber)
+			}
+			path, err := unquoteModulePath(fields[1])
+			if err != nil {
+				return nil, fmt.Errorf("go.mod:%d: %v", lineNumber, err)
+			}
+			result.Module = path
+		case "require":
+			switch {
+			case len(fields) == 2 && fields[1] == "(":
+				requireBlock = true
+			case len(fields) == 3:
+				if indirect {
+					continue
+				}
+				path, err := unquoteModulePath(fields[1])
+				if err != nil {
+					return nil, fmt.Errorf("go.mod:%d: %v", lineNumber, err)
+				}
+				result.Requires = append(result.Requires, path)
+			default:
+				return nil, fmt.Errorf("go.mod:%d: invalid require directive", lineNumber)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if result.Module == "" {
+		return nil, fmt.Errorf("go.mod: module directive is missing")
+	}
+	return &result, nil
+}
+
+func unquoteModulePath(s string) (string, error) {
+	if strings.HasPrefix(s, `"`) || strings.HasPrefix(s, "`") {
+		return strconv.Unquote(s)
+	}
+	return s, nil
+}
@@ -0,0 +1,55 @@
+package discovery
+
+import (
+	"encoding/xml"
+	"fmt"
+)
+
+var _ Parser = (*mavenParser)(nil)
+
+// mavenParser handles pom.xml files of Maven projects;
+// modules are identified by groupId:artifactId
+type mavenParser struct{}
+
+type mavenArtifact struct {
+	GroupID    string `xml:"groupId"`
+	ArtifactID string `xml:"artifactId"`
+}
+
+type pomXML struct {
+	mavenArtifact
+	Parent       mavenArtifact   `xml:"parent"`
+	Dependencies []mavenArtifact `xml:"dependencies>dependency"`
+}
+
+func (p *mavenParser) Name() string { return "pom.xml" }
+
+func (p *mavenParser) Match(filename string) bool { return filename == "pom.xml" }
+
+func (p *mavenParser) Parse(data []byte) (*Manifest, error) {
+	var pom pomXML
+	if err := xml.Unmarshal(data, &pom); err != nil {
+		return nil, fmt.Errorf("pom.xml: %v", err)
+	}
+
+	// groupId is inherited from parent if omitted
+	if pom.GroupID == "" {
+		pom.GroupID = pom.Parent.GroupID
+	}
+	if pom.GroupID == "" || pom.ArtifactID == "" {
+		return nil, fmt.Errorf("pom.xml: groupId or artifactId is missing")
+	}
+
+	result := &Manifest{Module: pom.coordinates()}
+	if pom.Parent.ArtifactID != "" {
+		result.Requires = append(result.Requires, pom.Parent.coordinates())
+	}
+	for _, dep := range pom.Dependencies {
+		result.Requires = append(result.Requires, dep.coordinates())
+	}
+	return result, nil
+}
+
+func (a *mavenArtifact) coordinates() string {
+	return a.GroupID + ":" + a.ArtifactID
+}
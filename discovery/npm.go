@@ -0,0 +1,54 @@
+package discovery
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+var _ Parser = (*npmParser)(nil)
+
+// npmParser handles package.json files of Node.js packages
+type npmParser struct{}
+
+type packageJSON struct {
+	Name                 string            `json:"name"`
+	Dependencies         map[string]string `json:"dependencies"`
+	DevDependencies      map[string]string `json:"devDependencies"`
+	PeerDependencies     map[string]string `json:"peerDependencies"`
+	OptionalDependencies map[string]string `json:"optionalDependencies"`
+}
+
+func (p *npmParser) Name() string { return "package.json" }
+
+func (p *npmParser) Match(filename string) bool { return filename == "package.json" }
+
+func (p *npmParser) Parse(data []byte) (*Manifest, error) {
+	var pkg packageJSON
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return nil, fmt.Errorf("package.json: %v", err)
+	}
+	if pkg.Name == "" {
+		return nil, fmt.Errorf("package.json: name is missing")
+	}
+
+	requires := make(map[string]bool)
+	dependencies := []map[string]string{
+		pkg.Dependencies,
+		pkg.DevDependencies,
+		pkg.PeerDependencies,
+		pkg.OptionalDependencies,
+	}
+	for _, deps := range dependencies {
+		for name := range deps {
+			requires[name] = true
+		}
+	}
+
+	result := &Manifest{Module: pkg.Name}
+	for name := range requires {
+		result.Requires = append(result.Requires, name)
+	}
+	sort.Strings(result.Requires)
+	return result, nil
+}
@@ -0,0 +1,28 @@
+package discovery
+
+// Manifest contains the information extracted from dependency manifest
+// (go.mod, package.json, pom.xml, etc.)
+type Manifest struct {
+	Module   string   // name other modules refer to this module by
+	Requires []string // names of modules this module depends on
+}
+
+// Parser extracts module name and its dependencies from the manifest files
+// of a particular package management system
+type Parser interface {
+	// Name returns human-readable parser name
+	Name() string
+	// Match reports whether the file with the given base name can be parsed
+	Match(filename string) bool
+	// Parse extracts module name and its dependencies from manifest contents
+	Parse(data []byte) (*Manifest, error)
+}
+
+// DefaultParsers returns the parsers of all supported manifest formats
+func DefaultParsers() []Parser {
+	return []Parser{
+		&goModParser{},
+		&npmParser{},
+		&mavenParser{},
+	}
+}
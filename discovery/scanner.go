@@ -0,0 +1,182 @@
+package discovery
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vitalyisaev2/buildgraph/config"
+)
+
+var (
+	// directories that never contain manifests of the project itself
+	skippedDirs = map[string]bool{
+		".git":         true,
+		"vendor":       true,
+		"node_modules": true,
+		"target":       true,
+	}
+)
+
+// Result contains dependencies discovered in project checkouts
+type Result struct {
+	// proposed graph in the form of adjacency list (dependency -> dependent projects)
+	Relations map[string][]string
+	// module names mapped to IDs of the projects declaring them
+	Modules map[string]string
+	// modules declared by several projects (e.g. vendored copies) mapped to IDs
+	// of the projects; nothing is linked through such modules
+	Conflicts map[string][]string
+	// IDs of projects that have at least one manifest
+	Scanned map[string]bool
+}
+
+// Scanner discovers dependencies between projects by parsing
+// manifests found in their local checkouts
+type Scanner struct {
+	root    string
+	parsers []Parser
+	logger  *logrus.Logger
+}
+
+// manifestInfo binds manifest to the project it was found in
+type manifestInfo struct {
+	*Manifest
+	projectID string
+	path      string
+}
+
+// Scan walks through checkouts of the described projects
+// (<root>/<namespace>/<name>) and builds proposed relations graph
+func (s *Scanner) Scan(descriptions []*config.Description) (*Result, error) {
+	var manifests []*manifestInfo
+	for _, d := range descriptions {
+		dir := filepath.Join(s.root, d.Namespace, d.Name)
+		found, err := s.scanCheckout(d.ID, dir)
+		if err != nil {
+			return nil, err
+		}
+		manifests = append(manifests, found...)
+	}
+
+	result := &Result{
+		Relations: make(map[string][]string),
+		Modules:   make(map[string]string),
+		Conflicts: make(map[string][]string),
+		Scanned:   make(map[string]bool),
+	}
+
+	// map module names back to projects
+	var (
+		owners = make(map[string]map[string]bool)
+		paths  = make(map[string][]string)
+	)
+	for _, m := range manifests {
+		result.Scanned[m.projectID] = true
+		if owners[m.Module] == nil {
+			owners[m.Module] = make(map[string]bool)
+		}
+		owners[m.Module][m.projectID] = true
+		paths[m.Module] = append(paths[m.Module], m.path)
+	}
+	for module, projects := range owners {
+		for projectID := range projects {
+			if len(projects) == 1 {
+				result.Modules[module] = projectID
+			} else {
+				result.Conflicts[module] = append(result.Conflicts[module], projectID)
+			}
+		}
+		if len(projects) > 1 {
+			sort.Strings(result.Conflicts[module])
+			s.logger.WithFields(logrus.Fields{
+				"module":   module,
+				"projects": result.Conflicts[module],
+				"paths":    paths[module],
+			}).Warn("module is declared by several projects, its dependents are not linked")
+		}
+	}
+
+	// link projects through the required modules
+	links := make(map[string]map[string]bool)
+	for _, m := range manifests {
+		for _, module := range m.Requires {
+			dependency, known := result.Modules[module]
+			if !known || dependency == m.projectID {
+				continue
+			}
+			if links[dependency] == nil {
+				links[dependency] = make(map[string]bool)
+			}
+			links[dependency][m.projectID] = true
+		}
+	}
+	for dependency, dependents := range links {
+		for dependent := range dependents {
+			result.Relations[dependency] = append(result.Relations[dependency], dependent)
+		}
+		sort.Strings(result.Relations[dependency])
+	}
+
+	return result, nil
+}
+
+// scanCheckout looks for manifests within the project directory
+func (s *Scanner) scanCheckout(projectID, dir string) ([]*manifestInfo, error) {
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		s.logger.WithFields(logrus.Fields{"project": projectID, "path": dir}).Warn("checkout not found")
+		return nil, nil
+	}
+
+	var result []*manifestInfo
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if path != dir && skippedDirs[info.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		for _, parser := range s.parsers {
+			if !parser.Match(info.Name()) {
+				continue
+			}
+			data, err := ioutil.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			manifest, err := parser.Parse(data)
+			if err != nil {
+				return fmt.Errorf("%s: %v", path, err)
+			}
+			s.logger.WithFields(logrus.Fields{
+				"project": projectID,
+				"path":    path,
+				"module":  manifest.Module,
+			}).Debug("manifest found")
+			result = append(result, &manifestInfo{Manifest: manifest, projectID: projectID, path: path})
+		}
+		return nil
+	})
+
+	return result, err
+}
+
+// NewScanner returns new Scanner instance; if no parsers are provided,
+// default ones are used
+func NewScanner(logger *logrus.Logger, root string, parsers ...Parser) *Scanner {
+	if len(parsers) == 0 {
+		parsers = DefaultParsers()
+	}
+	return &Scanner{
+		root:    root,
+		parsers: parsers,
+		logger:  logger,
+	}
+}
@@ -0,0 +1,5 @@
+module example.com/namespace1/project1
+
+go 1.10
+
+require github.com/sirupsen/logrus v1.0.4
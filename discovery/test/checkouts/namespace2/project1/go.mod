@@ -0,0 +1,6 @@
+module example.com/namespace2/project1
+
+require (
+	example.com/namespace1/project1 v0.1.0
+	github.com/pkg/errors v0.8.0 // indirect
+)
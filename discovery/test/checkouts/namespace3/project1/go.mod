@@ -0,0 +1,3 @@
+module "example.com/namespace3/project1"
+
+require "example.com/namespace2/project1" v0.3.0
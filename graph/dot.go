@@ -0,0 +1,32 @@
+package graph
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// WriteDOT renders graph in Graphviz DOT language
+func WriteDOT(w io.Writer, g Graph, name string) error {
+	buf := bufio.NewWriter(w)
+
+	fmt.Fprintf(buf, "digraph %s {\n", strconv.Quote(name))
+	for _, nodeName := range g.SortedKeys() {
+		n, err := g.GetNode(nodeName)
+		if err != nil {
+			return err
+		}
+		successors := n.Successors()
+		if len(successors) == 0 {
+			fmt.Fprintf(buf, "    %s;\n", strconv.Quote(nodeName))
+			continue
+		}
+		for _, successor := range successors {
+			fmt.Fprintf(buf, "    %s -> %s;\n", strconv.Quote(nodeName), strconv.Quote(successor.Name()))
+		}
+	}
+	fmt.Fprintln(buf, "}")
+
+	return buf.Flush()
+}
@@ -60,6 +60,22 @@ func (g *defaultGraph) PhasicTopologicalSortFromNode(rootName string) (PhasicTop
 	return phasicTopologicalSortFromNode(root)
 }
 
+// PhasicTopologicalSortFromNodes returns PhasicTopologicalSort
+// of the union of subgraphs for the given roots
+func (g *defaultGraph) PhasicTopologicalSortFromNodes(rootNames ...string) (PhasicTopologicalSort, error) {
+
+	roots := make([]Node, 0, len(rootNames))
+	for _, rootName := range rootNames {
+		root, err := g.GetNode(rootName)
+		if err != nil {
+			return nil, err
+		}
+		roots = append(roots, root)
+	}
+
+	return phasicTopologicalSortFromNodes(roots)
+}
+
 // Cyclic property performs cycle discovery in the given Directed Graph
 func (g *defaultGraph) Cyclic() (bool, NodeList, error) {
 
@@ -85,7 +101,7 @@ func (g *defaultGraph) Cyclic() (bool, NodeList, error) {
 	return false, nil, nil
 }
 
-//
+// SortedKeys returns sorted names of the nodes
 func (g *defaultGraph) SortedKeys() []string {
 	keys := make([]string, 0, len(g.storage))
 	for key := range g.storage {
@@ -97,6 +113,21 @@ func (g *defaultGraph) SortedKeys() []string {
 
 func (g *defaultGraph) Items() map[string]Node { return g.storage }
 
+// Reverse returns new graph with the same nodes and reversed links
+func (g *defaultGraph) Reverse() Graph {
+	reversed := &defaultGraph{make(map[string]Node, len(g.storage))}
+	for name, n := range g.storage {
+		reversed.storage[name] = NewNode(name, n.Value())
+	}
+	for name, n := range g.storage {
+		for _, successor := range n.Successors() {
+			// both nodes exist, so linking can't fail
+			_ = reversed.Link(successor.Name(), name)
+		}
+	}
+	return reversed
+}
+
 // String returns string representation of graph
 func (g *defaultGraph) String() string {
 
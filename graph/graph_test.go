@@ -2,6 +2,7 @@ package graph
 
 import (
 	//"fmt"
+	"bytes"
 	"io/ioutil"
 	"testing"
 
@@ -142,6 +143,11 @@ func TestPhasicTopologicalSort(t *testing.T) {
 	nodes = siblingNodes[4]
 	assert.Len(t, nodes, 1)
 	assert.Equal(t, "E", nodes[0].Name())
+
+	assert.Equal(t, [][]string{{"A"}, {"B"}, {"C"}, {"D"}, {"E"}}, PhaseNames(pts, nil))
+	assert.Equal(t, [][]string{{"A"}, {"C"}, {"E"}}, PhaseNames(pts, func(name string) bool {
+		return name != "B" && name != "D"
+	}))
 }
 
 func TestCyclicGraph(t *testing.T) {
@@ -175,3 +181,57 @@ func TestCyclicGraph(t *testing.T) {
 	assert.Contains(t, cycleNodeNames, "C")
 	assert.Nil(t, err)
 }
+
+func TestPhasicTopologicalSortFromNodes(t *testing.T) {
+
+	// simple1.yml - D is reachable from A, so it goes after it
+	g, _ := newGraphFromYAMLFile("test/simple1.yml")
+	pts, err := g.PhasicTopologicalSortFromNodes("D", "A", "C")
+	assert.NoError(t, err)
+
+	siblingNodes := pts.SiblingNodes()
+	assert.Len(t, siblingNodes, 3)
+	assert.ElementsMatch(t, []string{"A", "C"}, nodeNames(siblingNodes[0]))
+	assert.ElementsMatch(t, []string{"D", "E"}, nodeNames(siblingNodes[1]))
+	assert.ElementsMatch(t, []string{"F", "G", "H"}, nodeNames(siblingNodes[2]))
+
+	_, err = g.PhasicTopologicalSortFromNodes("A", "Z")
+	assert.Error(t, err)
+}
+
+func TestReverse(t *testing.T) {
+	g, _ := newGraphFromYAMLFile("test/simple1.yml")
+	r := g.Reverse()
+	assert.Equal(t, g.SortedKeys(), r.SortedKeys())
+
+	pts, err := r.PhasicTopologicalSortFromNode("G")
+	assert.NoError(t, err)
+	siblingNodes := pts.SiblingNodes()
+	assert.Len(t, siblingNodes, 3)
+	assert.ElementsMatch(t, []string{"G"}, nodeNames(siblingNodes[0]))
+	assert.ElementsMatch(t, []string{"D", "E"}, nodeNames(siblingNodes[1]))
+	assert.ElementsMatch(t, []string{"A", "B", "C"}, nodeNames(siblingNodes[2]))
+}
+
+func TestWriteDOT(t *testing.T) {
+	g, _ := NewGraphFromAdjacencyMap(map[string][]string{"A": {"B", "C"}})
+
+	var buf bytes.Buffer
+	assert.NoError(t, WriteDOT(&buf, g, "test"))
+	expected := `digraph "test" {
+    "A" -> "B";
+    "A" -> "C";
+    "B";
+    "C";
+}
+`
+	assert.Equal(t, expected, buf.String())
+}
+
+func nodeNames(ns []Node) []string {
+	result := make([]string, 0, len(ns))
+	for _, n := range ns {
+		result = append(result, n.Name())
+	}
+	return result
+}
@@ -14,6 +14,8 @@ type Graph interface {
 	CreateNode(string, interface{}) (Node, error)
 	Link(parent string, child string) error
 	Cyclic() (bool, NodeList, error)
+	SortedKeys() []string
+	Reverse() Graph
 }
 
 // Node represents a node (vertex) of a directed acyclic graph.
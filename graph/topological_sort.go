@@ -3,6 +3,7 @@ package graph
 import (
 	"bytes"
 	"fmt"
+	"sort"
 )
 
 // PhasicTopologicalSort performs sorting of directed acyclic graph for the given root,
@@ -14,6 +15,7 @@ type PhasicTopologicalSort interface {
 
 type phasicTopologicalSortBuilder interface {
 	PhasicTopologicalSortFromNode(string) (PhasicTopologicalSort, error)
+	PhasicTopologicalSortFromNodes(...string) (PhasicTopologicalSort, error)
 }
 
 type phasicTopologicalSort struct {
@@ -49,6 +51,26 @@ func (pts *phasicTopologicalSort) String() string {
 	return buffer.String()
 }
 
+// PhaseNames returns sorted names of the nodes of every phase accepted by filter
+// (any node if filter is nil); phases left empty are dropped
+func PhaseNames(pts PhasicTopologicalSort, filter func(name string) bool) [][]string {
+	var result [][]string
+	for _, nodes := range pts.SiblingNodes() {
+		names := make([]string, 0, len(nodes))
+		for _, n := range nodes {
+			if filter == nil || filter(n.Name()) {
+				names = append(names, n.Name())
+			}
+		}
+		if len(names) == 0 {
+			continue
+		}
+		sort.Strings(names)
+		result = append(result, names)
+	}
+	return result
+}
+
 // Visits all the nodes than belong to subgraph built from a given root node and
 // stores the maximal distance from the root for the every node
 func phasicTopologicalSortFromNode(n Node) (PhasicTopologicalSort, error) {
@@ -67,7 +89,23 @@ func phasicTopologicalSortFromNode(n Node) (PhasicTopologicalSort, error) {
 		return nil, fmt.Errorf("Algorithm error: stack is not empty after DFS")
 	}
 
-	// Invert nodeLevels to sequence of node slices
+	return newPhasicTopologicalSort(nodeLevels), nil
+}
+
+// Visits all the nodes that belong to subgraphs built from the given root nodes;
+// every node gets the maximal distance from any of the roots, so a root
+// reachable from another root is placed after it
+func phasicTopologicalSortFromNodes(roots []Node) (PhasicTopologicalSort, error) {
+	nodeLevels := make(map[Node]int)
+	for _, n := range roots {
+		var stack NodeList
+		traverseGraphPTS(n, stack, nodeLevels)
+	}
+	return newPhasicTopologicalSort(nodeLevels), nil
+}
+
+// Inverts nodeLevels to sequence of node slices
+func newPhasicTopologicalSort(nodeLevels map[Node]int) PhasicTopologicalSort {
 	siblingNodesMap := make(map[int][]Node)
 	for n, level := range nodeLevels {
 		siblingNodesMap[level] = append(siblingNodesMap[level], n)
@@ -77,8 +115,7 @@ func phasicTopologicalSortFromNode(n Node) (PhasicTopologicalSort, error) {
 		siblingNodesSeq = append(siblingNodesSeq, siblingNodesMap[i+1])
 	}
 
-	pts := &phasicTopologicalSort{siblingNodesSeq}
-	return pts, nil
+	return &phasicTopologicalSort{siblingNodesSeq}
 }
 
 // Depth first graph traversing for the sake of Phasic Topological Search
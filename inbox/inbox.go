@@ -0,0 +1,337 @@
+package inbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/vitalyisaev2/buildgraph/common"
+	"github.com/vitalyisaev2/buildgraph/config"
+	"github.com/vitalyisaev2/buildgraph/metrics"
+	"github.com/vitalyisaev2/buildgraph/storage"
+	"github.com/vitalyisaev2/buildgraph/vcs"
+)
+
+const (
+	// period of looking for deliveries which retry time has come
+	pollPeriod = time.Second
+	// period of updating queue depth metrics
+	metricsPeriod = 15 * time.Second
+	// period of removing expired webhook requests from the log
+	prunePeriod = time.Hour
+	// time delivery is hidden from other workers after it has been claimed;
+	// deliveries of crashed workers are processed again after that
+	claimLease = 5 * time.Minute
+	// maximal time of processing a single delivery
+	processingTimeout = time.Minute
+	// maximal time of a single storage request made by inbox itself
+	storageTimeout = 10 * time.Second
+)
+
+// Handler processes delivery taken from the inbox; deliveries
+// are retried on errors unless they are wrapped with Permanent
+type Handler func(ctx context.Context, d *storage.Delivery) error
+
+// PermanentError means that delivery can't be processed at all,
+// so it is marked as dead without retries
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string { return e.Err.Error() }
+
+// Permanent wraps error of processing that makes no sense to retry
+func Permanent(err error) error { return &PermanentError{Err: err} }
+
+// Inbox accepts webhook deliveries for processing in background
+type Inbox interface {
+	common.Service
+	common.HealthChecker
+	// Enqueue saves delivery into the storage; once it returns, delivery
+	// is not going to be lost; deliveries resent by VCS are not saved again,
+	// false is returned for them along with the stored delivery
+	Enqueue(ctx context.Context, p vcs.Provider, d *vcs.Delivery) (*storage.Delivery, bool, error)
+	// Requeue gives dead delivery another round of attempts
+	Requeue(ctx context.Context, id common.ObjectID) error
+	// Replay saves delivery as a new one regardless of its UUID,
+	// so it is processed again even if it has been processed before
+	Replay(ctx context.Context, p vcs.Provider, d *vcs.Delivery) (*storage.Delivery, error)
+}
+
+var _ Inbox = (*defaultInbox)(nil)
+
+type defaultInbox struct {
+	logger  *logrus.Logger
+	storage storage.DeliveryStorage
+	cfg     *config.InboxConfig
+	handler Handler
+
+	pollPeriod time.Duration
+	slots      chan struct{} // tokens of idle workers
+	wakeup     chan struct{} // makes dispatcher look for deliveries at once
+	exit       chan struct{}
+	wg         sync.WaitGroup
+
+	mutex    sync.Mutex
+	claimErr error // result of the last attempt to claim deliveries
+}
+
+func (in *defaultInbox) Enqueue(ctx context.Context, p vcs.Provider, d *vcs.Delivery) (*storage.Delivery, bool, error) {
+	stored := &storage.Delivery{
+		Provider: p.Name(),
+		UUID:     p.DeliveryID(d.Header),
+		Header:   d.Header,
+		Payload:  d.Payload,
+	}
+	saved, err := in.storage.SaveDelivery(ctx, stored)
+	if err != nil {
+		return nil, false, err
+	}
+	if saved {
+		in.notify()
+	}
+	return stored, saved, nil
+}
+
+func (in *defaultInbox) Requeue(ctx context.Context, id common.ObjectID) error {
+	if err := in.storage.RequeueDelivery(ctx, id); err != nil {
+		return err
+	}
+	in.notify()
+	return nil
+}
+
+func (in *defaultInbox) Replay(ctx context.Context, p vcs.Provider, d *vcs.Delivery) (*storage.Delivery, error) {
+	// deliveries without UUID are never considered resent
+	stored := &storage.Delivery{Provider: p.Name(), Header: d.Header, Payload: d.Payload}
+	if _, err := in.storage.SaveDelivery(ctx, stored); err != nil {
+		return nil, err
+	}
+	in.notify()
+	return stored, nil
+}
+
+// Stop waits for the deliveries that are being processed;
+// the rest of them stay in the storage till the next start
+func (in *defaultInbox) Stop() {
+	close(in.exit)
+	in.wg.Wait()
+}
+
+// CheckHealth reports whether deliveries are still taken from the storage
+func (in *defaultInbox) CheckHealth(context.Context) error {
+	select {
+	case <-in.exit:
+		return errors.New("inbox is stopped")
+	default:
+	}
+
+	in.mutex.Lock()
+	defer in.mutex.Unlock()
+	if in.claimErr != nil {
+		return fmt.Errorf("failed to claim webhook deliveries: %v", in.claimErr)
+	}
+	return nil
+}
+
+func (in *defaultInbox) notify() {
+	select {
+	case in.wakeup <- struct{}{}:
+	default:
+	}
+}
+
+// dispatch passes due deliveries to idle workers
+func (in *defaultInbox) dispatch() {
+	defer in.wg.Done()
+
+	poll := time.NewTicker(in.pollPeriod)
+	defer poll.Stop()
+	gauges := time.NewTicker(metricsPeriod)
+	defer gauges.Stop()
+	prune := time.NewTicker(prunePeriod)
+	defer prune.Stop()
+
+	in.updateMetrics()
+	in.prune()
+	for {
+		in.claim()
+		select {
+		case <-in.exit:
+			return
+		case <-in.wakeup:
+		case <-poll.C:
+		case <-gauges.C:
+			in.updateMetrics()
+		case <-prune.C:
+			in.prune()
+		}
+	}
+}
+
+// claim takes as many due deliveries as there are idle workers
+func (in *defaultInbox) claim() {
+	for {
+		idle := in.acquire()
+		if idle == 0 {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), storageTimeout)
+		deliveries, err := in.storage.ClaimDeliveries(ctx, idle, claimLease)
+		cancel()
+		in.mutex.Lock()
+		in.claimErr = err
+		in.mutex.Unlock()
+		if err != nil {
+			in.release(idle)
+			in.logger.WithError(err).Error("failed to claim webhook deliveries")
+			return
+		}
+
+		in.release(idle - len(deliveries))
+		for _, d := range deliveries {
+			in.wg.Add(1)
+			go in.process(d)
+		}
+		if len(deliveries) < idle {
+			return
+		}
+	}
+}
+
+// acquire takes tokens of all the idle workers
+func (in *defaultInbox) acquire() int {
+	for n := 0; ; n++ {
+		select {
+		case <-in.slots:
+		default:
+			return n
+		}
+	}
+}
+
+func (in *defaultInbox) release(n int) {
+	for i := 0; i < n; i++ {
+		in.slots <- struct{}{}
+	}
+}
+
+// process passes delivery to handler and saves the result
+func (in *defaultInbox) process(d *storage.Delivery) {
+	defer in.wg.Done()
+	defer in.notify()
+	defer in.release(1)
+
+	logger := in.logger.WithFields(logrus.Fields{
+		"delivery": d.ID,
+		"provider": d.Provider,
+		"attempt":  d.Attempts,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), processingTimeout)
+	err := in.handler(ctx, d)
+	cancel()
+
+	// the result is saved even if processing has timed out
+	ctx, cancel = context.WithTimeout(context.Background(), storageTimeout)
+	defer cancel()
+
+	var (
+		permanent *PermanentError
+		saveErr   error
+	)
+	switch {
+	case err == nil:
+		metrics.InboxAttempts.WithLabelValues(metrics.AttemptProcessed).Inc()
+		saveErr = in.storage.CompleteDelivery(ctx, d.ID)
+	case errors.As(err, &permanent) || d.Attempts >= in.cfg.MaxAttempts:
+		metrics.InboxAttempts.WithLabelValues(metrics.AttemptDead).Inc()
+		logger.WithError(err).Error("webhook delivery is dead")
+		saveErr = in.storage.BuryDelivery(ctx, d.ID, err.Error())
+	default:
+		metrics.InboxAttempts.WithLabelValues(metrics.AttemptPostponed).Inc()
+		delay := in.cfg.Delay(d.Attempts)
+		logger.WithError(err).WithField("delay", delay).Warn("failed to process webhook delivery, retrying later")
+		saveErr = in.storage.PostponeDelivery(ctx, d.ID, err.Error(), delay)
+	}
+	// unsaved delivery is processed again once its lease expires
+	if saveErr != nil {
+		logger.WithError(saveErr).Error("failed to save result of webhook delivery processing")
+	}
+}
+
+func (in *defaultInbox) updateMetrics() {
+	ctx, cancel := context.WithTimeout(context.Background(), storageTimeout)
+	defer cancel()
+
+	for _, state := range []string{storage.DeliveryPending, storage.DeliveryDead} {
+		count, err := in.storage.CountDeliveries(ctx, state)
+		if err != nil {
+			in.logger.WithError(err).Error("failed to count webhook deliveries")
+			return
+		}
+		metrics.InboxDeliveries.WithLabelValues(state).Set(float64(count))
+	}
+}
+
+// prune removes webhook requests and payloads of processed deliveries
+// which retention period has expired
+func (in *defaultInbox) prune() {
+	ctx, cancel := context.WithTimeout(context.Background(), storageTimeout)
+	defer cancel()
+
+	before := time.Now().Add(-in.cfg.Retention)
+
+	pruned, err := in.storage.PruneWebhookRequests(ctx, before)
+	if err != nil {
+		in.logger.WithError(err).Error("failed to prune webhook request log")
+	} else if pruned > 0 {
+		in.logger.WithField("pruned", pruned).Debug("expired webhook requests removed from the log")
+	}
+
+	pruned, err = in.storage.PruneDeliveries(ctx, before)
+	if err != nil {
+		in.logger.WithError(err).Error("failed to prune deliveries")
+	} else if pruned > 0 {
+		in.logger.WithField("pruned", pruned).Debug("payloads of expired deliveries removed")
+	}
+}
+
+// NewInbox starts processing of the deliveries kept in the storage
+func NewInbox(
+	logger *logrus.Logger,
+	s storage.DeliveryStorage,
+	cfg *config.InboxConfig,
+	handler Handler,
+) Inbox {
+	return newInbox(logger, s, cfg, handler, pollPeriod)
+}
+
+func newInbox(
+	logger *logrus.Logger,
+	s storage.DeliveryStorage,
+	cfg *config.InboxConfig,
+	handler Handler,
+	pollPeriod time.Duration,
+) *defaultInbox {
+	in := &defaultInbox{
+		logger:     logger,
+		storage:    s,
+		cfg:        cfg,
+		handler:    handler,
+		pollPeriod: pollPeriod,
+		slots:      make(chan struct{}, cfg.Workers),
+		wakeup:     make(chan struct{}, 1),
+		exit:       make(chan struct{}),
+	}
+	in.release(cfg.Workers)
+
+	in.wg.Add(1)
+	go in.dispatch()
+	return in
+}
@@ -0,0 +1,299 @@
+package inbox
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vitalyisaev2/buildgraph/common"
+	"github.com/vitalyisaev2/buildgraph/config"
+	"github.com/vitalyisaev2/buildgraph/storage"
+	"github.com/vitalyisaev2/buildgraph/vcs"
+	"github.com/vitalyisaev2/buildgraph/vcs/gitlab"
+)
+
+// memStorage keeps deliveries in memory
+type memStorage struct {
+	mutex       sync.Mutex
+	deliveries  []*storage.Delivery
+	requests    []*storage.WebhookRequest
+	unavailable bool // deliveries can't be claimed
+}
+
+func (s *memStorage) SaveDelivery(ctx context.Context, d *storage.Delivery) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, stored := range s.deliveries {
+		if d.UUID != "" && stored.Provider == d.Provider && stored.UUID == d.UUID {
+			*d = *stored
+			return false, nil
+		}
+	}
+	d.ID = len(s.deliveries) + 1
+	d.State, d.Received, d.NextAttempt = storage.DeliveryPending, time.Now(), time.Now()
+	copied := *d
+	s.deliveries = append(s.deliveries, &copied)
+	return true, nil
+}
+
+func (s *memStorage) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*storage.Delivery, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.unavailable {
+		return nil, errors.New("database is unavailable")
+	}
+	var result []*storage.Delivery
+	for _, d := range s.deliveries {
+		if len(result) < limit && d.State == storage.DeliveryPending && !d.NextAttempt.After(time.Now()) {
+			d.Attempts++
+			d.NextAttempt = time.Now().Add(lease)
+			copied := *d
+			result = append(result, &copied)
+		}
+	}
+	return result, nil
+}
+
+func (s *memStorage) update(id common.ObjectID, f func(d *storage.Delivery)) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if id < 1 || id > len(s.deliveries) {
+		return storage.ErrNotFound
+	}
+	f(s.deliveries[id-1])
+	return nil
+}
+
+func (s *memStorage) CompleteDelivery(ctx context.Context, id common.ObjectID) error {
+	return s.update(id, func(d *storage.Delivery) { d.State = storage.DeliveryDone })
+}
+
+func (s *memStorage) PostponeDelivery(ctx context.Context, id common.ObjectID, reason string, delay time.Duration) error {
+	return s.update(id, func(d *storage.Delivery) { d.LastError, d.NextAttempt = reason, time.Now().Add(delay) })
+}
+
+func (s *memStorage) BuryDelivery(ctx context.Context, id common.ObjectID, reason string) error {
+	return s.update(id, func(d *storage.Delivery) { d.LastError, d.State = reason, storage.DeliveryDead })
+}
+
+func (s *memStorage) RequeueDelivery(ctx context.Context, id common.ObjectID) error {
+	return s.update(id, func(d *storage.Delivery) {
+		d.State, d.Attempts, d.NextAttempt = storage.DeliveryPending, 0, time.Now()
+	})
+}
+
+func (s *memStorage) ListDeliveries(ctx context.Context, state string, page storage.Page) ([]*storage.Delivery, error) {
+	return nil, nil
+}
+
+func (s *memStorage) GetDelivery(ctx context.Context, id common.ObjectID) (*storage.Delivery, error) {
+	var result storage.Delivery
+	err := s.update(id, func(d *storage.Delivery) { result = *d })
+	return &result, err
+}
+
+func (s *memStorage) CountDeliveries(ctx context.Context, state string) (int, error) {
+	return 0, nil
+}
+
+func (s *memStorage) PruneDeliveries(ctx context.Context, before time.Time) (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	pruned := 0
+	for _, d := range s.deliveries {
+		if d.State == storage.DeliveryDone && d.Received.Before(before) && len(d.Payload) != 0 {
+			d.Header, d.Payload = http.Header{}, []byte{}
+			pruned++
+		}
+	}
+	return pruned, nil
+}
+
+func (s *memStorage) SaveWebhookRequest(ctx context.Context, r *storage.WebhookRequest) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.requests = append(s.requests, r)
+	return nil
+}
+
+func (s *memStorage) ListWebhookRequests(
+	ctx context.Context,
+	filter *storage.WebhookRequestFilter,
+	page storage.Page,
+) ([]*storage.WebhookRequest, error) {
+	return nil, nil
+}
+
+func (s *memStorage) GetWebhookRequest(ctx context.Context, id common.ObjectID) (*storage.WebhookRequest, error) {
+	return nil, storage.ErrNotFound
+}
+
+func (s *memStorage) PruneWebhookRequests(ctx context.Context, before time.Time) (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	var kept []*storage.WebhookRequest
+	for _, r := range s.requests {
+		if !r.Received.Before(before) {
+			kept = append(kept, r)
+		}
+	}
+	pruned := len(s.requests) - len(kept)
+	s.requests = kept
+	return pruned, nil
+}
+
+// waitState polls delivery until it gets into the state
+func waitState(t *testing.T, s *memStorage, id common.ObjectID, state string) *storage.Delivery {
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		d, err := s.GetDelivery(context.Background(), id)
+		assert.NoError(t, err)
+		if d.State == state || time.Now().After(deadline) {
+			assert.Equal(t, state, d.State)
+			return d
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestInbox(t *testing.T) {
+	cfg := &config.InboxConfig{Workers: 2, MaxAttempts: 3, Backoff: time.Millisecond, MaxBackoff: time.Millisecond, Retention: time.Hour}
+	s := &memStorage{
+		deliveries: []*storage.Delivery{
+			{
+				ID: 1, Provider: "gitlab", UUID: "expired", State: storage.DeliveryDone,
+				Received: time.Now().Add(-2 * time.Hour), Header: http.Header{}, Payload: []byte("expired"),
+			},
+		},
+		requests: []*storage.WebhookRequest{
+			{Received: time.Now().Add(-2 * time.Hour)},
+			{Received: time.Now()},
+		},
+	}
+
+	var failures int32
+	handler := func(ctx context.Context, d *storage.Delivery) error {
+		switch string(d.Payload) {
+		case "broken":
+			return Permanent(errors.New("invalid payload"))
+		case "flaky":
+			if d.Attempts < 2 {
+				return errors.New("database is unavailable")
+			}
+			return nil
+		case "failing":
+			atomic.AddInt32(&failures, 1)
+			return errors.New("database is unavailable")
+		}
+		return nil
+	}
+	logger := logrus.New()
+	logger.Out = ioutil.Discard
+	in := newInbox(logger, s, cfg, handler, 10*time.Millisecond)
+	defer in.Stop()
+
+	provider := &gitlab.Provider{}
+	enqueue := func(payload string) common.ObjectID {
+		header := http.Header{}
+		header.Set(gitlab.EventUUIDHeader, payload)
+		d, saved, err := in.Enqueue(context.Background(), provider, &vcs.Delivery{Header: header, Payload: []byte(payload)})
+		assert.NoError(t, err)
+		assert.True(t, saved)
+		return d.ID
+	}
+	ok, broken, flaky, failing := enqueue("ok"), enqueue("broken"), enqueue("flaky"), enqueue("failing")
+
+	// resent delivery is not processed again
+	header := http.Header{}
+	header.Set(gitlab.EventUUIDHeader, "ok")
+	d, saved, err := in.Enqueue(context.Background(), provider, &vcs.Delivery{Header: header, Payload: []byte("ok")})
+	assert.NoError(t, err)
+	assert.False(t, saved)
+	assert.Equal(t, ok, d.ID)
+
+	d = waitState(t, s, ok, storage.DeliveryDone)
+	assert.Equal(t, 1, d.Attempts)
+
+	// replayed delivery is processed once again
+	d, err = in.Replay(context.Background(), provider, &vcs.Delivery{Header: header, Payload: []byte("ok")})
+	assert.NoError(t, err)
+	assert.NotEqual(t, ok, d.ID)
+	waitState(t, s, d.ID, storage.DeliveryDone)
+
+	d = waitState(t, s, broken, storage.DeliveryDead)
+	assert.Equal(t, 1, d.Attempts)
+	assert.Equal(t, "invalid payload", d.LastError)
+
+	d = waitState(t, s, flaky, storage.DeliveryDone)
+	assert.Equal(t, 2, d.Attempts)
+
+	d = waitState(t, s, failing, storage.DeliveryDead)
+	assert.Equal(t, cfg.MaxAttempts, d.Attempts)
+	assert.Equal(t, int32(cfg.MaxAttempts), atomic.LoadInt32(&failures))
+
+	// requeued delivery gets fresh attempts
+	assert.NoError(t, in.Requeue(context.Background(), failing))
+	deadline := time.Now().Add(5 * time.Second)
+	for atomic.LoadInt32(&failures) < int32(2*cfg.MaxAttempts) && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	d = waitState(t, s, failing, storage.DeliveryDead)
+	assert.Equal(t, cfg.MaxAttempts, d.Attempts)
+	assert.Equal(t, int32(2*cfg.MaxAttempts), atomic.LoadInt32(&failures))
+
+	// expired webhook requests are removed from the log on start
+	// along with payloads of expired deliveries
+	s.mutex.Lock()
+	assert.Len(t, s.requests, 1)
+	assert.Empty(t, s.deliveries[0].Payload)
+	s.mutex.Unlock()
+
+	// while pruned deliveries are still recognized when resent
+	header.Set(gitlab.EventUUIDHeader, "expired")
+	d, saved, err = in.Enqueue(context.Background(), provider, &vcs.Delivery{Header: header, Payload: []byte("expired")})
+	assert.NoError(t, err)
+	assert.False(t, saved)
+	assert.Equal(t, 1, d.ID)
+}
+
+func TestInboxHealth(t *testing.T) {
+	cfg := &config.InboxConfig{Workers: 1, MaxAttempts: 1, Retention: time.Hour}
+	s := &memStorage{}
+	logger := logrus.New()
+	logger.Out = ioutil.Discard
+	in := newInbox(logger, s, cfg, func(context.Context, *storage.Delivery) error { return nil }, 10*time.Millisecond)
+
+	// waitHealth polls inbox till its health matches the expected one
+	waitHealth := func(healthy bool) error {
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			err := in.CheckHealth(context.Background())
+			if (err == nil) == healthy || time.Now().After(deadline) {
+				return err
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	assert.NoError(t, waitHealth(true))
+
+	s.mutex.Lock()
+	s.unavailable = true
+	s.mutex.Unlock()
+	assert.EqualError(t, waitHealth(false), "failed to claim webhook deliveries: database is unavailable")
+
+	s.mutex.Lock()
+	s.unavailable = false
+	s.mutex.Unlock()
+	assert.NoError(t, waitHealth(true))
+
+	in.Stop()
+	assert.EqualError(t, in.CheckHealth(context.Background()), "inbox is stopped")
+}
@@ -0,0 +1,110 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "buildgraph"
+
+// Outcomes of webhook deliveries
+const (
+	OutcomeAccepted  = "accepted"  // events were saved
+	OutcomeRejected  = "rejected"  // authentication failed
+	OutcomeInvalid   = "invalid"   // payload could not be decoded
+	OutcomeFailed    = "failed"    // events could not be saved
+	OutcomeDuplicate = "duplicate" // delivery has been received before
+)
+
+// EventUnknown labels deliveries that were not decoded
+const EventUnknown = "unknown"
+
+// Outcomes of attempts to process webhook deliveries taken from the inbox
+const (
+	AttemptProcessed = "processed" // events were saved
+	AttemptPostponed = "postponed" // delivery is going to be retried
+	AttemptDead      = "dead"      // delivery is not going to be retried anymore
+)
+
+// Reasons of rejecting HTTP requests before they are handled
+const (
+	ReasonBodyTooLarge = "body_too_large" // replied with 413
+	ReasonRateLimited  = "rate_limited"   // replied with 429
+)
+
+var (
+	// WebhookDeliveries counts webhook events by provider, event type and outcome
+	WebhookDeliveries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "webhook",
+		Name:      "deliveries_total",
+		Help:      "Number of webhook events received, by provider, event type and outcome.",
+	}, []string{"provider", "event", "outcome"})
+
+	// InboxDeliveries is the number of webhook deliveries in the inbox, by state
+	// (processed deliveries are not counted)
+	InboxDeliveries = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "inbox",
+		Name:      "deliveries",
+		Help:      "Number of pending and dead webhook deliveries in the inbox.",
+	}, []string{"state"})
+
+	// InboxAttempts counts attempts to process webhook deliveries by outcome
+	InboxAttempts = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "inbox",
+		Name:      "attempts_total",
+		Help:      "Number of attempts to process webhook deliveries, by outcome.",
+	}, []string{"outcome"})
+
+	// RejectedRequests counts HTTP requests rejected by server limits, by route group and reason
+	RejectedRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "rejected_requests_total",
+		Help:      "Number of HTTP requests rejected by server limits, by route group and reason.",
+	}, []string{"route", "reason"})
+
+	// StorageTransactionDuration measures storage transactions by operation
+	StorageTransactionDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "storage",
+		Name:      "transaction_duration_seconds",
+		Help:      "Duration of storage transactions, by operation.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"operation"})
+
+	// StorageTransactionFailures counts failed transactions by operation and failed step
+	StorageTransactionFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "storage",
+		Name:      "transaction_failures_total",
+		Help:      "Number of failed storage transactions, by operation and step.",
+	}, []string{"operation", "step"})
+
+	// GraphNodes is the number of projects in the dependency graph
+	GraphNodes = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "graph",
+		Name:      "nodes",
+		Help:      "Number of projects in the dependency graph.",
+	})
+
+	// GraphEdges is the number of relations in the dependency graph
+	GraphEdges = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "graph",
+		Name:      "edges",
+		Help:      "Number of relations in the dependency graph.",
+	})
+
+	// PlanDuration measures computation of rebuild plans
+	PlanDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "graph",
+		Name:      "plan_duration_seconds",
+		Help:      "Duration of rebuild plan computation.",
+		Buckets:   prometheus.ExponentialBuckets(0.0001, 4, 8),
+	})
+)
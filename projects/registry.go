@@ -0,0 +1,88 @@
+package projects
+
+import (
+	"sync"
+
+	"github.com/vitalyisaev2/buildgraph/config"
+	"github.com/vitalyisaev2/buildgraph/graph"
+	"github.com/vitalyisaev2/buildgraph/metrics"
+)
+
+// Registry keeps actual project descriptions and their dependency graph;
+// the whole state is replaced atomically, so readers never observe
+// partially applied changes
+type Registry interface {
+	Config() *config.ProjectsConfig
+	Graph() graph.Graph
+	Description(id string) (*config.Description, bool)
+	Update(*config.ProjectsConfig) (*config.ProjectsDiff, error)
+}
+
+var _ Registry = (*defaultRegistry)(nil)
+
+type defaultRegistry struct {
+	mutex        sync.RWMutex
+	cfg          *config.ProjectsConfig
+	graph        graph.Graph
+	descriptions map[string]*config.Description
+}
+
+func (r *defaultRegistry) Config() *config.ProjectsConfig {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.cfg
+}
+
+func (r *defaultRegistry) Graph() graph.Graph {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.graph
+}
+
+func (r *defaultRegistry) Description(id string) (*config.Description, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	d, ok := r.descriptions[id]
+	return d, ok
+}
+
+// Update builds new project graph and swaps it with the current one
+func (r *defaultRegistry) Update(cfg *config.ProjectsConfig) (*config.ProjectsDiff, error) {
+	g, err := cfg.Graph()
+	if err != nil {
+		return nil, err
+	}
+
+	descriptions := make(map[string]*config.Description, len(cfg.Descriptions))
+	for _, d := range cfg.Descriptions {
+		descriptions[d.ID] = d
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	diff := r.cfg.Diff(cfg)
+	r.cfg = cfg
+	r.graph = g
+	r.descriptions = descriptions
+
+	nodes := g.SortedKeys()
+	var edges int
+	for _, name := range nodes {
+		n, _ := g.GetNode(name)
+		edges += len(n.Successors())
+	}
+	metrics.GraphNodes.Set(float64(len(nodes)))
+	metrics.GraphEdges.Set(float64(edges))
+
+	return diff, nil
+}
+
+// NewRegistry builds new Registry instance from the given projects configuration
+func NewRegistry(cfg *config.ProjectsConfig) (Registry, error) {
+	r := &defaultRegistry{cfg: &config.ProjectsConfig{}}
+	if _, err := r.Update(cfg); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
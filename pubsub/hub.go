@@ -0,0 +1,199 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/vitalyisaev2/buildgraph/common"
+)
+
+const (
+	// DefaultHistorySize is the number of recent messages kept for resuming subscribers
+	DefaultHistorySize = 1024
+	// DefaultBufferSize is the number of messages subscriber may lag behind
+	// before it is considered to be too slow and gets disconnected
+	DefaultBufferSize = 64
+)
+
+var (
+	// ErrSlowSubscriber means that subscriber didn't keep up with publishers;
+	// it may resubscribe starting from the last message it has received
+	ErrSlowSubscriber = errors.New("subscriber is too slow")
+	// ErrStopped means that hub doesn't serve subscribers anymore
+	ErrStopped = errors.New("hub is stopped")
+)
+
+// Hub delivers messages from publishers to interested subscribers
+type Hub interface {
+	common.Service
+	common.HealthChecker
+	// Publish assigns ID to message and sends it to subscribers;
+	// it never blocks on slow subscribers
+	Publish(m *Message)
+	// Subscribe returns subscription receiving messages matching filter;
+	// if lastID is not zero, kept messages published after the one with
+	// lastID are received first
+	Subscribe(f *Filter, lastID uint64) Subscription
+}
+
+// Subscription is a stream of messages received by a single subscriber
+type Subscription interface {
+	// Messages returns channel that is closed when subscription terminates
+	Messages() <-chan *Message
+	// Err returns the reason subscription was terminated by hub,
+	// nil if it is still active or was closed by subscriber
+	Err() error
+	// Close terminates subscription
+	Close()
+}
+
+var _ Hub = (*defaultHub)(nil)
+
+type defaultHub struct {
+	mutex       sync.Mutex
+	lastID      uint64
+	history     []*Message // ring buffer of recent messages
+	head        int        // index of the oldest message in history
+	size        int        // number of messages in history
+	bufferSize  int
+	subscribers map[*subscription]struct{}
+	stopped     bool
+}
+
+func (h *defaultHub) Publish(m *Message) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if h.stopped {
+		return
+	}
+
+	h.lastID++
+	m.ID = h.lastID
+	if m.Time.IsZero() {
+		m.Time = time.Now()
+	}
+	h.remember(m)
+
+	for s := range h.subscribers {
+		if !s.filter.Match(m) {
+			continue
+		}
+		select {
+		case s.ch <- m:
+		default:
+			h.terminate(s, ErrSlowSubscriber)
+		}
+	}
+}
+
+func (h *defaultHub) Subscribe(f *Filter, lastID uint64) Subscription {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	var missed []*Message
+	if lastID != 0 {
+		for i := 0; i < h.size; i++ {
+			m := h.history[(h.head+i)%len(h.history)]
+			if m.ID > lastID && f.Match(m) {
+				missed = append(missed, m)
+			}
+		}
+	}
+
+	s := &subscription{
+		hub:    h,
+		filter: f,
+		ch:     make(chan *Message, h.bufferSize+len(missed)),
+	}
+	for _, m := range missed {
+		s.ch <- m
+	}
+
+	if h.stopped {
+		s.err = ErrStopped
+		close(s.ch)
+		return s
+	}
+	h.subscribers[s] = struct{}{}
+	return s
+}
+
+func (h *defaultHub) Stop() {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.stopped = true
+	for s := range h.subscribers {
+		h.terminate(s, ErrStopped)
+	}
+}
+
+// CheckHealth reports whether hub still accepts subscribers
+func (h *defaultHub) CheckHealth(context.Context) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if h.stopped {
+		return ErrStopped
+	}
+	return nil
+}
+
+// remember puts message into history replacing the oldest one if it is full
+func (h *defaultHub) remember(m *Message) {
+	if len(h.history) == 0 {
+		return
+	}
+	if h.size < len(h.history) {
+		h.history[(h.head+h.size)%len(h.history)] = m
+		h.size++
+		return
+	}
+	h.history[h.head] = m
+	h.head = (h.head + 1) % len(h.history)
+}
+
+// terminate unregisters subscriber; must be called under lock
+func (h *defaultHub) terminate(s *subscription, err error) {
+	if _, exists := h.subscribers[s]; !exists {
+		return
+	}
+	delete(h.subscribers, s)
+	s.err = err
+	close(s.ch)
+}
+
+// NewHub creates hub keeping historySize recent messages and allowing
+// subscribers to lag behind for bufferSize messages
+func NewHub(historySize, bufferSize int) Hub {
+	return &defaultHub{
+		history:     make([]*Message, historySize),
+		bufferSize:  bufferSize,
+		subscribers: make(map[*subscription]struct{}),
+	}
+}
+
+var _ Subscription = (*subscription)(nil)
+
+type subscription struct {
+	hub    *defaultHub
+	filter *Filter
+	ch     chan *Message
+	err    error
+}
+
+func (s *subscription) Messages() <-chan *Message { return s.ch }
+
+func (s *subscription) Err() error {
+	s.hub.mutex.Lock()
+	defer s.hub.mutex.Unlock()
+	return s.err
+}
+
+func (s *subscription) Close() {
+	s.hub.mutex.Lock()
+	defer s.hub.mutex.Unlock()
+	s.hub.terminate(s, nil)
+}
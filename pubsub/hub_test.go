@@ -0,0 +1,82 @@
+package pubsub
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func receive(s Subscription) []uint64 {
+	var ids []uint64
+	for {
+		select {
+		case m, ok := <-s.Messages():
+			if !ok {
+				return ids
+			}
+			ids = append(ids, m.ID)
+		default:
+			return ids
+		}
+	}
+}
+
+func TestHubFilter(t *testing.T) {
+	h := NewHub(DefaultHistorySize, DefaultBufferSize)
+	defer h.Stop()
+
+	all := h.Subscribe(nil, 0)
+	byProject := h.Subscribe(&Filter{Projects: []string{"namespace1/project1"}}, 0)
+	byNamespace := h.Subscribe(&Filter{Namespaces: []string{"namespace2"}}, 0)
+
+	h.Publish(&Message{Type: TypePush, Namespace: "namespace1", Project: "namespace1/project1"})
+	h.Publish(&Message{Type: TypePush, Namespace: "namespace2", Project: "namespace2/project1"})
+	h.Publish(&Message{Type: TypePush, Namespace: "namespace1", Project: "namespace1/project2"})
+
+	assert.Equal(t, []uint64{1, 2, 3}, receive(all))
+	assert.Equal(t, []uint64{1}, receive(byProject))
+	assert.Equal(t, []uint64{2}, receive(byNamespace))
+
+	all.Close()
+	assert.NoError(t, all.Err())
+	h.Publish(&Message{Type: TypePush, Namespace: "namespace1", Project: "namespace1/project1"})
+	assert.Empty(t, receive(all))
+}
+
+func TestHubResume(t *testing.T) {
+	h := NewHub(3, DefaultBufferSize)
+	defer h.Stop()
+
+	for i := 0; i < 5; i++ {
+		h.Publish(&Message{Type: TypePush, Namespace: "namespace1", Project: "namespace1/project1"})
+	}
+
+	// only the last 3 messages are kept
+	s := h.Subscribe(nil, 1)
+	assert.Equal(t, []uint64{3, 4, 5}, receive(s))
+	s = h.Subscribe(nil, 4)
+	assert.Equal(t, []uint64{5}, receive(s))
+	s = h.Subscribe(&Filter{Namespaces: []string{"namespace2"}}, 1)
+	assert.Empty(t, receive(s))
+}
+
+func TestHubSlowSubscriber(t *testing.T) {
+	h := NewHub(DefaultHistorySize, 2)
+
+	slow := h.Subscribe(nil, 0)
+	for i := 0; i < 3; i++ {
+		h.Publish(&Message{Type: TypePush, Namespace: "namespace1", Project: "namespace1/project1"})
+	}
+
+	// buffered messages are still delivered before channel is closed
+	assert.Equal(t, []uint64{1, 2}, receive(slow))
+	assert.Equal(t, ErrSlowSubscriber, slow.Err())
+
+	// subscriber catches up by resuming from the last received message
+	resumed := h.Subscribe(nil, 2)
+	assert.Equal(t, []uint64{3}, receive(resumed))
+
+	h.Stop()
+	assert.Equal(t, ErrStopped, resumed.Err())
+	assert.Equal(t, ErrStopped, h.Subscribe(nil, 0).Err())
+}
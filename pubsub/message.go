@@ -0,0 +1,56 @@
+package pubsub
+
+import (
+	"strings"
+	"time"
+)
+
+// Types of published messages
+const (
+	TypePush         = "push"
+	TypeTagPush      = "tag_push"
+	TypeMergeRequest = "merge_request"
+	TypePipeline     = "pipeline"
+	TypeJob          = "job"
+	TypeBuildState   = "build_state"
+)
+
+// Message is a notification about something happened to the project
+type Message struct {
+	ID        uint64      `json:"id"`
+	Time      time.Time   `json:"time"`
+	Type      string      `json:"type"`
+	Namespace string      `json:"namespace"`
+	Project   string      `json:"project"` // project path (namespace/name)
+	Data      interface{} `json:"data,omitempty"`
+}
+
+// Filter selects messages of particular projects or namespaces;
+// empty filter matches all messages
+type Filter struct {
+	Projects   []string // project paths (namespace/name)
+	Namespaces []string
+}
+
+// Empty returns true if filter matches all messages
+func (f *Filter) Empty() bool {
+	return f == nil || len(f.Projects)+len(f.Namespaces) == 0
+}
+
+// Match reports whether message passes the filter
+func (f *Filter) Match(m *Message) bool {
+	if f.Empty() {
+		return true
+	}
+	for _, p := range f.Projects {
+		if strings.EqualFold(p, m.Project) {
+			return true
+		}
+	}
+	for _, ns := range f.Namespaces {
+		if strings.EqualFold(ns, m.Namespace) {
+			return true
+		}
+	}
+	return false
+}
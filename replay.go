@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/vitalyisaev2/buildgraph/config"
+	"github.com/vitalyisaev2/buildgraph/service"
+	"github.com/vitalyisaev2/buildgraph/storage"
+	"github.com/vitalyisaev2/buildgraph/vcs"
+)
+
+const (
+	// timeout of a single replayed request
+	replayTimeout = 30 * time.Second
+	// maximal size of a single line of export file
+	maxExportLine = 64 << 20
+)
+
+// replay sends webhook requests exported from the request log
+// ('GET /inbox/requests/export') to a buildgraph instance, the oldest first;
+// secrets are not logged, so they are restored from the local config;
+// delivery IDs are removed, otherwise the instance would take
+// requests for resent deliveries and ignore them
+func replay(args []string) int {
+	var target, path string
+
+	flags := flag.NewFlagSet("replay", flag.ExitOnError)
+	flags.StringVar(&target, "url", "http://localhost:8080", "URL of buildgraph instance")
+	flags.StringVar(&path, "c", "", "path to config with webhook secrets of the instance")
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: buildgraph replay -c <config> [-url <url>] <export file or - for stdin>\n")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 1
+	}
+
+	cfg, err := config.NewConfig(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	providers, err := service.NewVCSRegistry(func() *config.VCSConfig { return cfg.VCS })
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	requests, err := readExport(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	client := &http.Client{Timeout: replayTimeout}
+	code := 0
+	for i := len(requests) - 1; i >= 0; i-- {
+		logged := requests[i]
+		if logged.Outcome == storage.RequestRejected {
+			fmt.Printf("request %d (%s %d): skipped\n", logged.ID, logged.Outcome, logged.Status)
+			continue
+		}
+		restoreSecrets(providers, logged)
+		logged.Header = providers.Anonymize(logged.Header)
+
+		status, err := replayRequest(client, strings.TrimRight(target, "/"), logged)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "request %d: %v\n", logged.ID, err)
+			return 1
+		}
+		fmt.Printf("request %d (%s %d): %d\n", logged.ID, logged.Outcome, logged.Status, status)
+		if status >= 500 {
+			code = 1
+		}
+	}
+	return code
+}
+
+// restoreSecrets adds secret headers of the provider of logged request
+func restoreSecrets(providers vcs.Registry, logged *storage.WebhookRequest) {
+	p, known := providers.Get(logged.Provider)
+	if !known {
+		if p, known = providers.Detect(logged.Header); !known {
+			return
+		}
+	}
+	d := &vcs.Delivery{Header: logged.Header.Clone(), Payload: logged.Payload}
+	p.RestoreSecrets(d)
+	logged.Header = d.Header
+}
+
+// readExport reads JSON lines produced by export endpoint
+func readExport(path string) ([]*storage.WebhookRequest, error) {
+	var input io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		input = f
+	}
+
+	var requests []*storage.WebhookRequest
+	scanner := bufio.NewScanner(input)
+	scanner.Buffer(nil, maxExportLine)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var logged storage.WebhookRequest
+		if err := json.Unmarshal(scanner.Bytes(), &logged); err != nil {
+			return nil, fmt.Errorf("%s:%d: %v", path, line, err)
+		}
+		requests = append(requests, &logged)
+	}
+	return requests, scanner.Err()
+}
+
+// replayRequest sends request with original URL, headers and payload
+// and returns status of the reply
+func replayRequest(client *http.Client, target string, logged *storage.WebhookRequest) (int, error) {
+	req, err := http.NewRequest("POST", target+logged.URL, bytes.NewReader(logged.Payload))
+	if err != nil {
+		return 0, err
+	}
+	for name, values := range logged.Header {
+		req.Header[name] = values
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, resp.Body)
+	return resp.StatusCode, nil
+}
@@ -1,34 +1,170 @@
 package service
 
 import (
+	"context"
+	"sync"
+
 	"github.com/sirupsen/logrus"
+	gitlabapi "github.com/xanzy/go-gitlab"
+
+	"github.com/vitalyisaev2/buildgraph/auth"
+	"github.com/vitalyisaev2/buildgraph/common"
 	"github.com/vitalyisaev2/buildgraph/config"
+	"github.com/vitalyisaev2/buildgraph/inbox"
+	"github.com/vitalyisaev2/buildgraph/projects"
+	"github.com/vitalyisaev2/buildgraph/pubsub"
 	"github.com/vitalyisaev2/buildgraph/storage"
 	"github.com/vitalyisaev2/buildgraph/storage/postgres"
+	"github.com/vitalyisaev2/buildgraph/vcs"
+	"github.com/vitalyisaev2/buildgraph/vcs/bitbucket"
+	"github.com/vitalyisaev2/buildgraph/vcs/gitea"
+	"github.com/vitalyisaev2/buildgraph/vcs/github"
+	"github.com/vitalyisaev2/buildgraph/vcs/gitlab"
+	"github.com/vitalyisaev2/buildgraph/workflow"
 )
 
 type Collection struct {
-	Logger  *logrus.Logger // TODO: turn into interface
-	Storage storage.Storage
+	Logger   *logrus.Logger // TODO: turn into interface
+	Storage  storage.Storage
+	Projects projects.Registry
+	Gitlab   *gitlabapi.Client // nil if Gitlab integration is not configured
+	VCS      vcs.Registry      // providers of webhook deliveries
+	Hub      pubsub.Hub        // live stream of incoming events and build states
+	Workflow workflow.Manager
+	Inbox    inbox.Inbox // webhook deliveries waiting for processing
+
+	// configuration the services are running with (may be replaced on reload)
+	cfg           *config.Config
+	cfgMutex      sync.Mutex
+	reloadErr     error              // result of the last config reload
+	authenticator auth.Authenticator // built from the current config
+
+	// serializes project registry updates
+	projectsMutex sync.Mutex
+
+	// services readiness depends on
+	healthChecks map[string]*healthCheck
+	healthMutex  sync.Mutex
+}
+
+// Config returns configuration the services are running with
+func (c *Collection) Config() *config.Config {
+	c.cfgMutex.Lock()
+	defer c.cfgMutex.Unlock()
+	return c.cfg
+}
+
+// Authenticator returns authenticator of API clients built from the current config
+func (c *Collection) Authenticator() auth.Authenticator {
+	c.cfgMutex.Lock()
+	defer c.cfgMutex.Unlock()
+	return c.authenticator
 }
 
 func (c *Collection) Stop() {
+	c.Logger.Debug("stopping inbox")
+	c.Inbox.Stop()
+	c.Logger.Debug("stopping event hub")
+	c.Hub.Stop()
 	c.Logger.Debug("stopping storage")
 	c.Storage.Stop()
 }
 
 func NewCollection(logger *logrus.Logger, cfg *config.Config) (*Collection, error) {
+	logger.Info("starting storage")
+	s, err := postgres.NewStorage(logger, cfg.Storage.Postgres)
+	if err != nil {
+		return nil, err
+	}
+	return NewCollectionWithStorage(logger, cfg, s)
+}
+
+// NewCollectionWithStorage starts the rest of services on top of the given storage;
+// the storage and the services started so far are stopped if the rest fail to start
+func NewCollectionWithStorage(logger *logrus.Logger, cfg *config.Config, s storage.Storage) (*Collection, error) {
 	var (
 		c   Collection
 		err error
 	)
 
 	c.Logger = logger
+	c.cfg = cfg
+	c.Storage = s
+	c.AddHealthCheck("storage", c.Storage, true)
+	// failed reload leaves the previous configuration working
+	c.AddHealthCheck("config", common.HealthCheckerFunc(c.checkConfig), false)
+	if c.authenticator, err = auth.NewAuthenticator(cfg.Webserver.Auth); err != nil {
+		c.Storage.Stop()
+		return nil, err
+	}
 
-	c.Logger.Info("starting storage")
-	if c.Storage, err = postgres.NewStorage(c.Logger, cfg.Storage.Postgres); err != nil {
+	c.Logger.Info("starting project registry")
+	ctx := context.Background()
+	if _, err = c.importProjects(ctx, cfg.Projects); err != nil {
+		c.Storage.Stop()
+		return nil, err
+	}
+	stored, err := c.Storage.LoadProjects(ctx)
+	if err != nil {
+		c.Storage.Stop()
+		return nil, err
+	}
+	if c.Projects, err = projects.NewRegistry(stored); err != nil {
+		c.Storage.Stop()
+		return nil, err
+	}
+
+	c.Hub = pubsub.NewHub(pubsub.DefaultHistorySize, pubsub.DefaultBufferSize)
+	c.AddHealthCheck("hub", c.Hub, true)
+	c.Workflow = workflow.NewManager(c.Logger, c.Projects, c.Hub)
+
+	if c.VCS, err = NewVCSRegistry(func() *config.VCSConfig { return c.Config().VCS }); err != nil {
+		c.Hub.Stop()
+		c.Storage.Stop()
 		return nil, err
 	}
 
+	if cfg.VCS != nil && cfg.VCS.Gitlab != nil {
+		c.Logger.WithField("endpoint", cfg.VCS.Gitlab.Endpoint).Info("starting Gitlab client")
+		if c.Gitlab, err = gitlab.NewClient(cfg.VCS.Gitlab); err != nil {
+			c.Hub.Stop()
+			c.Storage.Stop()
+			return nil, err
+		}
+		// webhooks are accepted while Gitlab API is unavailable
+		checker, err := gitlab.NewHealthChecker(cfg.VCS.Gitlab)
+		if err != nil {
+			c.Hub.Stop()
+			c.Storage.Stop()
+			return nil, err
+		}
+		c.AddHealthCheck("gitlab", checker, false)
+	}
+
+	// deliveries left from the previous run are processed at once
+	c.Logger.Info("starting inbox")
+	c.Inbox = inbox.NewInbox(c.Logger, c.Storage, cfg.Inbox, c.processDelivery)
+	// accepted deliveries are kept in the storage till inbox recovers
+	c.AddHealthCheck("inbox", c.Inbox, false)
+
 	return &c, nil
 }
+
+// NewVCSRegistry returns registry of all the supported webhook providers;
+// each of them is given its own section of the config returned by cfg,
+// which is called on every delivery, so that reloaded settings are used;
+// Gitea imitates Github headers, so it has to be detected first
+func NewVCSRegistry(cfg func() *config.VCSConfig) (vcs.Registry, error) {
+	sections := func() *config.VCSConfig {
+		if c := cfg(); c != nil {
+			return c
+		}
+		return &config.VCSConfig{}
+	}
+	return vcs.NewRegistry(
+		gitlab.NewProvider(func() *config.GitlabConfig { return sections().Gitlab }),
+		gitea.NewProvider(func() *config.SignedVCSConfig { return sections().Gitea }),
+		github.NewProvider(func() *config.SignedVCSConfig { return sections().Github }),
+		bitbucket.NewProvider(func() *config.SignedVCSConfig { return sections().Bitbucket }),
+	)
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/vitalyisaev2/buildgraph/inbox"
+	"github.com/vitalyisaev2/buildgraph/metrics"
+	"github.com/vitalyisaev2/buildgraph/pubsub"
+	"github.com/vitalyisaev2/buildgraph/storage"
+	"github.com/vitalyisaev2/buildgraph/vcs"
+)
+
+// eventView is a provider-independent summary of VCS event
+type eventView struct {
+	Provider   string   `json:"provider"`
+	Ref        string   `json:"ref,omitempty"`
+	Tag        string   `json:"tag,omitempty"`
+	Commit     string   `json:"commit,omitempty"`
+	Commits    []string `json:"commits,omitempty"`
+	IID        int      `json:"iid,omitempty"`
+	Title      string   `json:"title,omitempty"`
+	Action     string   `json:"action,omitempty"`
+	Status     string   `json:"status,omitempty"`
+	PipelineID int      `json:"pipeline_id,omitempty"`
+	JobID      int      `json:"job_id,omitempty"`
+	Name       string   `json:"name,omitempty"`
+	Stage      string   `json:"stage,omitempty"`
+	URL        string   `json:"url,omitempty"`
+}
+
+// processDelivery decodes webhook delivery taken from the inbox, saves its
+// events and passes them to subscribers and the workflow layer
+func (c *Collection) processDelivery(ctx context.Context, d *storage.Delivery) error {
+	p, exists := c.VCS.Get(d.Provider)
+	if !exists {
+		return inbox.Permanent(fmt.Errorf("unknown VCS provider '%s'", d.Provider))
+	}
+
+	events, err := p.Decode(&vcs.Delivery{Header: d.Header, Payload: d.Payload})
+	if err != nil {
+		metrics.WebhookDeliveries.WithLabelValues(p.Name(), metrics.EventUnknown, metrics.OutcomeInvalid).Inc()
+		return inbox.Permanent(err)
+	}
+
+	// events are saved all at once, so that retries neither
+	// save nor publish any of them for the second time
+	saved, err := c.Storage.SaveDeliveryEvents(ctx, d.ID, events)
+	if err != nil {
+		for _, event := range events {
+			metrics.WebhookDeliveries.WithLabelValues(p.Name(), eventType(event), metrics.OutcomeFailed).Inc()
+		}
+		return err
+	}
+	if !saved {
+		c.Logger.WithFields(logrus.Fields{
+			"delivery": d.ID,
+			"provider": p.Name(),
+		}).Info("events of delivery have been saved before")
+		return nil
+	}
+
+	for _, event := range events {
+		metrics.WebhookDeliveries.WithLabelValues(p.Name(), eventType(event), metrics.OutcomeAccepted).Inc()
+		c.Hub.Publish(newEventMessage(p.Name(), event))
+		// event is already saved, so workflow failures don't affect delivery
+		if err = c.registerEvent(event); err != nil {
+			c.Logger.WithError(err).WithFields(logrus.Fields{
+				"delivery": d.ID,
+				"provider": p.Name(),
+			}).Error("failed to register event in workflow")
+		}
+	}
+	return nil
+}
+
+// registerEvent passes event to the workflow layer
+func (c *Collection) registerEvent(event vcs.Event) error {
+	switch e := event.(type) {
+	case vcs.PushEvent:
+		return c.Workflow.RegisterVCSPushEvent(e)
+	case vcs.TagPushEvent:
+		return c.Workflow.RegisterVCSTagPushEvent(e)
+	case vcs.MergeRequestEvent:
+		return c.Workflow.RegisterVCSMergeRequestEvent(e)
+	case vcs.JobEvent:
+		return c.Workflow.RegisterCIJobEvent(e)
+	case vcs.PipelineEvent:
+		return c.Workflow.RegisterCIPipelineEvent(e)
+	default:
+		return fmt.Errorf("unexpected event type: %T", event)
+	}
+}
+
+// eventType names event in metrics and the event stream
+func eventType(event vcs.Event) string {
+	switch event.(type) {
+	case vcs.PushEvent:
+		return pubsub.TypePush
+	case vcs.TagPushEvent:
+		return pubsub.TypeTagPush
+	case vcs.MergeRequestEvent:
+		return pubsub.TypeMergeRequest
+	case vcs.JobEvent:
+		return pubsub.TypeJob
+	case vcs.PipelineEvent:
+		return pubsub.TypePipeline
+	default:
+		return metrics.EventUnknown
+	}
+}
+
+// newEventMessage describes VCS event for subscribers of the event hub
+func newEventMessage(provider string, event vcs.Event) *pubsub.Message {
+	project := event.GetProject()
+	m := &pubsub.Message{
+		Type:      eventType(event),
+		Namespace: project.GetNamespace(),
+		Project:   project.GetNamespace() + "/" + project.GetName(),
+	}
+	v := &eventView{Provider: provider}
+
+	switch e := event.(type) {
+	case vcs.PushEvent:
+		v.Ref = e.GetRef()
+		for _, c := range e.GetCommits() {
+			v.Commits = append(v.Commits, c.GetHash())
+		}
+	case vcs.TagPushEvent:
+		v.Tag = e.GetTag()
+		v.Commit = e.GetCommit()
+	case vcs.MergeRequestEvent:
+		v.IID = e.GetIID()
+		v.Title = e.GetTitle()
+		v.Action = e.GetAction()
+		v.Status = e.GetState()
+		v.Ref = e.GetTargetBranch()
+		v.Commit = e.GetLastCommit()
+		v.URL = e.GetURL()
+	case vcs.JobEvent:
+		v.JobID = e.GetJobID()
+		v.PipelineID = e.GetPipelineID()
+		v.Name = e.GetName()
+		v.Stage = e.GetStage()
+		v.Ref = e.GetRef()
+		v.Commit = e.GetCommit()
+		v.Status = e.GetStatus()
+	case vcs.PipelineEvent:
+		v.PipelineID = e.GetPipelineID()
+		v.Ref = e.GetRef()
+		v.Commit = e.GetCommit()
+		v.Status = e.GetStatus()
+	}
+
+	m.Data = v
+	return m
+}
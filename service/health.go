@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/vitalyisaev2/buildgraph/common"
+)
+
+const (
+	// maximal duration of a single component check
+	healthCheckTimeout = 3 * time.Second
+
+	HealthOK      = "ok"
+	HealthFailing = "failing"
+)
+
+// HealthReport describes readiness of the services
+type HealthReport struct {
+	Ready      bool                        `json:"ready"`
+	Components map[string]*ComponentHealth `json:"components"`
+}
+
+// ComponentHealth is a result of a single component check
+type ComponentHealth struct {
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Critical bool   `json:"critical"` // failure of critical component makes the whole service unready
+	Duration string `json:"duration"`
+}
+
+type healthCheck struct {
+	checker  common.HealthChecker
+	critical bool
+}
+
+// AddHealthCheck makes readiness depend on the service; services started
+// outside of the collection (e.g. webserver) register themselves here
+func (c *Collection) AddHealthCheck(name string, checker common.HealthChecker, critical bool) {
+	c.healthMutex.Lock()
+	defer c.healthMutex.Unlock()
+	if c.healthChecks == nil {
+		c.healthChecks = make(map[string]*healthCheck)
+	}
+	c.healthChecks[name] = &healthCheck{checker: checker, critical: critical}
+}
+
+// CheckReadiness runs checks of all the registered services concurrently
+func (c *Collection) CheckReadiness(ctx context.Context) *HealthReport {
+	c.healthMutex.Lock()
+	checks := make(map[string]*healthCheck, len(c.healthChecks))
+	for name, check := range c.healthChecks {
+		checks[name] = check
+	}
+	c.healthMutex.Unlock()
+
+	var (
+		report = &HealthReport{Ready: true, Components: make(map[string]*ComponentHealth, len(checks))}
+		mutex  sync.Mutex
+		wg     sync.WaitGroup
+	)
+	for name, check := range checks {
+		wg.Add(1)
+		go func(name string, check *healthCheck) {
+			defer wg.Done()
+			result := runHealthCheck(ctx, check)
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			report.Components[name] = result
+			if result.Status != HealthOK && check.critical {
+				report.Ready = false
+			}
+		}(name, check)
+	}
+	wg.Wait()
+
+	return report
+}
+
+func runHealthCheck(ctx context.Context, check *healthCheck) *ComponentHealth {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check.checker.CheckHealth(ctx)
+	result := &ComponentHealth{
+		Status:   HealthOK,
+		Critical: check.critical,
+		Duration: time.Since(start).String(),
+	}
+	if err != nil {
+		result.Status = HealthFailing
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// checkConfig reports whether the current configuration is the one read from file
+func (c *Collection) checkConfig(context.Context) error {
+	c.cfgMutex.Lock()
+	defer c.cfgMutex.Unlock()
+	if c.reloadErr != nil {
+		return fmt.Errorf("last reload has failed, previous config is in use: %v", c.reloadErr)
+	}
+	return nil
+}
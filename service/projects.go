@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/vitalyisaev2/buildgraph/config"
+)
+
+const (
+	// actor of the changes caused by config
+	configActor = "config"
+)
+
+// importProjects fills the storage with projects from config, if there are any;
+// if config is ignored since the storage already contains projects, the changes
+// config would have made are returned
+func (c *Collection) importProjects(ctx context.Context, cfg *config.ProjectsConfig) (*config.ProjectsDiff, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+
+	imported, err := c.Storage.ImportProjects(ctx, cfg, configActor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to import projects from config: %v", err)
+	}
+	if imported {
+		c.Logger.WithField("mode", cfg.Import).Info("projects have been imported from config")
+		return nil, nil
+	}
+
+	stored, err := c.Storage.LoadProjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ignored := stored.Diff(cfg)
+	if ignored.Empty() {
+		c.Logger.WithField("mode", cfg.Import).Info("storage already contains projects from config")
+		return nil, nil
+	}
+	c.Logger.WithFields(logrus.Fields{
+		"mode":    cfg.Import,
+		"added":   ignored.Added,
+		"removed": ignored.Removed,
+		"changed": ignored.Changed,
+	}).Warn("storage already contains projects, changes of projects in config are ignored")
+	return ignored, nil
+}
+
+// RefreshProjects loads projects from the storage and swaps the project graph;
+// it should be called after every change of stored projects
+func (c *Collection) RefreshProjects(ctx context.Context) (*config.ProjectsDiff, error) {
+	c.projectsMutex.Lock()
+	defer c.projectsMutex.Unlock()
+
+	stored, err := c.Storage.LoadProjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	diff, err := c.Projects.Update(stored)
+	if err != nil {
+		return nil, err
+	}
+
+	c.logProjectsDiff(diff)
+	return diff, nil
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vitalyisaev2/buildgraph/auth"
+	"github.com/vitalyisaev2/buildgraph/config"
+)
+
+// ReloadReport describes changes made by config reload
+type ReloadReport struct {
+	// 'seed' imports projects from config only if the storage has no projects,
+	// 'overwrite' replaces the stored projects with the ones from config
+	Import string `json:"import,omitempty"`
+	// changes applied to project graph
+	Applied *config.ProjectsDiff `json:"applied"`
+	// changes of projects in config that are not applied, since the storage
+	// already contains projects and they are imported in 'seed' mode
+	Ignored *config.ProjectsDiff `json:"ignored,omitempty"`
+	Warning string               `json:"warning,omitempty"`
+}
+
+// ReloadConfig re-reads configuration file and applies the settings
+// that can be changed without restart; the current configuration
+// stays untouched if the new one is invalid
+func (c *Collection) ReloadConfig() (*ReloadReport, error) {
+	c.cfgMutex.Lock()
+	defer c.cfgMutex.Unlock()
+
+	report, err := c.reloadConfig()
+	c.reloadErr = err
+	return report, err
+}
+
+// reloadConfig must be called under cfgMutex
+func (c *Collection) reloadConfig() (*ReloadReport, error) {
+	c.Logger.WithField("path", c.cfg.Path()).Info("reloading config")
+
+	next, err := config.NewConfig(c.cfg.Path())
+	if err != nil {
+		return nil, fmt.Errorf("invalid config: %v", err)
+	}
+
+	if err := c.cfg.CheckReloadable(next); err != nil {
+		return nil, err
+	}
+
+	authenticator, err := auth.NewAuthenticator(next.Webserver.Auth)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	ignored, err := c.importProjects(ctx, next.Projects)
+	if err != nil {
+		return nil, err
+	}
+	c.cfg = next
+	c.authenticator = authenticator
+
+	report := &ReloadReport{Ignored: ignored}
+	if next.Projects != nil {
+		report.Import = next.Projects.Import
+	}
+	if ignored != nil {
+		report.Warning = "storage already contains projects, so changes of projects in config are ignored; " +
+			"set 'import: overwrite' to replace the stored projects or use projects API"
+	}
+	if report.Applied, err = c.RefreshProjects(ctx); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (c *Collection) logProjectsDiff(diff *config.ProjectsDiff) {
+	if diff.Empty() {
+		c.Logger.Info("project configuration has not changed")
+		return
+	}
+
+	changes := []struct {
+		kind  string
+		items []string
+	}{
+		{"project added", diff.Added},
+		{"project removed", diff.Removed},
+		{"project changed", diff.Changed},
+		{"relation added", diff.LinksAdded},
+		{"relation removed", diff.LinksRemoved},
+	}
+	for _, change := range changes {
+		for _, item := range change.items {
+			c.Logger.WithField("item", item).Info(change.kind)
+		}
+	}
+}
@@ -2,15 +2,215 @@ package storage
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
 
 	"github.com/vitalyisaev2/buildgraph/common"
+	"github.com/vitalyisaev2/buildgraph/config"
 	"github.com/vitalyisaev2/buildgraph/vcs"
 )
 
+var (
+	// ErrNotFound is returned when requested object doesn't exist
+	ErrNotFound = errors.New("object not found")
+)
+
+// ConflictError is returned when requested change would break
+// the consistency of stored data (e.g. introduce a cycle into project graph)
+type ConflictError struct {
+	Reason string
+}
+
+func (e *ConflictError) Error() string { return e.Reason }
+
 // Storage is an abstraction layer above the particular SQL/NoSQL storages;
 // it should implement all the methods required by front and graph layer;
 type Storage interface {
 	// PushEvent
 	SavePushEvent(context.Context, vcs.PushEvent) error
+	// Other repository and CI events
+	SaveTagPushEvent(context.Context, vcs.TagPushEvent) error
+	SaveMergeRequestEvent(context.Context, vcs.MergeRequestEvent) error
+	SavePipelineEvent(context.Context, vcs.PipelineEvent) error
+	SaveJobEvent(context.Context, vcs.JobEvent) error
+	// SaveDeliveryEvents saves all the events decoded from webhook delivery within
+	// a single transaction; false is returned if they have been saved before
+	SaveDeliveryEvents(ctx context.Context, deliveryID common.ObjectID, events []vcs.Event) (bool, error)
+	EventReader
+	ProjectStorage
+	DeliveryStorage
 	common.Service
+	common.HealthChecker
+}
+
+// ProjectStorage keeps project descriptions and their relations;
+// every change is checked for consistency before commit and
+// recorded into audit trail on behalf of the actor
+type ProjectStorage interface {
+	// LoadProjects returns all the stored descriptions and relations
+	LoadProjects(ctx context.Context) (*config.ProjectsConfig, error)
+	// ImportProjects fills the storage with projects from config; in seed mode
+	// projects are imported only into empty storage, in overwrite mode
+	// the stored projects are replaced; returns true if the storage has been changed
+	ImportProjects(ctx context.Context, cfg *config.ProjectsConfig, actor string) (bool, error)
+	// SaveProject creates or updates project description
+	SaveProject(ctx context.Context, d *config.Description, actor string) error
+	// DeleteProject removes project with all its relations
+	DeleteProject(ctx context.Context, id string, actor string) error
+	// SaveRelation makes dependent project depend on dependency
+	SaveRelation(ctx context.Context, dependency, dependent string, actor string) error
+	// DeleteRelation removes relation between projects
+	DeleteRelation(ctx context.Context, dependency, dependent string, actor string) error
+	// ListAuditRecords returns the latest changes of projects, newest first
+	ListAuditRecords(ctx context.Context, limit int) ([]*AuditRecord, error)
+}
+
+// AuditRecord describes a single change of projects
+type AuditRecord struct {
+	ID     common.ObjectID `json:"id"`
+	Time   time.Time       `json:"time"`
+	Actor  string          `json:"actor"`
+	Action string          `json:"action"`
+	Object string          `json:"object"`           // project ID or relation
+	Before json.RawMessage `json:"before,omitempty"` // object state before the change
+	After  json.RawMessage `json:"after,omitempty"`  // object state after the change
+}
+
+// DeliveryStorage is a durable queue of webhook deliveries waiting
+// for processing; claimed deliveries are hidden from other workers
+// for the lease period, so the deliveries of crashed workers
+// are processed again once their lease expires
+type DeliveryStorage interface {
+	// SaveDelivery puts new delivery into the queue; delivery is saved only once
+	// per UUID, so for the resent ones false is returned and the stored delivery
+	// is read into d
+	SaveDelivery(ctx context.Context, d *Delivery) (bool, error)
+	// ClaimDeliveries returns up to limit pending deliveries that are due,
+	// counting attempt and postponing the next one for the lease period
+	ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*Delivery, error)
+	// CompleteDelivery marks delivery as processed
+	CompleteDelivery(ctx context.Context, id common.ObjectID) error
+	// PostponeDelivery records processing error and schedules the next attempt
+	PostponeDelivery(ctx context.Context, id common.ObjectID, reason string, delay time.Duration) error
+	// BuryDelivery records processing error and marks delivery as dead
+	BuryDelivery(ctx context.Context, id common.ObjectID, reason string) error
+	// RequeueDelivery returns dead delivery into the queue with fresh attempts
+	RequeueDelivery(ctx context.Context, id common.ObjectID) error
+	// ListDeliveries returns deliveries in the state (in any state if empty)
+	ListDeliveries(ctx context.Context, state string, page Page) ([]*Delivery, error)
+	// GetDelivery returns delivery by ID
+	GetDelivery(ctx context.Context, id common.ObjectID) (*Delivery, error)
+	// CountDeliveries returns the number of deliveries in the state
+	CountDeliveries(ctx context.Context, state string) (int, error)
+	// PruneDeliveries removes headers and payloads of done deliveries received
+	// before the given time and returns the number of pruned ones; deliveries
+	// are kept, so that the ones resent by VCS are still recognized by UUID
+	PruneDeliveries(ctx context.Context, before time.Time) (int, error)
+	// SaveWebhookRequest appends raw webhook request to the log
+	SaveWebhookRequest(ctx context.Context, r *WebhookRequest) error
+	// ListWebhookRequests returns logged requests matching the filter
+	ListWebhookRequests(ctx context.Context, filter *WebhookRequestFilter, page Page) ([]*WebhookRequest, error)
+	// GetWebhookRequest returns logged request by ID
+	GetWebhookRequest(ctx context.Context, id common.ObjectID) (*WebhookRequest, error)
+	// PruneWebhookRequests removes requests received before the given time
+	// and returns the number of removed ones
+	PruneWebhookRequests(ctx context.Context, before time.Time) (int, error)
+}
+
+// States of webhook deliveries
+const (
+	DeliveryPending = "pending" // waiting for processing
+	DeliveryDone    = "done"    // events have been saved
+	DeliveryDead    = "dead"    // failed every attempt or can't be processed at all
+)
+
+// Delivery is a webhook request kept in the queue
+type Delivery struct {
+	ID          common.ObjectID `json:"id"`
+	Received    time.Time       `json:"received"`
+	Provider    string          `json:"provider"`
+	UUID        string          `json:"uuid,omitempty"` // ID assigned by VCS, if any
+	Header      http.Header     `json:"header"`
+	Payload     []byte          `json:"payload"`
+	State       string          `json:"state"`
+	Attempts    int             `json:"attempts"`     // attempts made so far
+	NextAttempt time.Time       `json:"next_attempt"` // pending deliveries are not processed before this time
+	LastError   string          `json:"last_error,omitempty"`
+}
+
+func (d *Delivery) GetObjectID() common.ObjectID      { return d.ID }
+func (d *Delivery) SetObjectID(value common.ObjectID) { d.ID = value }
+
+// Outcomes of webhook requests
+const (
+	RequestAccepted  = "accepted"  // delivery has been put into the inbox
+	RequestDuplicate = "duplicate" // delivery has been received before
+	RequestRejected  = "rejected"  // unknown provider, failed authentication or unreadable body
+	RequestFailed    = "failed"    // delivery could not be saved
+)
+
+// WebhookRequest is a raw webhook request kept in the log as it was received,
+// except for secret headers, so that it could be inspected and replayed later
+type WebhookRequest struct {
+	ID         common.ObjectID  `json:"id"`
+	Received   time.Time        `json:"received"`
+	RemoteAddr string           `json:"remote_addr"`
+	URL        string           `json:"url"`
+	Provider   string           `json:"provider,omitempty"` // empty if provider is unknown
+	Header     http.Header      `json:"header"`
+	Payload    []byte           `json:"payload"`
+	Status     int              `json:"status"` // HTTP status of the reply
+	Outcome    string           `json:"outcome"`
+	Error      string           `json:"error,omitempty"`
+	DeliveryID *common.ObjectID `json:"delivery_id,omitempty"` // delivery made of request, if any
+}
+
+func (r *WebhookRequest) GetObjectID() common.ObjectID      { return r.ID }
+func (r *WebhookRequest) SetObjectID(value common.ObjectID) { r.ID = value }
+
+// WebhookRequestFilter narrows the list of logged requests; empty fields match everything
+type WebhookRequestFilter struct {
+	Provider string
+	Outcome  string
+	Since    time.Time // inclusive
+}
+
+// EventReader provides access to the received VCS events; lists are ordered
+// from the newest objects to the oldest ones and are split into pages
+type EventReader interface {
+	// ListVCSProjects returns projects that events have been received for
+	ListVCSProjects(ctx context.Context, page Page) ([]vcs.Project, error)
+	// GetVCSProject returns project by ID
+	GetVCSProject(ctx context.Context, id common.ObjectID) (vcs.Project, error)
+	// ListPushEvents returns push events (with commits) matching the filter
+	ListPushEvents(ctx context.Context, filter *EventFilter, page Page) ([]StoredPushEvent, error)
+	// ListCommits returns commits matching the filter
+	ListCommits(ctx context.Context, filter *EventFilter, page Page) ([]vcs.Commit, error)
+	// GetAuthor returns commit author by name and email
+	GetAuthor(ctx context.Context, name, email string) (vcs.Author, error)
+}
+
+// StoredPushEvent is a push event read from the storage
+type StoredPushEvent interface {
+	vcs.PushEvent
+	GetReceived() time.Time // the time event was received at
+}
+
+// Page describes a part of the list to return
+type Page struct {
+	Before common.ObjectID // return objects older than this one (0 - from the newest one)
+	Limit  int
+}
+
+// EventFilter narrows down the lists of events and commits;
+// zero values of the fields are ignored
+type EventFilter struct {
+	ProjectID common.ObjectID
+	Author    string    // name or email of commit author
+	Since     time.Time // inclusive
+	Until     time.Time // exclusive
+	Ref       string    // e.g. refs/heads/master
+	Path      string    // path added, modified or removed by commit
 }
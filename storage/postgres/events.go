@@ -0,0 +1,342 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+
+	"github.com/vitalyisaev2/buildgraph/common"
+	"github.com/vitalyisaev2/buildgraph/storage"
+	"github.com/vitalyisaev2/buildgraph/vcs"
+)
+
+func (s *defaultStorage) ListVCSProjects(ctx context.Context, page storage.Page) ([]vcs.Project, error) {
+	var where conditions
+	where.addPage("id", page)
+
+	rows, err := s.db.QueryContext(
+		ctx,
+		`SELECT id, provider, namespace, name, http_url FROM vcs.projects`+where.String()+
+			` ORDER BY id DESC LIMIT `+where.arg(page.Limit),
+		where.args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []vcs.Project{}
+	for rows.Next() {
+		var p project
+		if err := scanProject(rows, &p); err != nil {
+			return nil, err
+		}
+		result = append(result, &p)
+	}
+	return result, rows.Err()
+}
+
+func (s *defaultStorage) GetVCSProject(ctx context.Context, id common.ObjectID) (vcs.Project, error) {
+	var p project
+	err := scanProject(
+		s.db.QueryRowContext(ctx, `SELECT id, provider, namespace, name, http_url FROM vcs.projects WHERE id = $1`, id),
+		&p,
+	)
+	if err == sql.ErrNoRows {
+		return nil, storage.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (s *defaultStorage) ListPushEvents(
+	ctx context.Context,
+	filter *storage.EventFilter,
+	page storage.Page,
+) ([]storage.StoredPushEvent, error) {
+
+	ex, err := s.makeExecutor(ctx, "list_push_events", readOnlyTransaction)
+	if err != nil {
+		return nil, err
+	}
+
+	var where conditions
+	where.addPage("e.id", page)
+	if filter.ProjectID != 0 {
+		where.add("e.project_id = %s", filter.ProjectID)
+	}
+	if !filter.Since.IsZero() {
+		where.add("e.time >= %s", filter.Since.UTC())
+	}
+	if !filter.Until.IsZero() {
+		where.add("e.time < %s", filter.Until.UTC())
+	}
+	if filter.Ref != "" {
+		where.add("e.ref = %s", filter.Ref)
+	}
+	if filter.Author != "" {
+		where.add(
+			`EXISTS (SELECT 1 FROM vcs.event_commits ec
+			JOIN vcs.commits c ON c.id = ec.commit_id JOIN vcs.authors a ON a.id = c.author_id
+			WHERE ec.event_id = e.id AND %s IN (a.name, a.email))`,
+			filter.Author,
+		)
+	}
+	if filter.Path != "" {
+		where.add(
+			`EXISTS (SELECT 1 FROM vcs.event_commits ec JOIN vcs.commits c ON c.id = ec.commit_id
+			WHERE ec.event_id = e.id AND %s = ANY(c.added || c.modified || c.removed))`,
+			filter.Path,
+		)
+	}
+
+	var events []*pushEvent
+	ex.addStep("list_events", func() error {
+		rows, err := ex.tx.QueryContext(
+			ctx,
+			`SELECT e.id, e.ref, e.time, p.id, p.provider, p.namespace, p.name, p.http_url
+			FROM vcs.events e JOIN vcs.projects p ON p.id = e.project_id`+where.String()+
+				` ORDER BY e.id DESC LIMIT `+where.arg(page.Limit),
+			where.args...,
+		)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var (
+				e             pushEvent
+				id, projectID common.ObjectID
+				ref           sql.NullString
+			)
+			e.project = &project{}
+			if err := rows.Scan(
+				&id, &ref, &e.received,
+				&projectID, &e.project.provider, &e.project.namespace, &e.project.name, &e.project.httpURL,
+			); err != nil {
+				return err
+			}
+			e.SetObjectID(id)
+			e.project.SetObjectID(projectID)
+			e.ref = ref.String
+			events = append(events, &e)
+		}
+		return rows.Err()
+	})
+
+	// attach commits to their events
+	ex.addStep("list_event_commits", func() error {
+		if len(events) == 0 {
+			return nil
+		}
+		ids := make([]int64, 0, len(events))
+		byID := make(map[common.ObjectID]*pushEvent, len(events))
+		for _, e := range events {
+			ids = append(ids, int64(e.GetObjectID()))
+			byID[e.GetObjectID()] = e
+		}
+
+		var where conditions
+		where.add("ec.event_id = ANY(%s)", pq.Array(ids))
+		commits, eventIDs, err := loadCommits(ctx, ex.tx, &where, 0, true)
+		if err != nil {
+			return err
+		}
+		for i, c := range commits {
+			if e, exists := byID[eventIDs[i]]; exists {
+				e.commits = append(e.commits, c)
+			}
+		}
+		return nil
+	})
+
+	if err := ex.finalize(); err != nil {
+		return nil, err
+	}
+
+	result := make([]storage.StoredPushEvent, 0, len(events))
+	for _, e := range events {
+		result = append(result, e)
+	}
+	return result, nil
+}
+
+func (s *defaultStorage) ListCommits(
+	ctx context.Context,
+	filter *storage.EventFilter,
+	page storage.Page,
+) ([]vcs.Commit, error) {
+
+	ex, err := s.makeExecutor(ctx, "list_commits", readOnlyTransaction)
+	if err != nil {
+		return nil, err
+	}
+
+	var where conditions
+	where.addPage("c.id", page)
+	if filter.ProjectID != 0 {
+		where.add("c.project_id = %s", filter.ProjectID)
+	}
+	if !filter.Since.IsZero() {
+		where.add("c.time >= %s", filter.Since.UTC())
+	}
+	if !filter.Until.IsZero() {
+		where.add("c.time < %s", filter.Until.UTC())
+	}
+	if filter.Ref != "" {
+		where.add(
+			`EXISTS (SELECT 1 FROM vcs.event_commits ec JOIN vcs.events e ON e.id = ec.event_id
+			WHERE ec.commit_id = c.id AND e.ref = %s)`,
+			filter.Ref,
+		)
+	}
+	if filter.Author != "" {
+		where.add("%s IN (a.name, a.email)", filter.Author)
+	}
+	if filter.Path != "" {
+		where.add("%s = ANY(c.added || c.modified || c.removed)", filter.Path)
+	}
+
+	var commits []*commit
+	ex.addStep("list_commits", func() (err error) {
+		commits, _, err = loadCommits(ctx, ex.tx, &where, page.Limit, false)
+		return err
+	})
+	if err := ex.finalize(); err != nil {
+		return nil, err
+	}
+
+	result := make([]vcs.Commit, 0, len(commits))
+	for _, c := range commits {
+		result = append(result, c)
+	}
+	return result, nil
+}
+
+func (s *defaultStorage) GetAuthor(ctx context.Context, name, email string) (vcs.Author, error) {
+	var (
+		a  author
+		id common.ObjectID
+	)
+	err := s.db.QueryRowContext(
+		ctx,
+		`SELECT id, name, email FROM vcs.authors WHERE name = $1 AND email = $2`,
+		name, email,
+	).Scan(&id, &a.name, &a.email)
+	if err == sql.ErrNoRows {
+		return nil, storage.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	a.SetObjectID(id)
+	return &a, nil
+}
+
+// loadCommits reads commits with their authors, newest first; if withEvents
+// is set, commit is read once for every event it was pushed within (the events
+// can be referred by 'ec' alias), and IDs of the events are returned as well
+func loadCommits(
+	ctx context.Context,
+	tx *sql.Tx,
+	where *conditions,
+	limit int,
+	withEvents bool,
+) ([]*commit, []common.ObjectID, error) {
+	eventColumn, eventJoin := "NULL::integer", ""
+	if withEvents {
+		eventColumn, eventJoin = "ec.event_id", " JOIN vcs.event_commits ec ON ec.commit_id = c.id"
+	}
+	query := `SELECT c.id, c.hash, c.message, c.time, c.url, c.added, c.modified, c.removed,
+		` + eventColumn + `, a.id, a.name, a.email
+		FROM vcs.commits c JOIN vcs.authors a ON a.id = c.author_id` + eventJoin + where.String() +
+		` ORDER BY c.id DESC`
+	if limit > 0 {
+		query += ` LIMIT ` + where.arg(limit)
+	}
+
+	rows, err := tx.QueryContext(ctx, query, where.args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var (
+		commits  []*commit
+		eventIDs []common.ObjectID
+	)
+	for rows.Next() {
+		var (
+			c                 commit
+			id, authorID      common.ObjectID
+			eventID           sql.NullInt64
+			email             sql.NullString
+			added, mod, remov pq.StringArray
+		)
+		c.author = &author{}
+		if err := rows.Scan(
+			&id, &c.hash, &c.message, &c.timestamp, &c.url, &added, &mod, &remov,
+			&eventID, &authorID, &c.author.name, &email,
+		); err != nil {
+			return nil, nil, err
+		}
+		c.SetObjectID(id)
+		c.author.SetObjectID(authorID)
+		c.author.email = email.String
+		c.added, c.modified, c.removed = added, mod, remov
+		commits = append(commits, &c)
+		eventIDs = append(eventIDs, common.ObjectID(eventID.Int64))
+	}
+	return commits, eventIDs, rows.Err()
+}
+
+// scanner is implemented by both *sql.Row and *sql.Rows
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanProject(row scanner, p *project) error {
+	var id common.ObjectID
+	if err := row.Scan(&id, &p.provider, &p.namespace, &p.name, &p.httpURL); err != nil {
+		return err
+	}
+	p.SetObjectID(id)
+	return nil
+}
+
+// conditions builds WHERE clause of a query with numbered placeholders
+type conditions struct {
+	clauses []string
+	args    []interface{}
+}
+
+// add appends clause; every %s in format is replaced with the placeholder of value
+func (c *conditions) add(format string, value interface{}) {
+	c.clauses = append(c.clauses, strings.Replace(format, "%s", c.arg(value), -1))
+}
+
+// addPage restricts the list to the objects older than the page cursor
+func (c *conditions) addPage(column string, page storage.Page) {
+	if page.Before > 0 {
+		c.add(column+" < %s", page.Before)
+	}
+}
+
+// arg registers value and returns its placeholder
+func (c *conditions) arg(value interface{}) string {
+	c.args = append(c.args, value)
+	return fmt.Sprintf("$%d", len(c.args))
+}
+
+func (c *conditions) String() string {
+	if len(c.clauses) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(c.clauses, " AND ")
+}
@@ -0,0 +1,168 @@
+package postgres
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vitalyisaev2/buildgraph/storage"
+	"github.com/vitalyisaev2/buildgraph/vcs"
+	"github.com/vitalyisaev2/buildgraph/vcs/github"
+	"github.com/vitalyisaev2/buildgraph/vcs/gitlab"
+)
+
+func TestConditions(t *testing.T) {
+	var where conditions
+	assert.Equal(t, "", where.String())
+
+	where.addPage("c.id", storage.Page{Limit: 10})
+	assert.Equal(t, "", where.String())
+
+	where.addPage("c.id", storage.Page{Before: 100, Limit: 10})
+	where.add("%s IN (a.name, a.email)", "john@example.com")
+	assert.Equal(t, " WHERE c.id < $1 AND $2 IN (a.name, a.email)", where.String())
+	assert.Equal(t, "$3", where.arg(10))
+	assert.Equal(t, []interface{}{100, "john@example.com", 10}, where.args)
+}
+
+func (s *storageSuite) TestListPushEvents() {
+	f, err := os.Open("../../vcs/gitlab/test/push.json")
+	s.Require().NoError(err)
+	defer f.Close()
+
+	event, err := gitlab.DecodeEvent(gitlab.PushHook, f)
+	s.Require().NoError(err)
+	push := event.(*gitlab.PushEvent)
+	s.Require().NoError(s.storage.SavePushEvent(s.ctx, push))
+
+	projectID := push.GetProject().GetObjectID()
+	project, err := s.storage.GetVCSProject(s.ctx, projectID)
+	s.Require().NoError(err)
+	s.Equal("namespace1", project.GetNamespace())
+
+	filter := &storage.EventFilter{
+		ProjectID: projectID,
+		Author:    "john@example.com",
+		Ref:       "refs/heads/master",
+		Path:      "CHANGELOG",
+		Since:     time.Now().Add(-time.Hour),
+	}
+	events, err := s.storage.ListPushEvents(s.ctx, filter, storage.Page{Limit: 1})
+	s.Require().NoError(err)
+	s.Require().Len(events, 1)
+	s.Equal(push.GetObjectID(), events[0].GetObjectID())
+	s.Len(events[0].GetCommits(), 1)
+
+	commits, err := s.storage.ListCommits(s.ctx, filter, storage.Page{Limit: 10})
+	s.Require().NoError(err)
+	s.NotEmpty(commits)
+
+	filter.Path = "nonexistent"
+	events, err = s.storage.ListPushEvents(s.ctx, filter, storage.Page{Limit: 1})
+	s.NoError(err)
+	s.Empty(events)
+}
+
+func (s *storageSuite) TestSavePushEventTwice() {
+	// the same commits are pushed into another branch
+	var events []*gitlab.PushEvent
+	for _, ref := range []string{"refs/heads/master", "refs/heads/feature"} {
+		f, err := os.Open("../../vcs/gitlab/test/push.json")
+		s.Require().NoError(err)
+		event, err := gitlab.DecodeEvent(gitlab.PushHook, f)
+		f.Close()
+		s.Require().NoError(err)
+
+		push := event.(*gitlab.PushEvent)
+		push.Ref = ref
+		s.Require().NoError(s.storage.SavePushEvent(s.ctx, push))
+		events = append(events, push)
+	}
+	s.Equal(events[0].GetCommits()[0].GetObjectID(), events[1].GetCommits()[0].GetObjectID())
+
+	filter := &storage.EventFilter{ProjectID: events[0].GetProject().GetObjectID(), Ref: "refs/heads/feature"}
+	stored, err := s.storage.ListPushEvents(s.ctx, filter, storage.Page{Limit: 1})
+	s.Require().NoError(err)
+	s.Require().Len(stored, 1)
+	s.Equal(events[1].GetObjectID(), stored[0].GetObjectID())
+	s.Len(stored[0].GetCommits(), len(events[1].GetCommits()))
+
+	commits, err := s.storage.ListCommits(s.ctx, filter, storage.Page{Limit: 10})
+	s.Require().NoError(err)
+	s.NotEmpty(commits)
+}
+
+func (s *storageSuite) TestSaveMirroredProjects() {
+	// the same repository is mirrored to Gitlab and Github
+	var projects []vcs.Project
+	for _, hook := range []struct {
+		path   string
+		decode func(string, io.Reader) (vcs.Event, error)
+		kind   string
+	}{
+		{"../../vcs/gitlab/test/push.json", gitlab.DecodeEvent, gitlab.PushHook},
+		{"../../vcs/github/test/push.json", github.DecodeEvent, github.PushHook},
+	} {
+		f, err := os.Open(hook.path)
+		s.Require().NoError(err)
+		event, err := hook.decode(hook.kind, f)
+		f.Close()
+		s.Require().NoError(err)
+
+		push := event.(vcs.PushEvent)
+		s.Require().NoError(s.storage.SavePushEvent(s.ctx, push))
+		projects = append(projects, push.GetProject())
+	}
+	s.Equal(projects[0].GetNamespace()+"/"+projects[0].GetName(), projects[1].GetNamespace()+"/"+projects[1].GetName())
+	s.NotEqual(projects[0].GetObjectID(), projects[1].GetObjectID())
+
+	stored, err := s.storage.GetVCSProject(s.ctx, projects[1].GetObjectID())
+	s.Require().NoError(err)
+	s.Equal(github.ProviderName, stored.GetProvider())
+}
+
+func (s *storageSuite) TestSaveDeliveryEvents() {
+	d := &storage.Delivery{
+		Provider: gitlab.ProviderName,
+		UUID:     fmt.Sprintf("%d", time.Now().UnixNano()),
+		Header:   http.Header{"X-Gitlab-Event": []string{gitlab.PushHook}},
+		Payload:  []byte(`{"object_kind": "push"}`),
+	}
+	_, err := s.storage.SaveDelivery(s.ctx, d)
+	s.Require().NoError(err)
+
+	// delivery carries pushes into two branches
+	ref := "refs/heads/delivery-" + d.UUID
+	var events []vcs.Event
+	for _, suffix := range []string{"-1", "-2"} {
+		f, err := os.Open("../../vcs/gitlab/test/push.json")
+		s.Require().NoError(err)
+		event, err := gitlab.DecodeEvent(gitlab.PushHook, f)
+		f.Close()
+		s.Require().NoError(err)
+		event.(*gitlab.PushEvent).Ref = ref + suffix
+		events = append(events, event)
+	}
+
+	// retried delivery must not save events for the second time
+	for _, expected := range []bool{true, false} {
+		saved, err := s.storage.SaveDeliveryEvents(s.ctx, d.ID, events)
+		s.Require().NoError(err)
+		s.Equal(expected, saved)
+	}
+
+	for _, event := range events {
+		filter := &storage.EventFilter{
+			ProjectID: event.GetProject().GetObjectID(),
+			Ref:       event.(*gitlab.PushEvent).Ref,
+		}
+		stored, err := s.storage.ListPushEvents(s.ctx, filter, storage.Page{Limit: 10})
+		s.Require().NoError(err)
+		s.Len(stored, 1)
+	}
+}
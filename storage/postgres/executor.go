@@ -2,27 +2,44 @@ package postgres
 
 import (
 	"database/sql"
+	"time"
 
 	"github.com/lib/pq"
 	"github.com/sirupsen/logrus"
 	"github.com/vitalyisaev2/buildgraph/common"
+	"github.com/vitalyisaev2/buildgraph/metrics"
 	"github.com/vitalyisaev2/buildgraph/vcs"
 )
 
+// pseudo step names reported when transaction itself fails
+const (
+	beginStep  = "begin"
+	commitStep = "commit"
+)
+
 type step func() error
 
+// namedStep is reported in logs and metrics when it fails
+type namedStep struct {
+	name string
+	f    step
+}
+
 type executor struct {
-	tx     *sql.Tx
-	logger *logrus.Logger
-	steps  []step
+	tx        *sql.Tx
+	logger    *logrus.Logger
+	operation string    // name of storage method executor was made for
+	started   time.Time // when transaction was opened
+	steps     []namedStep
+	stopped   bool // set by step to commit transaction without executing the rest of steps
 }
 
 func (ex *executor) saveProject(project vcs.Project) {
 	f := func() error {
 		_, err := ex.tx.Exec(
-			`INSERT INTO vcs.projects(name, namespace, http_url)
-			VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`,
-			project.GetName(), project.GetNamespace(), project.GetHTTPURL(),
+			`INSERT INTO vcs.projects(provider, name, namespace, http_url)
+			VALUES ($1, $2, $3, $4) ON CONFLICT DO NOTHING`,
+			project.GetProvider(), project.GetName(), project.GetNamespace(), project.GetHTTPURL(),
 		)
 		if err != nil {
 			return err
@@ -30,8 +47,8 @@ func (ex *executor) saveProject(project vcs.Project) {
 
 		var id common.ObjectID
 		err = ex.tx.QueryRow(
-			`SELECT id from vcs.projects WHERE name = $1 AND namespace = $2;`,
-			project.GetName(), project.GetNamespace()).Scan(&id)
+			`SELECT id from vcs.projects WHERE provider = $1 AND name = $2 AND namespace = $3;`,
+			project.GetProvider(), project.GetName(), project.GetNamespace()).Scan(&id)
 		if err != nil {
 			return err
 		}
@@ -41,15 +58,16 @@ func (ex *executor) saveProject(project vcs.Project) {
 		return nil
 	}
 
-	ex.addStep(f)
+	ex.addStep("save_project", f)
 }
 
 func (ex *executor) saveEvent(event vcs.PushEvent) {
 	f := func() error {
 		var id common.ObjectID
 		err := ex.tx.QueryRow(
-			`INSERT INTO vcs.events(project_id) VALUES ($1) RETURNING ID`,
+			`INSERT INTO vcs.events(project_id, ref) VALUES ($1, $2) RETURNING ID`,
 			event.GetProject().GetObjectID(),
+			event.GetRef(),
 		).Scan(&id)
 
 		if err != nil {
@@ -60,7 +78,7 @@ func (ex *executor) saveEvent(event vcs.PushEvent) {
 		return nil
 	}
 
-	ex.addStep(f)
+	ex.addStep("save_event", f)
 }
 
 func (ex *executor) saveCommit(
@@ -68,13 +86,13 @@ func (ex *executor) saveCommit(
 	event vcs.PushEvent) {
 
 	f := func() error {
-		var id common.ObjectID
-		err := ex.tx.QueryRow(
+		// commit may have been pushed before within another event
+		_, err := ex.tx.Exec(
 			`INSERT INTO vcs.commits(
 				hash, message, time, url,
 				added, modified, removed,
-				project_id, author_id, event_id)
-			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id`,
+				project_id, author_id)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) ON CONFLICT (hash, project_id) DO NOTHING`,
 			commit.GetHash(),
 			commit.GetMessage(),
 			pq.FormatTimestamp(commit.GetTimestamp()),
@@ -84,9 +102,23 @@ func (ex *executor) saveCommit(
 			pq.Array(commit.GetRemoved()),
 			event.GetProject().GetObjectID(),
 			commit.GetAuthor().GetObjectID(),
-			event.GetObjectID(),
-		).Scan(&id)
+		)
+		if err != nil {
+			return err
+		}
 
+		var id common.ObjectID
+		err = ex.tx.QueryRow(
+			`SELECT id FROM vcs.commits WHERE hash = $1 AND project_id = $2`,
+			commit.GetHash(), event.GetProject().GetObjectID()).Scan(&id)
+		if err != nil {
+			return err
+		}
+
+		_, err = ex.tx.Exec(
+			`INSERT INTO vcs.event_commits(event_id, commit_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`,
+			event.GetObjectID(), id,
+		)
 		if err != nil {
 			return err
 		}
@@ -95,7 +127,7 @@ func (ex *executor) saveCommit(
 		return nil
 	}
 
-	ex.addStep(f)
+	ex.addStep("save_commit", f)
 }
 
 func (ex *executor) saveAuthor(author vcs.Author) {
@@ -121,29 +153,50 @@ func (ex *executor) saveAuthor(author vcs.Author) {
 		return nil
 	}
 
-	ex.addStep(f)
+	ex.addStep("save_author", f)
 }
 
-func (ex *executor) addStep(f step) {
-	ex.steps = append(ex.steps, f)
+func (ex *executor) addStep(name string, f step) {
+	ex.steps = append(ex.steps, namedStep{name: name, f: f})
 }
 
-func (ex *executor) finalize() error {
-	var err error
+// finalize executes stored steps, than commits or rolls back transaction;
+// the error of the first failed step is returned
+func (ex *executor) finalize() (err error) {
+	var failed string
 
 	// either commit, or rollback on exit
 	defer func() {
+		defer func() {
+			metrics.StorageTransactionDuration.WithLabelValues(ex.operation).Observe(time.Since(ex.started).Seconds())
+			if err != nil {
+				metrics.StorageTransactionFailures.WithLabelValues(ex.operation, failed).Inc()
+			}
+		}()
+
 		if err != nil {
-			err = ex.tx.Rollback()
-		} else {
-			err = ex.tx.Commit()
+			if rollbackErr := ex.tx.Rollback(); rollbackErr != nil {
+				ex.logger.WithError(rollbackErr).Error("rollback error")
+			}
+			return
+		}
+		if err = ex.tx.Commit(); err != nil {
+			failed = commitStep
 		}
 	}()
 
 	// walks through stored steps and
 	for i, s := range ex.steps {
-		if err = s(); err != nil {
-			ex.logger.WithError(err).WithField("step", i).Error("transaction error")
+		if ex.stopped {
+			break
+		}
+		if err = s.f(); err != nil {
+			failed = s.name
+			ex.logger.WithError(err).WithFields(logrus.Fields{
+				"operation": ex.operation,
+				"step":      s.name,
+				"index":     i,
+			}).Error("transaction error")
 			break
 		}
 	}
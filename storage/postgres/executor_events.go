@@ -0,0 +1,165 @@
+package postgres
+
+import (
+	"fmt"
+
+	"github.com/vitalyisaev2/buildgraph/common"
+	"github.com/vitalyisaev2/buildgraph/vcs"
+)
+
+func (ex *executor) saveTagPushEvent(event vcs.TagPushEvent) {
+	f := func() error {
+		var id common.ObjectID
+		err := ex.tx.QueryRow(
+			`INSERT INTO vcs.tag_events(project_id, tag, commit)
+			VALUES ($1, $2, $3) RETURNING id`,
+			event.GetProject().GetObjectID(),
+			event.GetTag(),
+			nullString(event.GetCommit()),
+		).Scan(&id)
+		if err != nil {
+			return err
+		}
+
+		event.SetObjectID(id)
+		return nil
+	}
+
+	ex.addStep("save_tag_push_event", f)
+}
+
+func (ex *executor) saveMergeRequestEvent(event vcs.MergeRequestEvent) {
+	f := func() error {
+		var id common.ObjectID
+		err := ex.tx.QueryRow(
+			`INSERT INTO vcs.merge_request_events(
+				project_id, iid, title, action, state,
+				source_branch, target_branch, last_commit, url)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id`,
+			event.GetProject().GetObjectID(),
+			event.GetIID(),
+			event.GetTitle(),
+			nullString(event.GetAction()),
+			event.GetState(),
+			event.GetSourceBranch(),
+			event.GetTargetBranch(),
+			nullString(event.GetLastCommit()),
+			nullString(event.GetURL()),
+		).Scan(&id)
+		if err != nil {
+			return err
+		}
+
+		event.SetObjectID(id)
+		return nil
+	}
+
+	ex.addStep("save_merge_request_event", f)
+}
+
+func (ex *executor) savePipelineEvent(event vcs.PipelineEvent) {
+	f := func() error {
+		var id common.ObjectID
+		err := ex.tx.QueryRow(
+			`INSERT INTO ci.pipeline_events(project_id, pipeline_id, ref, commit, status)
+			VALUES ($1, $2, $3, $4, $5) RETURNING id`,
+			event.GetProject().GetObjectID(),
+			event.GetPipelineID(),
+			event.GetRef(),
+			event.GetCommit(),
+			event.GetStatus(),
+		).Scan(&id)
+		if err != nil {
+			return err
+		}
+
+		event.SetObjectID(id)
+		return nil
+	}
+
+	ex.addStep("save_pipeline_event", f)
+}
+
+func (ex *executor) saveJobEvent(event vcs.JobEvent) {
+	f := func() error {
+		var id common.ObjectID
+		err := ex.tx.QueryRow(
+			`INSERT INTO ci.job_events(
+				project_id, job_id, pipeline_id, name, stage, ref, commit, status)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`,
+			event.GetProject().GetObjectID(),
+			event.GetJobID(),
+			event.GetPipelineID(),
+			event.GetName(),
+			event.GetStage(),
+			event.GetRef(),
+			event.GetCommit(),
+			event.GetStatus(),
+		).Scan(&id)
+		if err != nil {
+			return err
+		}
+
+		event.SetObjectID(id)
+		return nil
+	}
+
+	ex.addStep("save_job_event", f)
+}
+
+// nullString turns empty strings into NULL values
+func nullString(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// claimDeliveryEvents marks events of the delivery as saved; if they
+// have been saved before, the rest of steps are not executed
+func (ex *executor) claimDeliveryEvents(deliveryID common.ObjectID, saved *bool) {
+	f := func() error {
+		result, err := ex.tx.Exec(
+			`INSERT INTO inbox.saved_events(delivery_id) VALUES ($1) ON CONFLICT DO NOTHING`,
+			deliveryID,
+		)
+		if err != nil {
+			return err
+		}
+		inserted, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		*saved = inserted > 0
+		ex.stopped = !*saved
+		return nil
+	}
+
+	ex.addStep("claim_delivery_events", f)
+}
+
+// saveAnyEvent saves repository or CI event along with its project
+func (ex *executor) saveAnyEvent(event vcs.Event) {
+	ex.saveProject(event.GetProject())
+
+	// JobEvent has to be checked before PipelineEvent,
+	// since it contains all the methods of the latter
+	switch e := event.(type) {
+	case vcs.PushEvent:
+		ex.saveEvent(e)
+		for _, commit := range e.GetCommits() {
+			ex.saveAuthor(commit.GetAuthor())
+			ex.saveCommit(commit, e)
+		}
+	case vcs.TagPushEvent:
+		ex.saveTagPushEvent(e)
+	case vcs.MergeRequestEvent:
+		ex.saveMergeRequestEvent(e)
+	case vcs.JobEvent:
+		ex.saveJobEvent(e)
+	case vcs.PipelineEvent:
+		ex.savePipelineEvent(e)
+	default:
+		ex.addStep("save_event", func() error { return fmt.Errorf("unexpected event type: %T", event) })
+	}
+}
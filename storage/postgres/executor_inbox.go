@@ -0,0 +1,215 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/vitalyisaev2/buildgraph/common"
+	"github.com/vitalyisaev2/buildgraph/storage"
+)
+
+func (ex *executor) saveDelivery(d *storage.Delivery, saved *bool) {
+	f := func() error {
+		header, err := json.Marshal(d.Header)
+		if err != nil {
+			return err
+		}
+
+		d.State, d.Attempts = storage.DeliveryPending, 0
+		err = ex.tx.QueryRow(
+			`INSERT INTO inbox.deliveries(provider, uuid, header, payload, state)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (provider, uuid) WHERE uuid IS NOT NULL DO NOTHING
+			RETURNING id, received, next_attempt`,
+			d.Provider, nullString(d.UUID), header, d.Payload, d.State,
+		).Scan(&d.ID, &d.Received, &d.NextAttempt)
+		if err != sql.ErrNoRows {
+			*saved = err == nil
+			return err
+		}
+
+		// delivery has been resent
+		return scanDelivery(
+			ex.tx.QueryRow(
+				`SELECT `+deliveryColumns+` FROM inbox.deliveries WHERE provider = $1 AND uuid = $2`,
+				d.Provider, d.UUID,
+			),
+			d,
+		)
+	}
+
+	ex.addStep("save_delivery", f)
+}
+
+func (ex *executor) claimDeliveries(limit int, lease time.Duration, result *[]*storage.Delivery) {
+	f := func() error {
+		// deliveries claimed concurrently by other workers are skipped
+		rows, err := ex.tx.Query(
+			`UPDATE inbox.deliveries SET
+				attempts = attempts + 1,
+				next_attempt = (now() AT TIME ZONE 'utc') + make_interval(secs => $2)
+			WHERE id IN (
+				SELECT id FROM inbox.deliveries
+				WHERE state = $3 AND next_attempt <= (now() AT TIME ZONE 'utc')
+				ORDER BY id LIMIT $1 FOR UPDATE SKIP LOCKED
+			) RETURNING `+deliveryColumns,
+			limit, lease.Seconds(), storage.DeliveryPending,
+		)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var d storage.Delivery
+			if err := scanDelivery(rows, &d); err != nil {
+				return err
+			}
+			*result = append(*result, &d)
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		// the oldest deliveries go first
+		sort.Slice(*result, func(i, j int) bool { return (*result)[i].ID < (*result)[j].ID })
+		return nil
+	}
+
+	ex.addStep("claim_deliveries", f)
+}
+
+func (ex *executor) completeDelivery(id common.ObjectID) {
+	f := func() error {
+		result, err := ex.tx.Exec(
+			`UPDATE inbox.deliveries SET state = $2, last_error = NULL WHERE id = $1`,
+			id, storage.DeliveryDone,
+		)
+		return checkAffected(result, err)
+	}
+
+	ex.addStep("complete_delivery", f)
+}
+
+func (ex *executor) postponeDelivery(id common.ObjectID, reason string, delay time.Duration) {
+	f := func() error {
+		result, err := ex.tx.Exec(
+			`UPDATE inbox.deliveries SET
+				last_error = $2,
+				next_attempt = (now() AT TIME ZONE 'utc') + make_interval(secs => $3)
+			WHERE id = $1`,
+			id, reason, delay.Seconds(),
+		)
+		return checkAffected(result, err)
+	}
+
+	ex.addStep("postpone_delivery", f)
+}
+
+func (ex *executor) buryDelivery(id common.ObjectID, reason string) {
+	f := func() error {
+		result, err := ex.tx.Exec(
+			`UPDATE inbox.deliveries SET state = $2, last_error = $3 WHERE id = $1`,
+			id, storage.DeliveryDead, reason,
+		)
+		return checkAffected(result, err)
+	}
+
+	ex.addStep("bury_delivery", f)
+}
+
+func (ex *executor) requeueDelivery(id common.ObjectID) {
+	f := func() error {
+		var state string
+		err := ex.tx.QueryRow(`SELECT state FROM inbox.deliveries WHERE id = $1 FOR UPDATE`, id).Scan(&state)
+		if err == sql.ErrNoRows {
+			return storage.ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if state != storage.DeliveryDead {
+			return &storage.ConflictError{Reason: fmt.Sprintf("delivery %d is %s, only dead deliveries can be requeued", id, state)}
+		}
+
+		_, err = ex.tx.Exec(
+			`UPDATE inbox.deliveries SET
+				state = $2, attempts = 0, next_attempt = (now() AT TIME ZONE 'utc')
+			WHERE id = $1`,
+			id, storage.DeliveryPending,
+		)
+		return err
+	}
+
+	ex.addStep("requeue_delivery", f)
+}
+
+func (ex *executor) saveWebhookRequest(r *storage.WebhookRequest) {
+	f := func() error {
+		header, err := json.Marshal(r.Header)
+		if err != nil {
+			return err
+		}
+
+		// payload of unreadable request may be missing at all
+		payload := r.Payload
+		if payload == nil {
+			payload = []byte{}
+		}
+		return ex.tx.QueryRow(
+			`INSERT INTO inbox.requests(
+				received, remote_addr, url, provider, header, payload, status, outcome, error, delivery_id
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id`,
+			r.Received.UTC(), r.RemoteAddr, r.URL, nullString(r.Provider), header, payload,
+			r.Status, r.Outcome, nullString(r.Error), r.DeliveryID,
+		).Scan(&r.ID)
+	}
+
+	ex.addStep("save_webhook_request", f)
+}
+
+func (ex *executor) pruneDeliveries(before time.Time, pruned *int) {
+	f := func() error {
+		result, err := ex.tx.Exec(
+			`UPDATE inbox.deliveries SET header = '{}', payload = ''
+			WHERE state = $1 AND received < $2 AND payload <> ''`,
+			storage.DeliveryDone, before.UTC(),
+		)
+		if err != nil {
+			return err
+		}
+		affected, err := result.RowsAffected()
+		*pruned = int(affected)
+		return err
+	}
+
+	ex.addStep("prune_deliveries", f)
+}
+
+func (ex *executor) pruneWebhookRequests(before time.Time, pruned *int) {
+	f := func() error {
+		result, err := ex.tx.Exec(`DELETE FROM inbox.requests WHERE received < $1`, before.UTC())
+		if err != nil {
+			return err
+		}
+		affected, err := result.RowsAffected()
+		*pruned = int(affected)
+		return err
+	}
+
+	ex.addStep("prune_webhook_requests", f)
+}
+
+// checkAffected turns update of missing row into ErrNotFound
+func checkAffected(result sql.Result, err error) error {
+	if err != nil {
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return storage.ErrNotFound
+	}
+	return nil
+}
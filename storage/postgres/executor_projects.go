@@ -0,0 +1,323 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/vitalyisaev2/buildgraph/config"
+	"github.com/vitalyisaev2/buildgraph/storage"
+)
+
+// queryer is implemented by both *sql.DB and *sql.Tx
+type queryer interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// loadProjects reads all descriptions and relations
+func loadProjects(q queryer) (*config.ProjectsConfig, error) {
+	descriptions, err := loadDescriptions(q)
+	if err != nil {
+		return nil, err
+	}
+	relations, err := loadRelations(q)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &config.ProjectsConfig{
+		Descriptions: descriptions,
+		Relations:    relations,
+	}
+	result.SetOrigin(projectsOrigin)
+	return result, nil
+}
+
+func loadDescriptions(q queryer) ([]*config.Description, error) {
+	rows, err := q.Query(`SELECT id, namespace, name, build FROM registry.projects ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*config.Description
+	for rows.Next() {
+		var (
+			d     config.Description
+			build []byte
+		)
+		if err := rows.Scan(&d.ID, &d.Namespace, &d.Name, &build); err != nil {
+			return nil, err
+		}
+		if build != nil {
+			if err := json.Unmarshal(build, &d.Build); err != nil {
+				return nil, fmt.Errorf("invalid build spec of project %s: %v", d.ID, err)
+			}
+		}
+		result = append(result, &d)
+	}
+	return result, rows.Err()
+}
+
+func loadRelations(q queryer) (map[string][]string, error) {
+	rows, err := q.Query(`SELECT dependency, dependent FROM registry.relations ORDER BY dependency, dependent`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[string][]string)
+	for rows.Next() {
+		var dependency, dependent string
+		if err := rows.Scan(&dependency, &dependent); err != nil {
+			return nil, err
+		}
+		result[dependency] = append(result[dependency], dependent)
+	}
+	return result, rows.Err()
+}
+
+// loadDescription returns description of the project or nil if it doesn't exist
+func loadDescription(q queryer, id string) (*config.Description, error) {
+	var (
+		d     = config.Description{ID: id}
+		build []byte
+	)
+	err := q.QueryRow(
+		`SELECT namespace, name, build FROM registry.projects WHERE id = $1`, id,
+	).Scan(&d.Namespace, &d.Name, &build)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if build != nil {
+		if err := json.Unmarshal(build, &d.Build); err != nil {
+			return nil, fmt.Errorf("invalid build spec of project %s: %v", d.ID, err)
+		}
+	}
+	return &d, nil
+}
+
+func (ex *executor) saveDescription(d *config.Description, actor string) {
+	f := func() error {
+		before, err := loadDescription(ex.tx, d.ID)
+		if err != nil {
+			return err
+		}
+
+		var build []byte
+		if d.Build != nil {
+			if build, err = json.Marshal(d.Build); err != nil {
+				return err
+			}
+		}
+
+		_, err = ex.tx.Exec(
+			`INSERT INTO registry.projects(id, namespace, name, build)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (id) DO UPDATE SET namespace = $2, name = $3, build = $4`,
+			d.ID, d.Namespace, d.Name, build,
+		)
+		if err != nil {
+			return err
+		}
+
+		action := "project.create"
+		if before != nil {
+			action = "project.update"
+		}
+		return ex.insertAuditRecord(actor, action, d.ID, before, d)
+	}
+
+	ex.addStep("save_description", f)
+}
+
+func (ex *executor) deleteDescription(id string, actor string) {
+	f := func() error {
+		before, err := loadDescription(ex.tx, id)
+		if err != nil {
+			return err
+		}
+		if before == nil {
+			return storage.ErrNotFound
+		}
+
+		if _, err := ex.tx.Exec(`DELETE FROM registry.projects WHERE id = $1`, id); err != nil {
+			return err
+		}
+		return ex.insertAuditRecord(actor, "project.delete", id, before, nil)
+	}
+
+	ex.addStep("delete_description", f)
+}
+
+func (ex *executor) saveRelation(dependency, dependent string, actor string) {
+	f := func() error {
+		for _, id := range []string{dependency, dependent} {
+			d, err := loadDescription(ex.tx, id)
+			if err != nil {
+				return err
+			}
+			if d == nil {
+				return &storage.ConflictError{Reason: fmt.Sprintf("project %s doesn't exist", id)}
+			}
+		}
+
+		result, err := ex.tx.Exec(
+			`INSERT INTO registry.relations(dependency, dependent) VALUES ($1, $2) ON CONFLICT DO NOTHING`,
+			dependency, dependent,
+		)
+		if err != nil {
+			return err
+		}
+		if inserted, _ := result.RowsAffected(); inserted == 0 {
+			return nil
+		}
+
+		relation := relationName(dependency, dependent)
+		return ex.insertAuditRecord(actor, "relation.create", relation, nil, relation)
+	}
+
+	ex.addStep("save_relation", f)
+}
+
+func (ex *executor) deleteRelation(dependency, dependent string, actor string) {
+	f := func() error {
+		result, err := ex.tx.Exec(
+			`DELETE FROM registry.relations WHERE dependency = $1 AND dependent = $2`,
+			dependency, dependent,
+		)
+		if err != nil {
+			return err
+		}
+		if deleted, _ := result.RowsAffected(); deleted == 0 {
+			return storage.ErrNotFound
+		}
+
+		relation := relationName(dependency, dependent)
+		return ex.insertAuditRecord(actor, "relation.delete", relation, relation, nil)
+	}
+
+	ex.addStep("delete_relation", f)
+}
+
+// importProjects replaces stored projects with the given ones;
+// in seed mode nothing is done if the storage already has projects
+func (ex *executor) importProjects(cfg *config.ProjectsConfig, actor string, imported *bool) {
+	f := func() error {
+		if cfg.Import != config.ImportOverwrite {
+			var count int
+			if err := ex.tx.QueryRow(`SELECT count(*) FROM registry.projects`).Scan(&count); err != nil {
+				return err
+			}
+			if count > 0 {
+				return nil
+			}
+		}
+
+		before, err := loadProjects(ex.tx)
+		if err != nil {
+			return err
+		}
+
+		// relations are removed in cascade
+		if _, err := ex.tx.Exec(`DELETE FROM registry.projects`); err != nil {
+			return err
+		}
+
+		for _, d := range cfg.Descriptions {
+			var build []byte
+			if d.Build != nil {
+				if build, err = json.Marshal(d.Build); err != nil {
+					return err
+				}
+			}
+			_, err := ex.tx.Exec(
+				`INSERT INTO registry.projects(id, namespace, name, build) VALUES ($1, $2, $3, $4)`,
+				d.ID, d.Namespace, d.Name, build,
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		for dependency, dependents := range cfg.Relations {
+			for _, dependent := range dependents {
+				_, err := ex.tx.Exec(
+					`INSERT INTO registry.relations(dependency, dependent) VALUES ($1, $2)`,
+					dependency, dependent,
+				)
+				if err != nil {
+					return fmt.Errorf("failed to import relation %s: %v", relationName(dependency, dependent), err)
+				}
+			}
+		}
+
+		*imported = true
+		return ex.insertAuditRecord(actor, "projects.import", "*", before, cfg)
+	}
+
+	ex.addStep("import_projects", f)
+}
+
+// checkProjectGraph makes sure that the changes made within
+// the transaction keep project graph acyclic
+func (ex *executor) checkProjectGraph() {
+	f := func() error {
+		cfg, err := loadProjects(ex.tx)
+		if err != nil {
+			return err
+		}
+		if err := cfg.Check(); err != nil {
+			return &storage.ConflictError{Reason: err.Error()}
+		}
+		return nil
+	}
+
+	ex.addStep("check_project_graph", f)
+}
+
+func (ex *executor) insertAuditRecord(actor, action, object string, before, after interface{}) error {
+	beforeData, err := marshalAuditState(before)
+	if err != nil {
+		return err
+	}
+	afterData, err := marshalAuditState(after)
+	if err != nil {
+		return err
+	}
+
+	_, err = ex.tx.Exec(
+		`INSERT INTO registry.audit(actor, action, object, before, after) VALUES ($1, $2, $3, $4, $5)`,
+		actor, action, object, beforeData, afterData,
+	)
+	return err
+}
+
+// marshalAuditState returns JSON representation of object state or nil if there is no state;
+// values of build environment often hold credentials, so they are kept out of the audit trail
+func marshalAuditState(state interface{}) ([]byte, error) {
+	switch s := state.(type) {
+	case *config.Description:
+		if s != nil {
+			state = s.Redacted()
+		}
+	case *config.ProjectsConfig:
+		if s != nil {
+			state = s.Redacted()
+		}
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil || string(data) == "null" {
+		return nil, err
+	}
+	return data, nil
+}
+
+func relationName(dependency, dependent string) string {
+	return fmt.Sprintf("%s -> %s", dependency, dependent)
+}
@@ -0,0 +1,50 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// table where migration tool keeps schema version
+const schemaVersionQuery = "SELECT version, dirty FROM schema_migrations"
+
+// CheckHealth makes sure that database is reachable and its schema is up to date
+func (s *defaultStorage) CheckHealth(ctx context.Context) error {
+	if err := s.db.PingContext(ctx); err != nil {
+		return fmt.Errorf("database is unreachable: %v", err)
+	}
+
+	var (
+		version uint
+		dirty   bool
+	)
+	if err := s.db.QueryRowContext(ctx, schemaVersionQuery).Scan(&version, &dirty); err != nil {
+		return fmt.Errorf("failed to get schema version: %v", err)
+	}
+	if dirty {
+		return fmt.Errorf("migration %d has not been completed", version)
+	}
+	if version != s.schemaVersion {
+		return fmt.Errorf("schema version is %d, while %d is expected", version, s.schemaVersion)
+	}
+	return nil
+}
+
+// latestMigration returns the highest version among migration files
+// named like '0001_init_schema.up.sql'
+func latestMigration(names []string) (uint, error) {
+	var latest uint
+	for _, name := range names {
+		prefix := strings.SplitN(name, "_", 2)[0]
+		version, err := strconv.ParseUint(prefix, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("unexpected migration file name: %s", name)
+		}
+		if uint(version) > latest {
+			latest = uint(version)
+		}
+	}
+	return latest, nil
+}
@@ -0,0 +1,30 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vitalyisaev2/buildgraph/storage/postgres/migrations"
+)
+
+func TestLatestMigration(t *testing.T) {
+	version, err := latestMigration([]string{
+		"0002_tables.down.sql",
+		"0010_extra.up.sql",
+		"0001_init_schema.up.sql",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, uint(10), version)
+
+	_, err = latestMigration([]string{"init_schema.up.sql"})
+	assert.Error(t, err)
+
+	version, err = latestMigration(migrations.AssetNames())
+	assert.NoError(t, err)
+	assert.NotZero(t, version)
+}
+
+func (s *storageSuite) TestCheckHealth() {
+	s.Assert().NoError(s.storage.CheckHealth(s.ctx))
+}
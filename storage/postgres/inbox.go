@@ -0,0 +1,264 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/vitalyisaev2/buildgraph/common"
+	"github.com/vitalyisaev2/buildgraph/storage"
+)
+
+// columns of inbox.deliveries in the order scanDelivery expects them
+const deliveryColumns = `id, received, provider, uuid, header, payload, state, attempts, next_attempt, last_error`
+
+// columns of inbox.requests in the order scanWebhookRequest expects them
+const webhookRequestColumns = `id, received, remote_addr, url, provider, header, payload, status, outcome, error, delivery_id`
+
+func (s *defaultStorage) SaveDelivery(ctx context.Context, d *storage.Delivery) (bool, error) {
+	ex, err := s.makeExecutor(ctx, "save_delivery", nil)
+	if err != nil {
+		return false, err
+	}
+
+	var saved bool
+	ex.saveDelivery(d, &saved)
+	if err := ex.finalize(); err != nil {
+		return false, err
+	}
+	return saved, nil
+}
+
+func (s *defaultStorage) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*storage.Delivery, error) {
+	ex, err := s.makeExecutor(ctx, "claim_deliveries", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*storage.Delivery
+	ex.claimDeliveries(limit, lease, &result)
+	if err := ex.finalize(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *defaultStorage) CompleteDelivery(ctx context.Context, id common.ObjectID) error {
+	ex, err := s.makeExecutor(ctx, "complete_delivery", nil)
+	if err != nil {
+		return err
+	}
+
+	ex.completeDelivery(id)
+	return ex.finalize()
+}
+
+func (s *defaultStorage) PostponeDelivery(ctx context.Context, id common.ObjectID, reason string, delay time.Duration) error {
+	ex, err := s.makeExecutor(ctx, "postpone_delivery", nil)
+	if err != nil {
+		return err
+	}
+
+	ex.postponeDelivery(id, reason, delay)
+	return ex.finalize()
+}
+
+func (s *defaultStorage) BuryDelivery(ctx context.Context, id common.ObjectID, reason string) error {
+	ex, err := s.makeExecutor(ctx, "bury_delivery", nil)
+	if err != nil {
+		return err
+	}
+
+	ex.buryDelivery(id, reason)
+	return ex.finalize()
+}
+
+func (s *defaultStorage) RequeueDelivery(ctx context.Context, id common.ObjectID) error {
+	ex, err := s.makeExecutor(ctx, "requeue_delivery", nil)
+	if err != nil {
+		return err
+	}
+
+	ex.requeueDelivery(id)
+	return ex.finalize()
+}
+
+func (s *defaultStorage) ListDeliveries(ctx context.Context, state string, page storage.Page) ([]*storage.Delivery, error) {
+	var where conditions
+	where.addPage("id", page)
+	if state != "" {
+		where.add("state = %s", state)
+	}
+
+	rows, err := s.db.QueryContext(
+		ctx,
+		`SELECT `+deliveryColumns+` FROM inbox.deliveries`+where.String()+
+			` ORDER BY id DESC LIMIT `+where.arg(page.Limit),
+		where.args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []*storage.Delivery{}
+	for rows.Next() {
+		var d storage.Delivery
+		if err := scanDelivery(rows, &d); err != nil {
+			return nil, err
+		}
+		result = append(result, &d)
+	}
+	return result, rows.Err()
+}
+
+func (s *defaultStorage) GetDelivery(ctx context.Context, id common.ObjectID) (*storage.Delivery, error) {
+	var d storage.Delivery
+	err := scanDelivery(
+		s.db.QueryRowContext(ctx, `SELECT `+deliveryColumns+` FROM inbox.deliveries WHERE id = $1`, id),
+		&d,
+	)
+	if err == sql.ErrNoRows {
+		return nil, storage.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+func (s *defaultStorage) CountDeliveries(ctx context.Context, state string) (int, error) {
+	var count int
+	err := s.db.QueryRowContext(
+		ctx, `SELECT count(*) FROM inbox.deliveries WHERE state = $1`, state,
+	).Scan(&count)
+	return count, err
+}
+
+func (s *defaultStorage) SaveWebhookRequest(ctx context.Context, r *storage.WebhookRequest) error {
+	ex, err := s.makeExecutor(ctx, "save_webhook_request", nil)
+	if err != nil {
+		return err
+	}
+
+	ex.saveWebhookRequest(r)
+	return ex.finalize()
+}
+
+func (s *defaultStorage) ListWebhookRequests(
+	ctx context.Context,
+	filter *storage.WebhookRequestFilter,
+	page storage.Page,
+) ([]*storage.WebhookRequest, error) {
+	var where conditions
+	where.addPage("id", page)
+	if filter.Provider != "" {
+		where.add("provider = %s", filter.Provider)
+	}
+	if filter.Outcome != "" {
+		where.add("outcome = %s", filter.Outcome)
+	}
+	if !filter.Since.IsZero() {
+		where.add("received >= %s", filter.Since.UTC())
+	}
+
+	rows, err := s.db.QueryContext(
+		ctx,
+		`SELECT `+webhookRequestColumns+` FROM inbox.requests`+where.String()+
+			` ORDER BY id DESC LIMIT `+where.arg(page.Limit),
+		where.args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []*storage.WebhookRequest{}
+	for rows.Next() {
+		var r storage.WebhookRequest
+		if err := scanWebhookRequest(rows, &r); err != nil {
+			return nil, err
+		}
+		result = append(result, &r)
+	}
+	return result, rows.Err()
+}
+
+func (s *defaultStorage) GetWebhookRequest(ctx context.Context, id common.ObjectID) (*storage.WebhookRequest, error) {
+	var r storage.WebhookRequest
+	err := scanWebhookRequest(
+		s.db.QueryRowContext(ctx, `SELECT `+webhookRequestColumns+` FROM inbox.requests WHERE id = $1`, id),
+		&r,
+	)
+	if err == sql.ErrNoRows {
+		return nil, storage.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+func (s *defaultStorage) PruneDeliveries(ctx context.Context, before time.Time) (int, error) {
+	ex, err := s.makeExecutor(ctx, "prune_deliveries", nil)
+	if err != nil {
+		return 0, err
+	}
+
+	var pruned int
+	ex.pruneDeliveries(before, &pruned)
+	if err := ex.finalize(); err != nil {
+		return 0, err
+	}
+	return pruned, nil
+}
+
+func (s *defaultStorage) PruneWebhookRequests(ctx context.Context, before time.Time) (int, error) {
+	ex, err := s.makeExecutor(ctx, "prune_webhook_requests", nil)
+	if err != nil {
+		return 0, err
+	}
+
+	var pruned int
+	ex.pruneWebhookRequests(before, &pruned)
+	if err := ex.finalize(); err != nil {
+		return 0, err
+	}
+	return pruned, nil
+}
+
+func scanDelivery(row scanner, d *storage.Delivery) error {
+	var (
+		header          []byte
+		uuid, lastError sql.NullString
+	)
+	if err := row.Scan(
+		&d.ID, &d.Received, &d.Provider, &uuid, &header, &d.Payload,
+		&d.State, &d.Attempts, &d.NextAttempt, &lastError,
+	); err != nil {
+		return err
+	}
+	d.UUID, d.LastError = uuid.String, lastError.String
+	return json.Unmarshal(header, &d.Header)
+}
+
+func scanWebhookRequest(row scanner, r *storage.WebhookRequest) error {
+	var (
+		header               []byte
+		provider, errMessage sql.NullString
+		deliveryID           sql.NullInt64
+	)
+	if err := row.Scan(
+		&r.ID, &r.Received, &r.RemoteAddr, &r.URL, &provider, &header, &r.Payload,
+		&r.Status, &r.Outcome, &errMessage, &deliveryID,
+	); err != nil {
+		return err
+	}
+	r.Provider, r.Error = provider.String, errMessage.String
+	if deliveryID.Valid {
+		id := common.ObjectID(deliveryID.Int64)
+		r.DeliveryID = &id
+	}
+	return json.Unmarshal(header, &r.Header)
+}
@@ -0,0 +1,131 @@
+package postgres
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/vitalyisaev2/buildgraph/storage"
+)
+
+func (s *storageSuite) TestDeliveries() {
+	uuid := fmt.Sprintf("%d", time.Now().UnixNano())
+	d := &storage.Delivery{
+		Provider: "gitlab",
+		UUID:     uuid,
+		Header:   http.Header{"X-Gitlab-Event": []string{"Push Hook"}},
+		Payload:  []byte(`{"object_kind": "push"}`),
+	}
+	saved, err := s.storage.SaveDelivery(s.ctx, d)
+	s.Require().NoError(err)
+	s.True(saved)
+	s.NotZero(d.ID)
+
+	resent := &storage.Delivery{Provider: "gitlab", UUID: uuid, Header: http.Header{}, Payload: []byte(`{}`)}
+	saved, err = s.storage.SaveDelivery(s.ctx, resent)
+	s.Require().NoError(err)
+	s.False(saved)
+	s.Equal(d.ID, resent.ID)
+	s.Equal(d.Payload, resent.Payload)
+
+	claimed, err := s.storage.ClaimDeliveries(s.ctx, 100, time.Minute)
+	s.Require().NoError(err)
+	var found *storage.Delivery
+	for _, c := range claimed {
+		if c.ID == d.ID {
+			found = c
+		}
+	}
+	s.Require().NotNil(found)
+	s.Equal(1, found.Attempts)
+	s.Equal(d.Header, found.Header)
+	s.Equal(d.Payload, found.Payload)
+
+	// claimed delivery is hidden from other workers
+	claimed, err = s.storage.ClaimDeliveries(s.ctx, 100, time.Minute)
+	s.Require().NoError(err)
+	for _, c := range claimed {
+		s.NotEqual(d.ID, c.ID)
+	}
+
+	s.Require().Error(s.storage.RequeueDelivery(s.ctx, d.ID))
+	s.Require().NoError(s.storage.BuryDelivery(s.ctx, d.ID, "invalid payload"))
+	dead, err := s.storage.ListDeliveries(s.ctx, storage.DeliveryDead, storage.Page{Limit: 1})
+	s.Require().NoError(err)
+	s.Require().Len(dead, 1)
+	s.Equal(d.ID, dead[0].ID)
+	s.Equal("invalid payload", dead[0].LastError)
+
+	s.Require().NoError(s.storage.RequeueDelivery(s.ctx, d.ID))
+	s.Require().NoError(s.storage.CompleteDelivery(s.ctx, d.ID))
+	stored, err := s.storage.GetDelivery(s.ctx, d.ID)
+	s.Require().NoError(err)
+	s.Equal(storage.DeliveryDone, stored.State)
+	s.Equal(0, stored.Attempts)
+
+	s.Equal(storage.ErrNotFound, s.storage.CompleteDelivery(s.ctx, -1))
+
+	// payload of expired delivery is removed, but it is still recognized when resent
+	pruned, err := s.storage.PruneDeliveries(s.ctx, time.Now().Add(time.Minute))
+	s.Require().NoError(err)
+	s.True(pruned >= 1)
+	stored, err = s.storage.GetDelivery(s.ctx, d.ID)
+	s.Require().NoError(err)
+	s.Empty(stored.Payload)
+	s.Empty(stored.Header)
+	saved, err = s.storage.SaveDelivery(s.ctx, resent)
+	s.Require().NoError(err)
+	s.False(saved)
+	s.Equal(d.ID, resent.ID)
+}
+
+func (s *storageSuite) TestWebhookRequests() {
+	d := &storage.Delivery{Provider: "gitlab", Header: http.Header{}, Payload: []byte(`{}`)}
+	_, err := s.storage.SaveDelivery(s.ctx, d)
+	s.Require().NoError(err)
+
+	accepted := &storage.WebhookRequest{
+		Received:   time.Now(),
+		RemoteAddr: "127.0.0.1:41234",
+		URL:        "/vcs/gitlab/events",
+		Provider:   "gitlab",
+		Header:     http.Header{"X-Gitlab-Event": []string{"Push Hook"}},
+		Payload:    []byte(`{"object_kind": "push"}`),
+		Status:     202,
+		Outcome:    storage.RequestAccepted,
+		DeliveryID: &d.ID,
+	}
+	rejected := &storage.WebhookRequest{
+		Received:   time.Now(),
+		RemoteAddr: "127.0.0.1:41235",
+		URL:        "/hooks",
+		Header:     http.Header{},
+		Status:     400,
+		Outcome:    storage.RequestRejected,
+		Error:      "unable to detect VCS provider by request headers",
+	}
+	s.Require().NoError(s.storage.SaveWebhookRequest(s.ctx, accepted))
+	s.Require().NoError(s.storage.SaveWebhookRequest(s.ctx, rejected))
+	s.NotZero(accepted.ID)
+
+	stored, err := s.storage.GetWebhookRequest(s.ctx, accepted.ID)
+	s.Require().NoError(err)
+	s.Equal(accepted.Header, stored.Header)
+	s.Equal(accepted.Payload, stored.Payload)
+	s.Require().NotNil(stored.DeliveryID)
+	s.Equal(d.ID, *stored.DeliveryID)
+
+	filter := &storage.WebhookRequestFilter{Outcome: storage.RequestRejected, Since: accepted.Received.Add(-time.Minute)}
+	requests, err := s.storage.ListWebhookRequests(s.ctx, filter, storage.Page{Limit: 1})
+	s.Require().NoError(err)
+	s.Require().Len(requests, 1)
+	s.Equal(rejected.ID, requests[0].ID)
+	s.Equal("", requests[0].Provider)
+	s.Nil(requests[0].DeliveryID)
+
+	pruned, err := s.storage.PruneWebhookRequests(s.ctx, time.Now().Add(time.Minute))
+	s.Require().NoError(err)
+	s.True(pruned >= 2)
+	_, err = s.storage.GetWebhookRequest(s.ctx, accepted.ID)
+	s.Equal(storage.ErrNotFound, err)
+}
@@ -0,0 +1 @@
+DROP SCHEMA registry CASCADE;
@@ -0,0 +1,24 @@
+CREATE SCHEMA registry;
+
+CREATE TABLE registry.projects (
+    id TEXT PRIMARY KEY,
+    namespace TEXT NOT NULL,
+    name TEXT NOT NULL,
+    build JSONB
+);
+
+CREATE TABLE registry.relations (
+    dependency TEXT NOT NULL REFERENCES registry.projects(id) ON DELETE CASCADE,
+    dependent TEXT NOT NULL REFERENCES registry.projects(id) ON DELETE CASCADE,
+    PRIMARY KEY (dependency, dependent)
+);
+
+CREATE TABLE registry.audit (
+    id SERIAL PRIMARY KEY,
+    time TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'utc'),
+    actor TEXT NOT NULL,
+    action TEXT NOT NULL,
+    object TEXT NOT NULL,
+    before JSONB,
+    after JSONB
+);
@@ -0,0 +1,5 @@
+DROP TABLE ci.job_events CASCADE;
+DROP TABLE ci.pipeline_events CASCADE;
+DROP TABLE vcs.merge_request_events CASCADE;
+DROP TABLE vcs.tag_events CASCADE;
+ALTER TABLE vcs.events DROP COLUMN ref;
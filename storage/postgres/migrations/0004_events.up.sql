@@ -0,0 +1,46 @@
+ALTER TABLE vcs.events ADD COLUMN ref TEXT;
+
+CREATE TABLE vcs.tag_events (
+    id SERIAL PRIMARY KEY,
+    time TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'utc'),
+    project_id INTEGER REFERENCES vcs.projects(id),
+    tag TEXT NOT NULL,
+    commit TEXT
+);
+
+CREATE TABLE vcs.merge_request_events (
+    id SERIAL PRIMARY KEY,
+    time TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'utc'),
+    project_id INTEGER REFERENCES vcs.projects(id),
+    iid INTEGER NOT NULL,
+    title TEXT NOT NULL,
+    action TEXT,
+    state TEXT NOT NULL,
+    source_branch TEXT NOT NULL,
+    target_branch TEXT NOT NULL,
+    last_commit TEXT,
+    url TEXT
+);
+
+CREATE TABLE ci.pipeline_events (
+    id SERIAL PRIMARY KEY,
+    time TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'utc'),
+    project_id INTEGER REFERENCES vcs.projects(id),
+    pipeline_id INTEGER NOT NULL,
+    ref TEXT NOT NULL,
+    commit TEXT NOT NULL,
+    status TEXT NOT NULL
+);
+
+CREATE TABLE ci.job_events (
+    id SERIAL PRIMARY KEY,
+    time TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'utc'),
+    project_id INTEGER REFERENCES vcs.projects(id),
+    job_id INTEGER NOT NULL,
+    pipeline_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    stage TEXT NOT NULL,
+    ref TEXT NOT NULL,
+    commit TEXT NOT NULL,
+    status TEXT NOT NULL
+);
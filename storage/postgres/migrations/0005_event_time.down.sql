@@ -0,0 +1,4 @@
+DROP INDEX vcs.commits_event_id_idx;
+DROP INDEX vcs.commits_project_id_idx;
+DROP INDEX vcs.events_project_id_idx;
+ALTER TABLE vcs.events DROP COLUMN time;
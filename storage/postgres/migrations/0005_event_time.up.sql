@@ -0,0 +1,5 @@
+ALTER TABLE vcs.events ADD COLUMN time TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'utc');
+
+CREATE INDEX events_project_id_idx ON vcs.events(project_id, id);
+CREATE INDEX commits_project_id_idx ON vcs.commits(project_id, id);
+CREATE INDEX commits_event_id_idx ON vcs.commits(event_id);
@@ -0,0 +1 @@
+DROP SCHEMA inbox CASCADE;
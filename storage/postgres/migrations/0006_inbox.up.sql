@@ -0,0 +1,16 @@
+CREATE SCHEMA inbox;
+
+CREATE TABLE inbox.deliveries (
+    id SERIAL PRIMARY KEY,
+    received TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'utc'),
+    provider TEXT NOT NULL,
+    header JSONB NOT NULL,
+    payload BYTEA NOT NULL,
+    state TEXT NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'utc'),
+    last_error TEXT
+);
+
+-- processed deliveries are the majority, but they are never looked up by state
+CREATE INDEX deliveries_state_idx ON inbox.deliveries(state, next_attempt) WHERE state <> 'done';
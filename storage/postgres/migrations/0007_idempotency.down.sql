@@ -0,0 +1,9 @@
+ALTER TABLE vcs.commits ADD COLUMN event_id INTEGER REFERENCES vcs.events(id);
+UPDATE vcs.commits c SET event_id = (
+    SELECT min(ec.event_id) FROM vcs.event_commits ec WHERE ec.commit_id = c.id
+);
+CREATE INDEX commits_event_id_idx ON vcs.commits(event_id);
+DROP TABLE vcs.event_commits;
+
+DROP INDEX inbox.deliveries_uuid_idx;
+ALTER TABLE inbox.deliveries DROP COLUMN uuid;
@@ -0,0 +1,17 @@
+-- deliveries resent by VCS keep their UUID
+ALTER TABLE inbox.deliveries ADD COLUMN uuid TEXT;
+CREATE UNIQUE INDEX deliveries_uuid_idx ON inbox.deliveries(provider, uuid) WHERE uuid IS NOT NULL;
+
+-- the same commit may be pushed several times (e.g. into new branch)
+CREATE TABLE vcs.event_commits (
+    event_id INTEGER NOT NULL REFERENCES vcs.events(id) ON DELETE CASCADE,
+    commit_id INTEGER NOT NULL REFERENCES vcs.commits(id) ON DELETE CASCADE,
+    PRIMARY KEY (event_id, commit_id)
+);
+CREATE INDEX event_commits_commit_id_idx ON vcs.event_commits(commit_id);
+
+INSERT INTO vcs.event_commits(event_id, commit_id)
+SELECT event_id, id FROM vcs.commits WHERE event_id IS NOT NULL;
+
+DROP INDEX vcs.commits_event_id_idx;
+ALTER TABLE vcs.commits DROP COLUMN event_id;
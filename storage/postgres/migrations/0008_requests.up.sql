@@ -0,0 +1,17 @@
+-- raw webhook requests as they were received, including rejected ones
+CREATE TABLE inbox.requests (
+    id SERIAL PRIMARY KEY,
+    received TIMESTAMP NOT NULL,
+    remote_addr TEXT NOT NULL,
+    url TEXT NOT NULL,
+    provider TEXT,
+    header JSONB NOT NULL,
+    payload BYTEA NOT NULL,
+    status INTEGER NOT NULL,
+    outcome TEXT NOT NULL,
+    error TEXT,
+    delivery_id INTEGER REFERENCES inbox.deliveries(id) ON DELETE SET NULL
+);
+
+-- the log is pruned by receive time
+CREATE INDEX requests_received_idx ON inbox.requests(received);
@@ -0,0 +1 @@
+-- removed secrets can't be restored
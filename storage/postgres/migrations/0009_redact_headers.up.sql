@@ -0,0 +1,5 @@
+-- secrets are no longer saved with webhook headers
+UPDATE inbox.requests
+SET header = header - 'X-Gitlab-Token' - 'Authorization' - 'Proxy-Authorization' - 'Cookie';
+UPDATE inbox.deliveries
+SET header = header - 'X-Gitlab-Token' - 'Authorization' - 'Proxy-Authorization' - 'Cookie';
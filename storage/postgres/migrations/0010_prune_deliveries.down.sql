@@ -0,0 +1 @@
+DROP INDEX inbox.deliveries_prune_idx;
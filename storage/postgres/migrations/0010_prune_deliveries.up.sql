@@ -0,0 +1,2 @@
+-- payloads of processed deliveries are pruned by receive time
+CREATE INDEX deliveries_prune_idx ON inbox.deliveries(received) WHERE state = 'done' AND payload <> '';
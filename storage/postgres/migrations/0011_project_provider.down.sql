@@ -0,0 +1,3 @@
+ALTER TABLE vcs.projects DROP CONSTRAINT projects_provider_namespace_name_key;
+ALTER TABLE vcs.projects ADD CONSTRAINT projects_namespace_name_key UNIQUE (namespace, name);
+ALTER TABLE vcs.projects DROP COLUMN provider;
@@ -0,0 +1,6 @@
+-- the same path may belong to projects of several VCS (e.g. mirrors);
+-- projects saved before were received from Gitlab
+ALTER TABLE vcs.projects ADD COLUMN provider TEXT NOT NULL DEFAULT 'gitlab';
+ALTER TABLE vcs.projects ALTER COLUMN provider DROP DEFAULT;
+ALTER TABLE vcs.projects DROP CONSTRAINT projects_namespace_name_key;
+ALTER TABLE vcs.projects ADD CONSTRAINT projects_provider_namespace_name_key UNIQUE (provider, namespace, name);
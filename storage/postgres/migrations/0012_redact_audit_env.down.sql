@@ -0,0 +1 @@
+-- redacted values can't be restored
@@ -0,0 +1,27 @@
+-- values of build environment are no longer saved in the audit trail;
+-- they are redacted the same way in snapshots of both single projects
+-- and whole imports
+CREATE FUNCTION pg_temp.redact_env(description JSONB) RETURNS JSONB AS $$
+    SELECT CASE WHEN jsonb_typeof(description->'build'->'env') = 'object' THEN
+        jsonb_set(description, '{build,env}', (
+            SELECT coalesce(
+                jsonb_object_agg(key, CASE WHEN value = '""' THEN value ELSE '"<redacted>"' END),
+                '{}'
+            )
+            FROM jsonb_each(description->'build'->'env')
+        ))
+    ELSE description END
+$$ LANGUAGE SQL;
+
+CREATE FUNCTION pg_temp.redact_state(state JSONB) RETURNS JSONB AS $$
+    SELECT CASE WHEN jsonb_typeof(state->'descriptions') = 'array' THEN
+        jsonb_set(state, '{descriptions}', (
+            SELECT coalesce(jsonb_agg(pg_temp.redact_env(d) ORDER BY i), '[]')
+            FROM jsonb_array_elements(state->'descriptions') WITH ORDINALITY AS e(d, i)
+        ))
+    ELSE pg_temp.redact_env(state) END
+$$ LANGUAGE SQL;
+
+UPDATE registry.audit
+SET before = pg_temp.redact_state(before), after = pg_temp.redact_state(after)
+WHERE action IN ('project.create', 'project.update', 'project.delete', 'projects.import');
@@ -0,0 +1,6 @@
+-- events of delivery are saved once, even if delivery is processed again
+-- (e.g. when its result could not be saved)
+CREATE TABLE inbox.saved_events (
+    delivery_id INTEGER PRIMARY KEY REFERENCES inbox.deliveries(id) ON DELETE CASCADE,
+    saved TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'utc')
+);
@@ -4,7 +4,28 @@
 // 0001_init_schema.up.sql
 // 0002_tables.down.sql
 // 0002_tables.up.sql
-// bindata.go
+// 0003_projects.down.sql
+// 0003_projects.up.sql
+// 0004_events.down.sql
+// 0004_events.up.sql
+// 0005_event_time.down.sql
+// 0005_event_time.up.sql
+// 0006_inbox.down.sql
+// 0006_inbox.up.sql
+// 0007_idempotency.down.sql
+// 0007_idempotency.up.sql
+// 0008_requests.down.sql
+// 0008_requests.up.sql
+// 0009_redact_headers.down.sql
+// 0009_redact_headers.up.sql
+// 0010_prune_deliveries.down.sql
+// 0010_prune_deliveries.up.sql
+// 0011_project_provider.down.sql
+// 0011_project_provider.up.sql
+// 0012_redact_audit_env.down.sql
+// 0012_redact_audit_env.up.sql
+// 0013_delivery_events.down.sql
+// 0013_delivery_events.up.sql
 // DO NOT EDIT!
 
 package migrations
@@ -72,7 +93,7 @@ func (fi bindataFileInfo) Sys() interface{} {
 	return nil
 }
 
-var __0001_init_schemaDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\x09\xf2\x0f\x50\x08\x76\xf6\x70\xf5\x75\x54\x28\xcf\x2f\xca\x4e\xcb\xc9\x2f\xb7\xe6\x42\x16\x2d\x4b\x2e\x46\x15\x48\xce\xb4\xe6\x02\x04\x00\x00\xff\xff\x06\xc3\x14\xe8\x37\x00\x00\x00")
+var __0001_init_schemaDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x37\x00\xc8\xff\x44\x52\x4f\x50\x20\x53\x43\x48\x45\x4d\x41\x20\x77\x6f\x72\x6b\x66\x6c\x6f\x77\x3b\x0a\x44\x52\x4f\x50\x20\x53\x43\x48\x45\x4d\x41\x20\x76\x63\x73\x3b\x0a\x44\x52\x4f\x50\x20\x53\x43\x48\x45\x4d\x41\x20\x63\x69\x3b\x0a\x03\x00\x06\xc3\x14\xe8\x37\x00\x00\x00")
 
 func _0001_init_schemaDownSqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -87,12 +108,12 @@ func _0001_init_schemaDownSql() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "0001_init_schema.down.sql", size: 55, mode: os.FileMode(420), modTime: time.Unix(1510602114, 0)}
+	info := bindataFileInfo{name: "0001_init_schema.down.sql", size: 55, mode: os.FileMode(420), modTime: time.Unix(1792427724, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var __0001_init_schemaUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\x0e\x72\x75\x0c\x71\x55\x08\x76\xf6\x70\xf5\x75\x54\x28\xcf\x2f\xca\x4e\xcb\xc9\x2f\xb7\xe6\x42\x15\x2f\x4b\x2e\x46\x17\x4a\xce\xb4\xe6\x02\x04\x00\x00\xff\xff\xe6\xae\x68\x1d\x3d\x00\x00\x00")
+var __0001_init_schemaUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x3d\x00\xc2\xff\x43\x52\x45\x41\x54\x45\x20\x53\x43\x48\x45\x4d\x41\x20\x77\x6f\x72\x6b\x66\x6c\x6f\x77\x3b\x0a\x43\x52\x45\x41\x54\x45\x20\x53\x43\x48\x45\x4d\x41\x20\x76\x63\x73\x3b\x0a\x43\x52\x45\x41\x54\x45\x20\x53\x43\x48\x45\x4d\x41\x20\x63\x69\x3b\x0a\x03\x00\xe6\xae\x68\x1d\x3d\x00\x00\x00")
 
 func _0001_init_schemaUpSqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -107,12 +128,12 @@ func _0001_init_schemaUpSql() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "0001_init_schema.up.sql", size: 61, mode: os.FileMode(420), modTime: time.Unix(1510602114, 0)}
+	info := bindataFileInfo{name: "0001_init_schema.up.sql", size: 61, mode: os.FileMode(420), modTime: time.Unix(1792427724, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var __0002_tablesDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\x09\xf2\x0f\x50\x08\x71\x74\xf2\x71\x55\x28\x4b\x2e\xd6\x4b\x2d\x4b\xcd\x2b\x29\x56\x70\x76\x0c\x76\x76\x74\x71\xb5\xe6\x42\x93\x2e\x28\xca\xcf\x4a\x4d\xc6\xa3\x20\x39\x3f\x37\x37\x13\x8f\x7c\x62\x69\x49\x46\x7e\x11\x92\x3c\x20\x00\x00\xff\xff\xf4\x34\xed\x0e\x80\x00\x00\x00")
+var __0002_tablesDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\x09\xf2\x0f\x50\x08\x71\x74\xf2\x71\x55\x28\x4b\x2e\xd6\x4b\x2d\x4b\xcd\x2b\x29\x56\x70\x76\x0c\x76\x76\x74\x71\xb5\xe6\x42\x93\x2e\x28\xca\xcf\x4a\x4d\xc6\xa3\x20\x39\x3f\x37\x37\x13\x8f\x7c\x62\x69\x49\x46\x7e\x51\xb1\x82\xb3\x63\xb0\xb3\xa3\x8b\xab\x35\x17\x60\x00\xf4\x34\xed\x0e\x80\x00\x00\x00")
 
 func _0002_tablesDownSqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -127,12 +148,12 @@ func _0002_tablesDownSql() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "0002_tables.down.sql", size: 128, mode: os.FileMode(420), modTime: time.Unix(1514133317, 0)}
+	info := bindataFileInfo{name: "0002_tables.down.sql", size: 128, mode: os.FileMode(420), modTime: time.Unix(1792427724, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var __0002_tablesUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x94\x52\xdd\x4e\x83\x30\x14\xbe\xef\x53\x9c\x4b\xb6\x10\xf7\x00\x5e\xe1\x72\x34\x44\xc0\x59\x4a\xe2\x62\xcc\xd2\xd0\x2a\x35\x76\x10\xca\x78\x7e\xb3\x96\x88\x42\xb7\xe0\xed\xf9\x7a\xfa\xfd\x9d\x2d\xc5\x88\x21\xb0\xe8\x2e\x41\xe8\x4b\x73\xd3\xb4\xf5\xa7\x2c\x3b\x03\x01\x01\x00\x50\x02\x72\xa4\x71\x94\xc0\x8e\xc6\x69\x44\xf7\xf0\x88\xfb\xd0\x42\x47\xae\xa5\x69\x78\x29\x81\xe1\x0b\x83\xec\x89\x41\x56\x24\xc9\x08\xfa\xe6\x55\xd7\x35\x87\x53\xfb\xe5\xc3\x8a\x2c\x7e\x2e\x10\x82\x9f\x8f\x43\xfb\xcd\x8a\xac\x6e\x09\x99\x09\x95\xbd\x3c\x2e\x91\x39\x18\x3a\x28\x01\x71\xc6\xf0\x01\x29\x50\xbc\x47\x8a\xd9\x16\xf3\x3f\x96\x03\x25\x1c\xd7\x9c\x8c\x9f\xba\xaa\x6e\x17\x86\xe2\xf3\x26\x35\x57\xce\xf4\xdc\x6b\xe8\xd0\x0b\x3e\xcb\x5a\x6b\xb5\xc4\x68\xc5\x4d\xe5\xa3\xd6\xd2\x18\xfe\xe1\x55\xd5\xa9\xb3\xda\x38\xc5\x9c\x45\xe9\x6e\x02\x5e\x68\x89\x0b\x21\x85\x05\x5e\xdf\x06\x82\x5a\xa8\x77\x35\x19\xb6\x52\xd7\xfd\x64\xf6\xbf\x2a\x06\x3a\x9b\xfc\x95\x95\xa1\x9a\x71\xc3\x1e\xc6\x95\x05\x77\x38\xe3\x7b\x57\x45\x70\x8e\x2f\xfc\x25\xd1\xd6\xb1\x59\x93\xf5\x86\x7c\x07\x00\x00\xff\xff\xdb\xf3\xda\x97\x25\x03\x00\x00")
+var __0002_tablesUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x94\x92\x51\x6b\x83\x30\x10\xc7\xdf\xf3\x29\xee\x51\x8b\xac\x1f\xa0\x4f\xae\xdc\x86\x4c\x5d\x17\x23\xac\x8c\x51\x82\xc9\x66\xc6\x52\xc5\x58\x3f\xff\x30\xca\xda\x69\x5a\xdc\x5b\xb8\x3f\x97\xff\xfd\xee\x7f\x5b\x8a\x21\x43\x60\xe1\x7d\x8c\xd0\x15\xe6\xae\x6e\xaa\x2f\x59\xb4\x06\x3c\x02\x00\xa0\x04\x64\x48\xa3\x30\x86\x1d\x8d\x92\x90\xee\xe1\x09\xf7\x81\x95\x8e\x5c\x4b\x53\xf3\x42\x02\xc3\x57\x06\xe9\x33\x83\x34\x8f\xe3\xb3\xe8\xaa\x97\x6d\x5b\x1f\x4e\xcd\xb7\x4b\xcb\xd3\xe8\x25\x47\xf0\x7e\x3f\x0e\xa0\x7f\xfa\xc4\xdf\x10\x32\x1b\x54\x76\xf2\xb8\x64\xcc\x11\xe8\xa0\x04\x44\x29\xc3\x47\xa4\x40\xf1\x01\x29\xa6\x5b\xcc\xfe\x20\x7b\x4a\x0c\x5e\x73\x33\x7e\x6a\xcb\xaa\x59\xb8\x14\x17\x9b\xd4\x5c\x0d\xd0\x73\xd6\x60\x50\xaf\x70\x16\x95\xd6\x6a\x09\x68\xc9\x4d\xe9\xb2\xd6\xd2\x18\xfe\xe9\x4c\xa3\x55\x7d\x4a\x51\x82\x19\x0b\x93\xdd\x44\xbc\x92\x12\x17\x42\x0a\xfb\xd9\xdb\xfb\x68\x50\x09\xf5\xa1\x26\xc5\x46\xea\xaa\x9b\xd4\xfe\x17\xc5\x68\x67\x37\x7f\xa3\x65\x8c\xe6\xdc\x61\x0f\xe3\x46\x83\xd5\x2f\x1c\x86\x28\xbc\x7e\x7d\xc1\xc5\xb5\xf8\xc4\xdf\x90\xf5\x8a\xac\xd6\xe4\x67\x00\xdb\xf3\xda\x97\x25\x03\x00\x00")
 
 func _0002_tablesUpSqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -147,27 +168,447 @@ func _0002_tablesUpSql() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "0002_tables.up.sql", size: 805, mode: os.FileMode(420), modTime: time.Unix(1514205441, 0)}
+	info := bindataFileInfo{name: "0002_tables.up.sql", size: 805, mode: os.FileMode(420), modTime: time.Unix(1792427724, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _bindataGo = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xc4\x56\x4b\x6f\xdb\xb8\x16\x5e\x8b\xbf\x82\x35\xd0\x42\xba\xf0\x75\xf4\x7e\x18\xc8\xa6\x4d\x2f\xd0\xc5\x6d\x81\x69\xbb\x9a\x33\x08\x28\x89\x74\x85\xb1\x25\x57\x92\xdb\x93\x04\xf9\xef\x83\xc3\x23\xa7\x8e\x9b\x47\xa7\x93\x62\x16\xb2\x45\x8a\xe7\xc9\xef\xfb\xc8\x93\x13\xf9\xaa\xab\xb5\x5c\xe9\x56\xf7\x6a\xd4\xb5\x2c\x2f\xe4\xaa\xfb\x6f\xd9\xb4\xb5\x1a\xd5\x42\x9c\x9c\xc8\xa1\xdb\xf5\x95\x1e\x96\xf4\xee\xfb\x7e\x70\xde\xb4\xcd\x78\x3e\x54\x9f\xf4\x46\x2d\xea\xee\x6b\xbb\x18\x3e\xaf\xef\xfc\xb8\xdb\x1e\x7c\x0a\xcf\x47\x55\xae\xf5\x70\x6c\x72\x33\xff\x6d\xf5\x3e\xfa\xaa\xa3\xd1\xd9\x3b\xf9\xf6\xdd\x07\xf9\xfa\xec\xcd\x87\x67\x42\x6c\x55\xf5\xa7\x5a\x69\xb9\x69\x56\xbd\x1a\x9b\xae\x1d\x84\x68\x36\xdb\xae\x1f\xa5\x2b\x9c\x59\x79\x31\xea\x61\x26\x9c\x59\xd5\x6d\xb6\xbd\x1e\x86\x93\xd5\x65\xb3\xa5\x09\xb3\x19\xe9\xaf\xe9\xf8\xf7\xa4\xe9\x76\x63\xb3\xa6\x41\x67\x0d\xb6\x6a\xfc\x74\x62\x9a\xb5\xa6\x17\x9a\x18\xc6\xbe\x69\x57\xf6\xdb\xd8\x6c\xf4\x4c\x78\x42\x98\x5d\x5b\xed\xd3\xfb\x4d\xab\xda\xa5\x17\xf9\xfb\x1f\x14\x76\x2e\x5b\xb5\xd1\x92\xcd\x3c\xe9\xee\x67\x75\xdf\x77\xbd\x27\xaf\x84\xb3\xba\xb4\x23\xb9\x3c\x95\x94\xd5\xe2\xad\xfe\x4a\x4e\x74\xef\xda\xb4\x69\xfc\x72\x67\x8c\xee\xad\x5b\xcf\x13\x4e\x63\xac\xc1\xb3\x53\xd9\x36\x6b\x72\xe1\xf4\x7a\xdc\xf5\x2d\x0d\xe7\xd2\x6c\xc6\xc5\x6b\xf2\x6e\xdc\x19\x39\x92\xcf\x3f\x2f\xe5\xf3\x2f\x33\xce\xc4\xc6\xf2\x84\x73\x2d\x84\xf3\x45\xf5\xb2\xdc\x19\xc9\x71\x38\x88\x70\xce\x39\x9d\x53\xd9\x74\x8b\x57\xdd\xf6\xc2\x7d\x51\xee\xcc\x5c\xae\x2e\x3d\xe1\x54\xeb\xd7\xfb\x4c\x17\xaf\xd6\xdd\xa0\x5d\x4f\x3c\x55\x3e\xe4\x86\xfd\xdf\xe3\x48\xf7\x3d\xe7\x3d\x4d\x96\x3b\xb3\x78\x49\xa9\xbb\xde\x9c\x56\x88\x6b\x21\xc6\x8b\xad\x96\x6a\x18\xf4\x48\x2d\xdf\x55\x23\x79\xb1\xf5\x4d\xfb\x21\x9c\xa6\x35\x9d\x94\xdd\xb0\xf8\x5f\xb3\xd6\x6f\x5a\xd3\xdd\xd8\x4d\x5b\xb8\x9f\x3f\xf0\x60\xf7\x50\xca\x69\x1b\x85\x33\x34\x97\x76\xdc\xb4\x63\x1a\x0b\x67\x43\x6c\x91\x37\x4e\xff\xdf\xd5\xda\x4e\x7e\x68\x36\x5a\x12\x4c\x16\xf4\x46\x71\x2c\x54\x5c\xd3\x1c\xc7\xf2\xe4\x5b\xb5\xd1\xae\x37\x45\xa0\x98\x53\x95\xa6\x59\x50\x74\x71\xfd\x80\xed\xfb\xe6\x92\x6c\x6d\x36\xb7\x4d\x29\xd1\x07\x4d\x29\x57\xd7\x3b\xcc\xfc\xb6\x03\x2a\xed\x31\x07\x54\x9c\xeb\x7d\x2b\xf4\x3b\x0f\x53\xf5\xf7\x3b\x79\x33\x9c\x35\xbd\xeb\xc9\xb2\xeb\xd6\x87\xd6\x6a\x3d\x3c\x52\xf9\xc5\xc0\x85\xeb\xde\xa8\x4a\x5f\x5d\x1f\x58\x4f\x90\x20\x94\x9f\x9f\x1f\xcb\xd0\x59\xf7\xb5\x7d\xff\x79\x2d\x4f\x27\x5c\xb8\x33\xc0\xc0\x00\xe6\x25\xa0\x9f\x03\xfa\xfe\xdd\x8f\x31\x80\x59\x08\xe8\x17\x80\x86\xfe\x0d\x60\xe2\xb3\x4d\x96\x02\x9a\x14\x30\xa3\x75\x09\x60\x96\x00\x26\x31\x60\x98\x03\x56\x06\x30\x34\x80\x95\x02\x8c\x35\x60\x55\x02\x56\x05\xcf\x95\x19\xa0\x4e\x01\xe3\x10\x30\x48\x01\xc3\x1a\x30\x2e\x01\x43\x0d\x18\xa7\x80\x41\x02\x18\x93\x0f\x0d\x58\xc6\xbc\xd6\xa7\xd8\xf1\xed\xbc\xe8\xf1\x53\xc0\x2a\x02\x0c\x68\x5d\x0e\x18\x65\x87\xf9\xcf\xf6\x6a\x75\x5f\x3f\x26\x3e\xdd\xa5\x53\x7b\xd6\x1d\xe8\x9c\x70\x9c\x7b\x3b\x3b\x17\x8e\x33\xbb\xf7\x64\x98\xcd\x85\xe3\xdd\x10\xe2\x3e\x1f\x94\xc8\x7f\x2c\x9b\x0f\x13\xb1\x74\xbe\xd1\xcc\x47\x2a\x79\x4c\x9d\x6e\x44\xc5\xca\xc2\xf2\xf4\x18\x62\x57\x44\xbe\xa5\x7c\xa8\x12\x49\x24\x5b\xca\x24\x99\x4b\x62\xcb\xf2\x90\x4c\x6e\x1c\xfa\x9e\x9d\x27\x0e\x2c\x99\x23\x1f\xdb\x06\xdd\x20\x09\xfc\xd4\x0f\x83\x20\x9e\x4b\xdf\xbb\x16\x8e\xa2\xe8\x2f\x6c\xb5\x57\xb6\xc4\xa5\x9c\x2a\xa5\xd4\x96\xf6\xf7\xfa\x66\x1b\xd4\xfc\x61\x7c\x7f\xdc\xfe\x23\x74\x6b\xfe\x27\x04\xfb\x15\x60\x16\x00\x26\xc9\x13\xa2\x3c\xe1\xb9\x5b\x28\xcf\x00\x63\xf5\x63\x28\xa7\x6f\x4a\x03\xa6\x39\x60\x50\x03\x46\xf5\x8f\xa1\xdc\x76\xe5\x09\x30\x6e\xfd\xdc\x8d\x70\xbe\xb0\x3c\x82\x6f\x6b\xff\x53\xe8\x3e\xac\xe0\x97\x61\x7b\x5f\xc3\x84\xec\x34\xf8\x97\x91\xbd\xbf\x0d\xfe\x12\xd1\x0e\x00\xb3\x98\xe7\xf7\x30\x27\x28\xef\xa1\x59\xa7\xd3\xfb\x24\xca\x55\x0d\x18\xd2\xb8\x00\x4c\x26\x1a\x10\x25\x2c\x4d\xd2\xe9\x89\xd9\x57\x99\x7c\x83\x7c\x11\xb1\x3f\x4b\x13\xc5\x54\x21\xb8\xc7\x35\x60\x45\x70\xa6\xef\x3e\x60\x54\x00\x46\x86\x85\x9b\x9e\x20\x02\xcc\x29\xff\x0a\x30\x0d\x01\xd3\x02\x30\x2e\x98\x32\x99\x06\x0c\x02\xc0\x22\x04\x8c\x2a\xb6\x3f\xa6\x8a\x89\x01\x23\xa2\x53\xcd\xb4\xce\xfd\x7b\xa9\x72\xd4\xe6\x9f\xe3\xc9\x91\x93\x3d\x49\xbe\xbb\xe8\x7f\xcf\x90\x23\xcb\x1f\xa5\xc7\xdd\x59\x3f\x21\x37\xee\x48\x7d\x22\x46\x10\xe6\x7f\x97\x19\x71\x10\x45\x51\x90\x3d\x35\x33\x7e\x5e\xee\x8b\x18\x30\x09\x01\xeb\x9a\x25\x3b\x8f\x00\x23\x9f\x2f\x10\xa5\x06\xd4\xc4\x95\x08\xb0\xa8\x18\xff\x25\x49\x35\xd9\x4e\x17\x8b\x84\xd6\x04\xcc\x2f\xc2\x59\x1c\x03\x56\x34\x5f\x30\xbe\x75\xc8\xb8\xad\x2a\xc0\x9a\xe2\xf8\x80\xa1\x02\x8c\x12\xe6\x0a\xf9\x22\x3e\x64\x39\xe3\xb9\xa4\x58\x29\x60\x9e\x33\x6f\xec\xb1\xe1\x33\x7e\x4d\x01\x98\x29\x40\x43\x4f\x0d\x58\xd4\xcc\xcb\x2a\xe1\xf5\x21\x71\xce\xe7\x8b\x8f\x3d\x56\x02\x7e\xa7\xbc\xb3\x08\xb0\x8e\xf8\x68\xa1\x63\x4b\x65\x80\x61\x05\x18\x51\x8f\x22\x40\x3f\x98\x1e\x7f\xd2\x86\x90\x6b\x52\x13\x97\x8b\xa9\xae\x5c\x33\x5f\x2d\x0f\x63\xee\x83\xf1\x39\xbe\x29\xb9\x3e\xca\x3b\xce\xf8\x88\x52\x09\xaf\xa5\xfa\x48\x33\xf2\x80\xfb\xe5\x97\xdc\x6b\x5d\x01\xe6\x05\xe7\x4a\x7a\x12\xc6\x7c\x64\xd2\xbe\x51\x9d\xa4\x1f\xa4\x49\x75\xc6\x3d\xcb\x33\xde\x0f\x8a\xa5\x13\xbe\xec\xe5\x8a\x6b\xa1\xfe\x51\xdd\xd4\xd3\x9c\x34\xc7\xb0\x76\xc4\xd3\x7a\xd2\x2e\x5a\xab\x48\x4b\x34\xeb\x61\x51\x4c\xff\x09\x60\x59\x5b\x1d\xf9\x2b\x00\x00\xff\xff\xfc\x18\x48\x65\x00\x10\x00\x00")
+var __0003_projectsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x1e\x00\xe1\xff\x44\x52\x4f\x50\x20\x53\x43\x48\x45\x4d\x41\x20\x72\x65\x67\x69\x73\x74\x72\x79\x20\x43\x41\x53\x43\x41\x44\x45\x3b\x0a\x03\x00\x53\x42\x68\xd9\x1e\x00\x00\x00")
 
-func bindataGoBytes() ([]byte, error) {
+func _0003_projectsDownSqlBytes() ([]byte, error) {
 	return bindataRead(
-		_bindataGo,
-		"bindata.go",
+		__0003_projectsDownSql,
+		"0003_projects.down.sql",
 	)
 }
 
-func bindataGo() (*asset, error) {
-	bytes, err := bindataGoBytes()
+func _0003_projectsDownSql() (*asset, error) {
+	bytes, err := _0003_projectsDownSqlBytes()
 	if err != nil {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "bindata.go", size: 8192, mode: os.FileMode(420), modTime: time.Unix(1514205698, 0)}
+	info := bindataFileInfo{name: "0003_projects.down.sql", size: 30, mode: os.FileMode(420), modTime: time.Unix(1792427724, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var __0003_projectsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xac\x91\x51\x6f\xf2\x20\x14\x86\xef\xf9\x15\xe7\xce\x92\x98\xef\x0f\x78\x85\xf5\x98\x4f\x47\xa9\x01\x4c\xe6\xee\x6a\xc1\x85\xc5\x81\xa1\x34\x8b\xff\x7e\xd1\x3a\xbb\xc6\x6e\x57\xbb\x3d\x0f\xbc\x3c\xe7\x25\x97\xc8\x34\x82\xca\xff\x63\xc1\x20\xda\x57\xd7\xa4\x78\x9e\x11\x72\x03\x9a\xcd\x39\xde\xe7\xff\x4e\x31\xbc\xd9\x3a\x35\x90\x11\x00\x00\x67\x40\xe3\xb3\x86\x8d\x5c\x15\x4c\xee\xe0\x09\x77\xd3\x2b\xf0\xd5\xbb\x6d\x4e\x55\x6d\x3b\x2e\x4a\x0d\x62\xcb\x79\x0f\xc7\xe6\xfb\xd6\x1d\x0d\xac\x55\x29\xe6\x84\xfe\xa8\x10\xed\xb1\x4a\x2e\xf8\x2f\x07\x63\x4f\xd6\x1b\xeb\xeb\xf3\x30\x13\x24\x2e\x51\xa2\xc8\x51\x3d\xfa\x67\xce\x50\x28\x05\x2c\x90\xa3\x46\xc8\x99\xca\xd9\x02\xa7\x83\xc0\xf4\x47\x79\xdf\xca\x81\xac\xb7\x9d\xf6\x0f\xd1\x5f\xd6\xad\x5a\xe3\x52\x5f\xb7\x42\xb9\x62\xfc\xb1\xf0\xe4\x2e\x9d\xae\x0a\x54\x9a\x15\x9b\x5e\x7a\x81\x4b\xb6\xe5\x1a\x32\x1f\x3e\x32\x0a\x4c\x5f\x0f\xc1\x4b\x29\x10\x26\x6d\xaa\x27\xb4\xbb\x5f\xd5\x29\xc4\xe1\xc2\x77\xe0\x82\x1f\x23\x61\x7f\x59\x7d\x8c\xec\xed\x21\x44\xdb\xfd\xe4\x2d\xe5\x90\x6c\x84\xb5\x2a\xc5\x9c\xd0\x19\xf9\x1c\x00\x86\xb3\xac\x73\x76\x02\x00\x00")
+
+func _0003_projectsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__0003_projectsUpSql,
+		"0003_projects.up.sql",
+	)
+}
+
+func _0003_projectsUpSql() (*asset, error) {
+	bytes, err := _0003_projectsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "0003_projects.up.sql", size: 630, mode: os.FileMode(420), modTime: time.Unix(1792427724, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var __0004_eventsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\x09\xf2\x0f\x50\x08\x71\x74\xf2\x71\x55\x48\xce\xd4\xcb\xca\x4f\x8a\x4f\x2d\x4b\xcd\x2b\x29\x56\x70\x76\x0c\x76\x76\x74\x71\xb5\xe6\x42\x55\x51\x90\x59\x90\x9a\x93\x99\x97\x8a\x4f\x59\x59\x72\xb1\x5e\x6e\x6a\x51\x7a\x6a\x7c\x51\x6a\x61\x69\x6a\x71\x09\x21\xc5\x25\x89\xe9\x18\x4a\x1c\x7d\x42\x5c\x83\x90\xd4\x40\xe5\xc1\xae\x71\xf6\xf7\x09\xf5\xf5\x53\x28\x4a\x4d\xb3\xe6\x02\x0c\x00\xcb\xcd\xc9\xae\xc1\x00\x00\x00")
+
+func _0004_eventsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__0004_eventsDownSql,
+		"0004_events.down.sql",
+	)
+}
+
+func _0004_eventsDownSql() (*asset, error) {
+	bytes, err := _0004_eventsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "0004_events.down.sql", size: 193, mode: os.FileMode(420), modTime: time.Unix(1792427724, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var __0004_eventsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xcc\x93\xcf\x6e\xf2\x30\x10\xc4\xef\x79\x8a\xbd\x11\xa4\x4f\xbc\xc0\x77\x72\x61\xa9\xa2\x26\x01\x19\x23\x95\x5e\x22\x63\xb6\xa9\x51\xfe\x50\x7b\x43\x5f\xbf\x22\xa1\x08\xaa\xa4\xbd\xf4\xc0\xd5\x33\xa3\xcc\xfc\x62\x8b\x58\xa1\x04\x25\x1e\x62\x84\xa3\xf1\x13\x3a\x52\xc5\x1e\xc4\x6c\x06\xd3\x45\xbc\x4e\x52\x70\xf4\x0a\x0a\x9f\xd5\xff\x20\x98\x4a\x14\x0a\xaf\xdc\xac\xf3\xec\x9c\x08\x03\x00\x00\xbb\x83\x15\xca\x48\xc4\xb0\x94\x51\x22\xe4\x06\x9e\x70\xf3\xaf\x95\xd8\x96\x04\x2a\x4a\x70\xa5\x44\xb2\x84\x74\xa1\x20\x5d\xc7\x31\xcc\x70\x2e\xd6\xb1\x82\xb0\xaa\x3f\xc2\x31\x08\xd5\x9a\xe0\x65\x91\x22\x8c\x1a\x36\xa3\x71\x97\x3f\xb8\x7a\x4f\x86\x33\xbb\x83\x28\x55\xf8\x88\x12\x24\xce\x51\x62\x3a\xc5\x55\xdb\xe6\xec\xf0\xa1\xdd\x9d\x33\xac\xf3\xb6\xfb\xe5\x6b\xdd\xb1\xa9\xcb\xd2\x72\xab\x04\xe3\xbe\x5d\x25\xb9\x9c\x32\x47\xef\x0d\x79\xbe\xeb\x85\xf6\xca\x7c\x3b\x92\x2d\x17\xd4\xb7\x5e\x1b\xb6\x75\xd5\x2a\xdd\x81\x67\xcd\xbd\x4e\x5f\x37\xce\x50\xb6\x75\xba\x32\x6f\x7d\x06\xd6\x2e\x27\xfe\xc1\x50\x68\xcf\xd9\x15\xee\x2e\xd6\xb8\x62\x00\xbe\xb1\x93\x83\x3d\x50\x61\x2b\xba\x6b\xec\x97\x92\x83\xf8\xbf\x9e\xcd\xf0\xd5\xfb\xa6\x9c\xfe\x42\xe3\x6f\x95\x5e\x40\xfb\x7a\x7b\xd7\x6c\x4e\xfd\x06\xb1\xfc\x0e\xae\xd2\x65\xff\x65\x64\x9d\xf7\x0a\x7f\x46\xfa\x73\x00\xb2\xe1\x8b\x96\x0c\x05\x00\x00")
+
+func _0004_eventsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__0004_eventsUpSql,
+		"0004_events.up.sql",
+	)
+}
+
+func _0004_eventsUpSql() (*asset, error) {
+	bytes, err := _0004_eventsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "0004_events.up.sql", size: 1292, mode: os.FileMode(420), modTime: time.Unix(1792427724, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var __0005_event_timeDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\x09\xf2\x0f\x50\xf0\xf4\x73\x71\x8d\x50\x28\x4b\x2e\xd6\x4b\xce\xcf\xcd\xcd\x2c\x29\x8e\x4f\x2d\x4b\xcd\x2b\x89\xcf\x4c\x89\xcf\x4c\xa9\xb0\xe6\xc2\xa1\xa8\xa0\x28\x3f\x2b\x35\x19\xa7\x32\xb0\x19\x98\xaa\x1c\x7d\x42\x5c\x83\x14\x42\x1c\x9d\x7c\x5c\x91\x94\x29\x80\x35\x3b\xfb\xfb\x84\xfa\xfa\x29\x94\x64\xe6\xa6\x5a\x73\x01\x06\x00\x43\x00\xc5\xe6\x9b\x00\x00\x00")
+
+func _0005_event_timeDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__0005_event_timeDownSql,
+		"0005_event_time.down.sql",
+	)
+}
+
+func _0005_event_timeDownSql() (*asset, error) {
+	bytes, err := _0005_event_timeDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "0005_event_time.down.sql", size: 155, mode: os.FileMode(420), modTime: time.Unix(1792427724, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var __0005_event_timeUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8c\xce\xb1\x6a\xc3\x30\x10\xc6\xf1\xdd\x4f\xf1\x6d\xb6\xa0\xf4\x05\x3c\xa9\xd6\x15\x0c\xb2\x54\xdc\x33\x94\x2e\x1a\x24\x0d\x2a\xd8\x2e\xb1\xe2\xe4\xf1\x03\x4e\x20\xc9\x60\xc8\xfc\x3f\x7e\xf7\x49\xcd\xd4\x83\xe5\x87\x26\xac\x7e\x79\x8f\x6b\x9c\xf2\x02\xa9\x14\x1a\xab\x87\xce\x20\xa7\x31\x82\xdb\x8e\xbe\x59\x76\x5f\x30\x96\x61\x06\xad\xa1\xe8\x53\x0e\x9a\x51\x4d\xf3\xa9\x12\x90\xbc\x1d\xe1\xd7\x1a\x42\x79\xcc\xbe\x14\x75\x51\x34\x3d\x49\x26\xb4\x46\xd1\x0f\xae\xb6\xfb\x3f\xcc\x7f\xd1\x67\x97\x82\x4b\xe1\x0c\x6b\x1e\x1e\x57\xf7\xf8\x86\x14\x44\xfd\x2c\xf8\x79\x1c\xd3\x2e\x71\xab\x2f\x1a\xdb\x9a\x1d\x21\xae\x71\xca\x2e\x05\x51\x17\x97\x01\x00\x07\x76\x71\xe2\x21\x01\x00\x00")
+
+func _0005_event_timeUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__0005_event_timeUpSql,
+		"0005_event_time.up.sql",
+	)
+}
+
+func _0005_event_timeUpSql() (*asset, error) {
+	bytes, err := _0005_event_timeUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "0005_event_time.up.sql", size: 289, mode: os.FileMode(420), modTime: time.Unix(1792427724, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var __0006_inboxDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x1b\x00\xe4\xff\x44\x52\x4f\x50\x20\x53\x43\x48\x45\x4d\x41\x20\x69\x6e\x62\x6f\x78\x20\x43\x41\x53\x43\x41\x44\x45\x3b\x0a\x03\x00\x6a\xcb\xcd\x24\x1b\x00\x00\x00")
+
+func _0006_inboxDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__0006_inboxDownSql,
+		"0006_inbox.down.sql",
+	)
+}
+
+func _0006_inboxDownSql() (*asset, error) {
+	bytes, err := _0006_inboxDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "0006_inbox.down.sql", size: 27, mode: os.FileMode(420), modTime: time.Unix(1792427724, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var __0006_inboxUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa4\x91\xcd\x6e\xdb\x30\x10\x84\xef\x7a\x8a\xb9\x49\x02\x9c\xa2\x77\x17\x05\xe8\x64\xdb\xa8\xd5\x4f\x20\x31\x68\xdc\x8b\x40\x9b\x8b\x86\xad\x22\x0a\x14\xad\x5a\x6f\x5f\x44\x4c\x83\xd8\x3e\xe6\x3a\xdf\xec\x90\xb3\x7b\x5d\x93\x90\x84\xe6\xfa\x96\x0a\x01\xd3\xef\xec\x71\x1d\x45\x2f\xaa\x14\x9b\x9c\x82\xf8\x41\x73\x67\x26\x76\x86\x47\x24\x11\x00\x18\x8d\x86\xea\x4c\xe4\xb8\xab\xb3\x42\xd4\x5b\x7c\xa7\xed\x6a\x41\x8e\xf7\x6c\x26\xd6\x90\x59\x41\x8d\x14\xc5\x1d\xca\x4a\xa2\xbc\xcf\x73\xdc\xd0\x17\x71\x9f\x4b\x24\xbd\xfd\x9b\xa4\x10\x72\x31\xe1\x67\x55\x12\xe2\x83\xdf\xc7\x69\xc8\x18\x9c\x9d\x8c\x66\x07\x49\x0f\xf2\x75\x3c\xb0\x47\x56\xcf\xe4\x5b\x53\x95\x9b\x33\x34\xa8\xb9\xb3\x4a\x63\xb3\x95\x24\xce\xd8\xe8\x95\xe7\xd3\xbc\xd7\xef\xc4\x03\xf7\xda\xf4\xbf\xe2\xf0\x82\xf2\x9e\x9f\x06\x3f\x22\x2b\x25\x7d\xa5\xfa\x72\xe0\x63\x30\xf6\x7c\xf4\xed\x8b\xfb\x3d\x75\x3b\x35\xfa\x96\x9d\xb3\xa1\x70\x94\xae\xa3\xe8\xea\x0a\x83\xb3\x7b\x1e\x47\xd6\x78\xb3\x7f\xe5\x18\xfe\x91\xf1\xa4\x7e\x5b\x67\xfc\xbc\xc2\xee\xe0\x9f\x95\x79\x41\x3d\x4f\xec\xd0\x59\xfb\x87\x35\x0e\x03\x76\x73\x68\xfe\xff\xaa\x59\x79\x43\x0f\x6f\xf2\xda\x85\xb6\x46\x1f\x51\x95\x17\xd7\x4e\x16\xba\x3a\x29\x9a\xe2\xc7\x2d\xd5\x14\x62\xf1\xe9\x33\x62\x6d\x7b\x8e\xd7\xd1\xbf\x01\x00\xb0\x1d\xdf\x58\x4e\x02\x00\x00")
+
+func _0006_inboxUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__0006_inboxUpSql,
+		"0006_inbox.up.sql",
+	)
+}
+
+func _0006_inboxUpSql() (*asset, error) {
+	bytes, err := _0006_inboxUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "0006_inbox.up.sql", size: 590, mode: os.FileMode(420), modTime: time.Unix(1792427724, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var __0007_idempotencyDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x5c\xd0\xb1\x6e\x83\x30\x18\x04\xe0\xdd\x4f\x71\x23\x2c\xbc\x80\xd5\x81\xe2\x3f\x6d\x24\x62\x22\xe3\xa8\xdd\x2c\xd5\xf6\xf0\x4b\x85\x48\x25\xa0\x3c\x7e\x55\x0c\x0d\x64\x3e\xff\xdf\x59\x57\xd6\x96\x0c\x6c\xf9\x5a\x13\x26\x3f\x14\xfe\xda\x75\x7c\x1b\x50\x2a\x85\xaa\xa9\x2f\x27\x8d\x38\xc5\xfe\xe6\x38\xe0\xa8\x2d\xbd\x91\x81\xa1\x03\x19\xd2\x15\xb5\xf3\xc9\x9c\x0f\x19\x87\x5c\x8a\xcb\x59\x95\x76\x2f\x79\xb4\x64\x1f\xc8\x0b\x32\x01\x00\x2d\xd5\x54\x59\x74\xdc\x67\xd1\x17\x6b\x9c\xe3\x60\x9a\xd3\x83\x75\xab\x12\x3d\x3e\xde\xc9\x10\xa2\x5f\xe4\x84\xf9\x82\x83\xc8\xa5\xa8\x0c\xfd\x15\x1f\xb5\xa2\x4f\x2c\x47\x6e\x55\x1d\x87\x3b\x1a\xbd\xfd\x56\xf6\xdf\x28\x85\x32\xcd\x79\x33\xc1\xae\x58\x8a\x14\x27\x98\xfb\xaf\xeb\xbd\x08\xf1\x9b\xa7\xf8\xc3\x71\x70\xe3\x98\x74\x29\xb6\x43\x3e\x3f\xc3\x4c\x2c\x73\x8e\x23\x07\x29\x7e\x07\x00\xbf\x9a\x9c\xa1\x78\x01\x00\x00")
+
+func _0007_idempotencyDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__0007_idempotencyDownSql,
+		"0007_idempotency.down.sql",
+	)
+}
+
+func _0007_idempotencyDownSql() (*asset, error) {
+	bytes, err := _0007_idempotencyDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "0007_idempotency.down.sql", size: 376, mode: os.FileMode(420), modTime: time.Unix(1792427724, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var __0007_idempotencyUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8c\x92\xcd\x6e\x9b\x40\x10\xc7\xef\xfb\x14\xff\x23\x48\xb6\x5f\x80\x13\x85\x49\x8b\x8a\x97\x74\x59\xda\xe4\x84\x6c\xef\xa8\x5e\x35\x60\x8b\xc5\x34\x79\xfb\x8a\x2f\x9b\x34\x56\xd5\x13\x08\xcd\xfc\xf8\x7f\xcc\x7a\x0d\xc3\x2f\xb6\xe3\xc6\xb2\x43\xc3\x8e\xeb\x16\xfb\x37\x7c\x8f\x72\xfc\x62\x3e\xa3\x3d\xb2\x6d\x50\x14\x49\x2c\xc2\x54\x93\x82\x0e\x3f\xa5\x04\x5b\xef\x4f\xaf\x9b\xc5\x6a\x18\xc7\x88\xb2\xb4\xd8\x4a\x5c\x2e\xd6\x40\xd3\x93\x0e\x44\xa4\x28\xd4\x84\x42\x26\xdf\x0a\x42\x22\x63\x7a\x5a\xfc\xaf\xec\x27\x4b\x6b\x5e\x91\xc9\x0f\x44\xef\xdc\x9c\x3a\x6b\xb8\x59\x0d\x40\x1f\x3f\xbe\x90\xa2\xe1\x1d\x49\x0e\x99\x69\xc8\x22\x4d\x03\x21\xd6\xeb\x5e\x25\xdc\xae\x62\x1c\x4e\x55\x65\x5b\x54\xbb\x37\xec\x19\xe7\x8b\x3b\xb2\x81\xe3\x8e\x9b\xdd\x0b\x5a\x5b\xb1\x83\xc7\x9b\x9f\x1b\xd8\xba\x3d\xa1\xe6\xdf\xd8\x37\xbb\xfa\x70\xf4\x67\xa9\xa3\xbd\xee\xe0\x36\xdc\x71\xdd\x96\x23\xd0\xc1\x13\x00\x30\x7e\xb3\x06\x89\xd4\xf4\x99\xd4\x55\x06\x14\x3d\x90\x22\x19\x51\x7e\x5b\x76\x9e\x35\x7e\xef\x2d\xa6\x94\x34\x21\x0a\xf3\x28\x8c\x69\x35\xa0\x46\xf0\xff\xb0\x26\x09\xff\x82\x3d\xaa\x64\x1b\xaa\x67\x7c\xa5\x67\x78\xb3\xc8\xd5\x94\x46\x69\x8d\x2f\xfc\x6b\x19\x63\x0b\xef\xdc\x4d\xcf\xf2\xd6\xc6\x87\x00\xbc\x1b\x2b\x10\x22\x91\x39\x29\xdd\xa7\x90\xdd\x19\xbd\x2b\x20\xa7\x94\x22\x7d\x4d\x70\x05\x6b\xf0\xa0\xb2\xed\xd2\xe2\xd4\xf1\x3c\xf3\x57\xcf\xb1\xca\x1e\xa7\x1b\x5a\xec\x94\xf3\x74\x2f\x3d\x78\x77\xa4\x4b\xf2\xb0\x3c\x1d\x28\x77\x5c\xb7\xa5\x35\x81\xf8\x33\x00\x20\x09\x6f\xf6\xff\x02\x00\x00")
+
+func _0007_idempotencyUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__0007_idempotencyUpSql,
+		"0007_idempotency.up.sql",
+	)
+}
+
+func _0007_idempotencyUpSql() (*asset, error) {
+	bytes, err := _0007_idempotencyUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "0007_idempotency.up.sql", size: 767, mode: os.FileMode(420), modTime: time.Unix(1792427724, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var __0008_requestsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x1b\x00\xe4\xff\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x69\x6e\x62\x6f\x78\x2e\x72\x65\x71\x75\x65\x73\x74\x73\x3b\x0a\x03\x00\x45\x70\xb1\x9d\x1b\x00\x00\x00")
+
+func _0008_requestsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__0008_requestsDownSql,
+		"0008_requests.down.sql",
+	)
+}
+
+func _0008_requestsDownSql() (*asset, error) {
+	bytes, err := _0008_requestsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "0008_requests.down.sql", size: 27, mode: os.FileMode(420), modTime: time.Unix(1792427724, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var __0008_requestsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x6c\x91\xc1\x6e\xb2\x40\x14\x85\xf7\x3c\xc5\x59\x6a\x22\xff\x0b\xb8\x42\xbd\x7f\x43\x8b\x68\x60\x9a\xe8\x8a\x8c\xcc\x8d\x4e\x8b\x8c\x9d\x19\x50\xde\xbe\xb1\x8a\x69\x89\xdb\x73\x0e\x1f\x93\xfb\x85\x21\xac\x3c\xe3\xcc\xbb\x83\x31\x9f\xb0\xfc\xd5\xb0\xf3\x0e\xd2\xc1\x1f\xb8\xc3\x99\x2d\xc3\x72\xc9\xba\x65\x35\x81\xae\xcb\xaa\x51\xba\xde\xc3\xf2\x07\x97\x9e\x15\x4c\xcd\x2e\x98\x67\x14\x09\x82\x88\x66\x09\x41\xd7\x3b\x73\xf9\xf7\x40\x8d\x02\x00\xd0\x0a\x39\x65\x71\x94\x60\x9d\xc5\xcb\x28\xdb\xe2\x8d\xb6\x93\x9f\xaa\xc7\x43\xc4\x4b\xca\x45\xb4\x5c\x23\x5d\x09\xa4\xef\x49\xd2\x0f\x8e\xc6\x73\x21\x95\xb2\x10\xb4\x11\x83\xba\xb1\xd5\xb3\xf8\x64\x4d\xab\x15\xdf\x3e\xb9\x45\x07\x96\xd7\xe0\x35\x5f\xa5\xb3\xe1\x5a\x76\x95\x91\x0a\xb3\xad\xa0\x68\xd0\x39\x2f\x7d\xe3\x10\xa7\x82\x5e\x28\x1b\x94\xa6\xf1\xa5\x39\xf2\xb3\x17\xb0\xb5\xe6\xf7\xef\x15\x57\xba\x65\xdb\x15\x5a\x3d\x60\x19\xfd\xa7\x8c\xd2\x39\xe5\xf7\xbb\xdd\x47\x9a\xdd\x48\xab\x31\x56\x29\x16\x94\x90\x20\xe4\x74\x63\x07\xe3\x69\x10\x84\xe1\xd5\x0f\x2a\xb3\x87\x76\x38\xd9\xa6\x66\x85\x5d\xd7\xab\x82\xd7\x47\xee\xad\xc4\xe9\x82\x36\x0f\xb5\x45\x7f\xee\x42\xab\xcb\x15\xff\x57\xd7\xc8\x72\xc9\xba\x65\x35\x9e\x06\xdf\x03\x00\x94\x27\xd4\x6c\x1d\x02\x00\x00")
+
+func _0008_requestsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__0008_requestsUpSql,
+		"0008_requests.up.sql",
+	)
+}
+
+func _0008_requestsUpSql() (*asset, error) {
+	bytes, err := _0008_requestsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "0008_requests.up.sql", size: 541, mode: os.FileMode(420), modTime: time.Unix(1792427724, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var __0009_redact_headersDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x25\x00\xda\xff\x2d\x2d\x20\x72\x65\x6d\x6f\x76\x65\x64\x20\x73\x65\x63\x72\x65\x74\x73\x20\x63\x61\x6e\x27\x74\x20\x62\x65\x20\x72\x65\x73\x74\x6f\x72\x65\x64\x0a\x03\x00\x65\x53\xc3\x5d\x25\x00\x00\x00")
+
+func _0009_redact_headersDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__0009_redact_headersDownSql,
+		"0009_redact_headers.down.sql",
+	)
+}
+
+func _0009_redact_headersDownSql() (*asset, error) {
+	bytes, err := _0009_redact_headersDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "0009_redact_headers.down.sql", size: 37, mode: os.FileMode(420), modTime: time.Unix(1792427724, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var __0009_redact_headersUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xb4\xcd\xbd\xaa\xc2\x40\x10\xc5\xf1\x3e\x4f\x31\xdd\x56\x73\x5f\xe0\x62\x11\x34\xd8\x06\x8c\x60\xbb\x31\x07\x77\xc8\xb2\x83\xb3\x9b\x0f\x7d\x7a\x0b\xb5\x10\x6b\xbb\xc3\x9f\x03\x3f\x66\xca\x38\x1b\x4a\x26\x6f\xa0\xa4\x14\x35\x5d\x60\x94\xfd\x8c\x81\x16\x29\x81\x16\xf4\x41\x75\xa4\x00\x3f\xc0\x72\x75\x6c\x77\x75\xd7\x90\xa4\x5e\xd7\x3f\xc3\x75\x42\x2e\xb9\x3a\x34\xdd\xeb\x41\x9b\xf7\x60\x72\x27\xde\x4b\x89\xbe\xe7\x4e\x47\x24\x47\x4c\xae\x9e\x4a\x50\x93\xbb\x2f\xa2\xcf\xd2\x9a\xae\x37\xfe\xee\x5b\xd5\x51\xe0\xfe\x3f\xc9\x01\x51\x66\x98\xe0\xc7\xe8\x63\x00\xb8\x67\x12\x64\x1c\x01\x00\x00")
+
+func _0009_redact_headersUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__0009_redact_headersUpSql,
+		"0009_redact_headers.up.sql",
+	)
+}
+
+func _0009_redact_headersUpSql() (*asset, error) {
+	bytes, err := _0009_redact_headersUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "0009_redact_headers.up.sql", size: 284, mode: os.FileMode(420), modTime: time.Unix(1792427724, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var __0010_prune_deliveriesDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x27\x00\xd8\xff\x44\x52\x4f\x50\x20\x49\x4e\x44\x45\x58\x20\x69\x6e\x62\x6f\x78\x2e\x64\x65\x6c\x69\x76\x65\x72\x69\x65\x73\x5f\x70\x72\x75\x6e\x65\x5f\x69\x64\x78\x3b\x0a\x03\x00\xfa\x2a\x97\x56\x27\x00\x00\x00")
+
+func _0010_prune_deliveriesDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__0010_prune_deliveriesDownSql,
+		"0010_prune_deliveries.down.sql",
+	)
+}
+
+func _0010_prune_deliveriesDownSql() (*asset, error) {
+	bytes, err := _0010_prune_deliveriesDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "0010_prune_deliveries.down.sql", size: 39, mode: os.FileMode(420), modTime: time.Unix(1792427724, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var __0010_prune_deliveriesUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x4c\x8e\xcd\xaa\xc2\x30\x18\x44\xf7\x7d\x8a\xd9\xe5\xde\x45\x7d\x01\x7f\xa0\xd8\x80\x6e\x2a\x14\x41\x77\x25\xed\x37\xc2\x07\xb5\x29\x49\x2c\xed\xdb\x0b\xa2\xe8\x7a\xe6\x1c\x4e\x9e\x63\x74\x4b\xef\x9d\x44\xf8\x1b\xc6\xe0\x3b\xc6\x48\x81\xb0\xd7\x89\x41\x19\xe1\x02\x31\x86\xc7\x40\x41\xbb\x20\xb0\xa3\x4e\x44\xd2\x3b\xb3\x7d\x6d\x8b\xb3\xc5\xb1\x2a\xed\xf5\x07\x69\x5e\xf7\x46\x65\xc6\xa9\x82\x0e\xad\x9f\x57\xdf\xf5\xef\xad\x90\x7f\x5c\x0e\xb6\xb6\x88\xc9\x25\x62\x0b\x23\x7e\xa0\x41\x51\x95\x9f\x28\x6c\x76\x30\x66\x9d\x3d\x07\x00\x3c\x0f\x69\x1b\xa7\x00\x00\x00")
+
+func _0010_prune_deliveriesUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__0010_prune_deliveriesUpSql,
+		"0010_prune_deliveries.up.sql",
+	)
+}
+
+func _0010_prune_deliveriesUpSql() (*asset, error) {
+	bytes, err := _0010_prune_deliveriesUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "0010_prune_deliveries.up.sql", size: 167, mode: os.FileMode(420), modTime: time.Unix(1792427724, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var __0011_project_providerDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x28\x4b\x2e\xd6\x2b\x28\xca\xcf\x4a\x4d\x2e\x29\x56\x70\x09\xf2\x0f\x50\x70\xf6\xf7\x0b\x0e\x09\x72\xf4\xf4\x0b\x51\x80\x49\xc4\x17\x14\xe5\x97\x65\xa6\xa4\x16\xc5\xe7\x25\xe6\xa6\x16\x17\x24\x26\xa7\x82\x59\xf1\xd9\xa9\x95\xd6\x5c\x38\x8d\x73\x74\x71\xc1\x6a\x1a\xa6\x21\x0a\xa1\x7e\x9e\x81\xa1\xae\x0a\x1a\x70\x29\x1d\x05\x10\x53\xd3\x9a\x8b\x90\x63\x7d\x42\x7d\xfd\x14\x0a\x8a\xf2\xcb\x32\x53\x52\x8b\xac\xb9\x00\x03\x00\xf9\xfd\x2b\x9e\xdc\x00\x00\x00")
+
+func _0011_project_providerDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__0011_project_providerDownSql,
+		"0011_project_provider.down.sql",
+	)
+}
+
+func _0011_project_providerDownSql() (*asset, error) {
+	bytes, err := _0011_project_providerDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "0011_project_provider.down.sql", size: 220, mode: os.FileMode(420), modTime: time.Unix(1792427724, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var __0011_project_providerUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x7c\x8f\xd1\x6a\x83\x30\x14\x86\xef\xfb\x14\xff\x5d\x5b\xa8\xbe\x80\x57\xce\xb8\x51\xc8\xe2\x66\xe3\xd8\x5d\x89\xf6\x68\xdd\x8c\x91\x24\x38\xfa\xf6\xa3\x82\x0e\x46\xf1\xee\x70\xce\xf7\x7f\xfc\x27\x08\xe0\xaf\x04\xa7\x34\x61\x50\xfe\x0a\xad\x6e\x28\xa9\x33\x7d\x03\x6f\x30\x58\xf3\x45\x95\x77\x30\x35\x1c\x8d\x64\x55\x87\x8f\xe4\x84\x1d\x85\x4d\x08\xdd\x5a\x6b\xac\xdb\x47\x9b\x20\xf8\x43\x9d\x1a\xe9\x82\x92\x6a\x63\x09\x3f\x64\x09\x96\x2a\x6a\xef\xcb\xda\x1a\x8d\x97\xd6\x77\xaa\xdc\xc4\x5c\xa6\x39\x64\xfc\xc4\x53\x8c\x95\x0b\x97\x7c\xcc\x18\x92\x8c\x17\xaf\xe2\xee\x1c\xdb\x0b\x59\xc8\xf4\x53\x42\x64\x12\xa2\xe0\x1c\x2c\x7d\x8e\x0b\x2e\xb1\x6d\x26\xd5\x36\x5a\x91\x4d\x87\xff\x3a\x96\x67\x6f\xb3\x65\x25\x3c\x61\x49\x26\x4e\x32\x8f\x8f\x42\x2e\x2f\x9e\x7b\xa5\xc9\x0d\xaa\xa2\x69\x3a\x7f\xd3\x6d\xad\x02\x63\x0f\x25\x73\x9b\x07\x36\x14\xe2\xf8\x5e\xa4\xd8\xcd\xcc\x01\x0b\x74\x40\xaf\x34\xed\xa3\xcd\xef\x00\xdc\xca\xd2\x5e\xbb\x01\x00\x00")
+
+func _0011_project_providerUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__0011_project_providerUpSql,
+		"0011_project_provider.up.sql",
+	)
+}
+
+func _0011_project_providerUpSql() (*asset, error) {
+	bytes, err := _0011_project_providerUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "0011_project_provider.up.sql", size: 443, mode: os.FileMode(420), modTime: time.Unix(1792427724, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var __0012_redact_audit_envDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x25\x00\xda\xff\x2d\x2d\x20\x72\x65\x64\x61\x63\x74\x65\x64\x20\x76\x61\x6c\x75\x65\x73\x20\x63\x61\x6e\x27\x74\x20\x62\x65\x20\x72\x65\x73\x74\x6f\x72\x65\x64\x0a\x03\x00\x01\x8c\x23\x3d\x25\x00\x00\x00")
+
+func _0012_redact_audit_envDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__0012_redact_audit_envDownSql,
+		"0012_redact_audit_env.down.sql",
+	)
+}
+
+func _0012_redact_audit_envDownSql() (*asset, error) {
+	bytes, err := _0012_redact_audit_envDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "0012_redact_audit_env.down.sql", size: 37, mode: os.FileMode(420), modTime: time.Unix(1792427724, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var __0012_redact_audit_envUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa4\x94\xe1\x6e\xda\x3e\x14\xc5\xbf\xe7\x29\x8e\x10\x92\x89\x14\x78\x81\xfe\x8b\x44\xa9\x5b\xf8\x8b\x85\x2d\x09\xaa\xaa\x69\x42\x26\xb9\x40\xba\x60\x47\xb6\xa1\x42\x55\xdf\x7d\xb2\xd3\x75\x61\x83\xf5\xc3\x24\x84\x94\x6b\x9f\x73\x4f\xee\xcf\x4e\xbf\x8f\x83\xa8\xf6\x64\xa0\xd6\x58\xed\xcb\xaa\x00\xc9\x43\xa9\x95\xdc\x91\xb4\x10\x9a\x20\x15\x2a\x25\x37\xa4\x61\xc4\x81\x0a\x94\x12\x76\x4b\x10\xfb\xa2\xb4\xb0\x5a\x94\xd5\x55\xd0\xef\xbb\xda\xd1\xef\xd7\x54\x88\xdc\x52\xe1\x2a\x30\x62\x47\x78\x16\x47\xa7\x32\x52\xd4\x66\xab\x6c\xd3\x4b\xd9\x2d\x4c\x29\x37\x15\xa1\xd6\xea\x89\x72\x6b\x9c\x8d\x90\x05\x9e\xb7\xaa\x22\x94\xbb\x5a\x69\x6b\x82\x71\xc2\x47\x19\xc7\xdd\x22\x1e\x67\xd3\x79\x8c\x7a\xb3\xb4\xb4\xab\x07\x4d\x9f\x25\xc9\x43\xaf\x20\x93\xeb\xb2\xb6\xa5\x92\xf8\x3f\x9d\xc7\x37\x21\x12\x9e\x2d\x92\x38\x6d\x1e\x31\x4a\xd1\xed\x06\x00\x90\xf2\x19\x1f\x67\x18\x8f\x52\x8e\x87\x09\x8f\xf1\x64\x94\x5c\x2d\xed\xb1\x26\xb5\x6e\x1b\xf5\x87\xcc\xcf\x83\xf5\x87\x8c\xe4\x81\x85\xb8\x06\x53\x2b\x17\x94\x21\x9b\xf0\xd8\xdb\xb9\x5f\xe3\x60\xc8\xb6\xe5\x11\xd8\x8b\xd7\x47\x24\x0f\xaf\x2c\x42\xef\x7d\x7f\x2b\x46\xae\x44\x45\x26\xa7\xd3\xc5\x5f\xa6\x4d\xbf\xa5\xd8\x6c\x7a\xdf\xe9\x18\xb5\x62\x7b\x6a\x2e\x52\xa7\xd3\xc4\x69\x38\x82\xcf\x52\x0e\xd6\xf9\xef\x27\x85\x61\x87\x81\xc7\xb7\x61\xf4\x47\x07\xf6\xf2\xca\x4e\x8a\xe1\xc9\xd3\x5d\x32\xff\xf4\x96\x82\x44\xbe\xfd\xeb\x68\xde\x85\x61\xe3\xe1\x43\xb4\x04\x2e\x41\xd0\xed\x62\x36\x8a\xef\x17\xa3\x7b\x8e\xf4\xcb\xec\x2a\xf8\x88\xac\xb1\xc2\x52\xcf\xff\xff\x1b\x55\x6f\xd1\x1f\xb2\x56\x22\xd3\xf0\x14\x5a\x8b\xe3\x45\x9c\x5e\xe7\x40\xb6\x85\x1f\xb3\x6c\x86\xe6\x98\x9d\x3b\xaa\x21\xe6\xc9\x2d\x4f\x70\xf3\x88\x32\x8c\xc0\xbe\x7e\x63\x17\x07\xef\xe3\x2d\xa9\x22\x77\x19\xcd\xa5\xf7\x78\x98\x66\x13\x67\x3a\x8d\x47\xb3\x69\xf6\xe8\x0e\x3b\xf5\x8a\x08\xe5\x79\x2e\x67\x42\x79\xe3\xf0\x02\xa5\xc5\xe7\x5b\x47\x49\xd3\xa6\x34\x56\x1f\x07\xfe\xe2\x07\x29\xcf\xb0\xa2\xb5\xd2\x84\xeb\xf3\xe0\x9a\xd5\x30\x82\x58\x5b\xd2\x97\x76\xf9\xc5\x30\x78\x98\xf0\x84\x43\xe4\xee\x9d\x30\x8d\xd1\x63\x6f\x1f\x85\x41\xae\x49\x58\x62\x11\xde\x2b\xfb\xba\xf8\xad\x52\x50\x45\x27\x15\x33\x28\x77\xb5\xd2\x96\x85\x57\xc1\x8f\x01\x00\xba\x62\x1f\x2b\xe3\x04\x00\x00")
+
+func _0012_redact_audit_envUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__0012_redact_audit_envUpSql,
+		"0012_redact_audit_env.up.sql",
+	)
+}
+
+func _0012_redact_audit_envUpSql() (*asset, error) {
+	bytes, err := _0012_redact_audit_envUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "0012_redact_audit_env.up.sql", size: 1251, mode: os.FileMode(420), modTime: time.Unix(1792427724, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var __0013_delivery_eventsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x1f\x00\xe0\xff\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x69\x6e\x62\x6f\x78\x2e\x73\x61\x76\x65\x64\x5f\x65\x76\x65\x6e\x74\x73\x3b\x0a\x03\x00\x92\x92\x52\x70\x1f\x00\x00\x00")
+
+func _0013_delivery_eventsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__0013_delivery_eventsDownSql,
+		"0013_delivery_events.down.sql",
+	)
+}
+
+func _0013_delivery_eventsDownSql() (*asset, error) {
+	bytes, err := _0013_delivery_eventsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "0013_delivery_events.down.sql", size: 31, mode: os.FileMode(420), modTime: time.Unix(1792427724, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var __0013_delivery_eventsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x4c\x8e\xc1\x6e\xab\x30\x10\x45\xf7\x7c\xc5\xdd\x05\xa4\xc0\x0f\xbc\x95\x1f\x4c\x2a\x54\x30\x91\x71\x16\xe9\x26\x22\x78\x9a\x5a\x42\x76\x85\x81\xb4\x7f\x5f\xc9\x42\x55\xd7\x73\xe6\x9c\x9b\xe7\xe0\x8d\xdd\x12\xe0\xdf\x61\x78\xb2\x1b\xcf\xdf\x18\x66\x46\x18\x36\x36\xf0\x6e\xe4\x63\x44\x60\xff\x00\x36\xe0\x73\xf6\x23\x87\xc0\x06\xc3\x63\xb0\x2e\xc9\x73\xa4\x5c\x3c\x0a\x3c\x3f\xd8\xc1\x2e\x01\x33\x87\x75\x5a\x30\xfa\x75\x32\x70\x7e\xc1\x7d\xb7\x66\x49\xa9\x48\x68\x82\x16\xff\x1b\x82\x75\x77\xff\x55\xc4\xcb\x6d\x1f\x93\x26\x00\x7e\x73\x37\x6b\x50\x4b\x4d\x2f\xa4\x70\x56\x75\x2b\xd4\x15\xaf\x74\x85\xa2\x13\x29\x92\x25\xf5\xbb\x63\x7f\xb0\x1c\x52\x6b\x32\x74\x12\x15\x35\xa4\x09\xa5\xe8\x4b\x51\xd1\x31\x6a\x63\x09\xba\x6e\xa9\xd7\xa2\x3d\x43\x76\x1a\xf2\xd2\x34\xa8\xe8\x24\x2e\x8d\x46\xea\xfc\x33\xcd\x20\x74\x84\xf0\xd6\x49\xc2\x61\x5d\xc6\x43\x96\x64\xff\x92\x9f\x01\x00\x52\x71\x80\x88\x33\x01\x00\x00")
+
+func _0013_delivery_eventsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__0013_delivery_eventsUpSql,
+		"0013_delivery_events.up.sql",
+	)
+}
+
+func _0013_delivery_eventsUpSql() (*asset, error) {
+	bytes, err := _0013_delivery_eventsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "0013_delivery_events.up.sql", size: 307, mode: os.FileMode(420), modTime: time.Unix(1792427724, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -228,7 +669,28 @@ var _bindata = map[string]func() (*asset, error){
 	"0001_init_schema.up.sql": _0001_init_schemaUpSql,
 	"0002_tables.down.sql": _0002_tablesDownSql,
 	"0002_tables.up.sql": _0002_tablesUpSql,
-	"bindata.go": bindataGo,
+	"0003_projects.down.sql": _0003_projectsDownSql,
+	"0003_projects.up.sql": _0003_projectsUpSql,
+	"0004_events.down.sql": _0004_eventsDownSql,
+	"0004_events.up.sql": _0004_eventsUpSql,
+	"0005_event_time.down.sql": _0005_event_timeDownSql,
+	"0005_event_time.up.sql": _0005_event_timeUpSql,
+	"0006_inbox.down.sql": _0006_inboxDownSql,
+	"0006_inbox.up.sql": _0006_inboxUpSql,
+	"0007_idempotency.down.sql": _0007_idempotencyDownSql,
+	"0007_idempotency.up.sql": _0007_idempotencyUpSql,
+	"0008_requests.down.sql": _0008_requestsDownSql,
+	"0008_requests.up.sql": _0008_requestsUpSql,
+	"0009_redact_headers.down.sql": _0009_redact_headersDownSql,
+	"0009_redact_headers.up.sql": _0009_redact_headersUpSql,
+	"0010_prune_deliveries.down.sql": _0010_prune_deliveriesDownSql,
+	"0010_prune_deliveries.up.sql": _0010_prune_deliveriesUpSql,
+	"0011_project_provider.down.sql": _0011_project_providerDownSql,
+	"0011_project_provider.up.sql": _0011_project_providerUpSql,
+	"0012_redact_audit_env.down.sql": _0012_redact_audit_envDownSql,
+	"0012_redact_audit_env.up.sql": _0012_redact_audit_envUpSql,
+	"0013_delivery_events.down.sql": _0013_delivery_eventsDownSql,
+	"0013_delivery_events.up.sql": _0013_delivery_eventsUpSql,
 }
 
 // AssetDir returns the file names below a certain
@@ -275,7 +737,28 @@ var _bintree = &bintree{nil, map[string]*bintree{
 	"0001_init_schema.up.sql": &bintree{_0001_init_schemaUpSql, map[string]*bintree{}},
 	"0002_tables.down.sql": &bintree{_0002_tablesDownSql, map[string]*bintree{}},
 	"0002_tables.up.sql": &bintree{_0002_tablesUpSql, map[string]*bintree{}},
-	"bindata.go": &bintree{bindataGo, map[string]*bintree{}},
+	"0003_projects.down.sql": &bintree{_0003_projectsDownSql, map[string]*bintree{}},
+	"0003_projects.up.sql": &bintree{_0003_projectsUpSql, map[string]*bintree{}},
+	"0004_events.down.sql": &bintree{_0004_eventsDownSql, map[string]*bintree{}},
+	"0004_events.up.sql": &bintree{_0004_eventsUpSql, map[string]*bintree{}},
+	"0005_event_time.down.sql": &bintree{_0005_event_timeDownSql, map[string]*bintree{}},
+	"0005_event_time.up.sql": &bintree{_0005_event_timeUpSql, map[string]*bintree{}},
+	"0006_inbox.down.sql": &bintree{_0006_inboxDownSql, map[string]*bintree{}},
+	"0006_inbox.up.sql": &bintree{_0006_inboxUpSql, map[string]*bintree{}},
+	"0007_idempotency.down.sql": &bintree{_0007_idempotencyDownSql, map[string]*bintree{}},
+	"0007_idempotency.up.sql": &bintree{_0007_idempotencyUpSql, map[string]*bintree{}},
+	"0008_requests.down.sql": &bintree{_0008_requestsDownSql, map[string]*bintree{}},
+	"0008_requests.up.sql": &bintree{_0008_requestsUpSql, map[string]*bintree{}},
+	"0009_redact_headers.down.sql": &bintree{_0009_redact_headersDownSql, map[string]*bintree{}},
+	"0009_redact_headers.up.sql": &bintree{_0009_redact_headersUpSql, map[string]*bintree{}},
+	"0010_prune_deliveries.down.sql": &bintree{_0010_prune_deliveriesDownSql, map[string]*bintree{}},
+	"0010_prune_deliveries.up.sql": &bintree{_0010_prune_deliveriesUpSql, map[string]*bintree{}},
+	"0011_project_provider.down.sql": &bintree{_0011_project_providerDownSql, map[string]*bintree{}},
+	"0011_project_provider.up.sql": &bintree{_0011_project_providerUpSql, map[string]*bintree{}},
+	"0012_redact_audit_env.down.sql": &bintree{_0012_redact_audit_envDownSql, map[string]*bintree{}},
+	"0012_redact_audit_env.up.sql": &bintree{_0012_redact_audit_envUpSql, map[string]*bintree{}},
+	"0013_delivery_events.down.sql": &bintree{_0013_delivery_eventsDownSql, map[string]*bintree{}},
+	"0013_delivery_events.up.sql": &bintree{_0013_delivery_eventsUpSql, map[string]*bintree{}},
 }}
 
 // RestoreAsset restores an asset under the given directory
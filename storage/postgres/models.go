@@ -4,19 +4,24 @@ import (
 	"time"
 
 	"github.com/vitalyisaev2/buildgraph/common"
+	"github.com/vitalyisaev2/buildgraph/storage"
 	"github.com/vitalyisaev2/buildgraph/vcs"
 )
 
 // PushEvent
 
-var _ (vcs.PushEvent) = (*pushEvent)(nil)
+var _ (storage.StoredPushEvent) = (*pushEvent)(nil)
 
 type pushEvent struct {
 	common.Object
-	project *project
-	commits []*commit
+	ref      string
+	received time.Time
+	project  *project
+	commits  []*commit
 }
 
+func (e *pushEvent) GetRef() string          { return e.ref }
+func (e *pushEvent) GetReceived() time.Time  { return e.received }
 func (e *pushEvent) GetProject() vcs.Project { return e.project }
 func (e *pushEvent) GetCommits() []vcs.Commit {
 	result := make([]vcs.Commit, 0, len(e.commits))
@@ -32,11 +37,13 @@ var _ (vcs.Project) = (*project)(nil)
 
 type project struct {
 	common.Object
+	provider  string
 	namespace string
 	name      string
 	httpURL   string
 }
 
+func (m *project) GetProvider() string  { return m.provider }
 func (m *project) GetNamespace() string { return m.namespace }
 func (m *project) GetName() string      { return m.name }
 func (m *project) GetHTTPURL() string   { return m.httpURL }
@@ -0,0 +1,109 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/vitalyisaev2/buildgraph/config"
+	"github.com/vitalyisaev2/buildgraph/storage"
+)
+
+const (
+	// origin of project descriptions read from the database
+	projectsOrigin = "database"
+)
+
+func (s *defaultStorage) LoadProjects(ctx context.Context) (*config.ProjectsConfig, error) {
+	tx, err := s.db.BeginTx(ctx, readOnlyTransaction)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	return loadProjects(tx)
+}
+
+func (s *defaultStorage) ImportProjects(ctx context.Context, cfg *config.ProjectsConfig, actor string) (bool, error) {
+	ex, err := s.makeExecutor(ctx, "import_projects", nil)
+	if err != nil {
+		return false, err
+	}
+
+	var imported bool
+	ex.importProjects(cfg, actor, &imported)
+	ex.checkProjectGraph()
+	if err := ex.finalize(); err != nil {
+		return false, err
+	}
+	return imported, nil
+}
+
+func (s *defaultStorage) SaveProject(ctx context.Context, d *config.Description, actor string) error {
+	ex, err := s.makeExecutor(ctx, "save_project", nil)
+	if err != nil {
+		return err
+	}
+
+	ex.saveDescription(d, actor)
+	ex.checkProjectGraph()
+	return ex.finalize()
+}
+
+func (s *defaultStorage) DeleteProject(ctx context.Context, id string, actor string) error {
+	ex, err := s.makeExecutor(ctx, "delete_project", nil)
+	if err != nil {
+		return err
+	}
+
+	ex.deleteDescription(id, actor)
+	return ex.finalize()
+}
+
+func (s *defaultStorage) SaveRelation(ctx context.Context, dependency, dependent string, actor string) error {
+	ex, err := s.makeExecutor(ctx, "save_relation", nil)
+	if err != nil {
+		return err
+	}
+
+	ex.saveRelation(dependency, dependent, actor)
+	ex.checkProjectGraph()
+	return ex.finalize()
+}
+
+func (s *defaultStorage) DeleteRelation(ctx context.Context, dependency, dependent string, actor string) error {
+	ex, err := s.makeExecutor(ctx, "delete_relation", nil)
+	if err != nil {
+		return err
+	}
+
+	ex.deleteRelation(dependency, dependent, actor)
+	return ex.finalize()
+}
+
+func (s *defaultStorage) ListAuditRecords(ctx context.Context, limit int) ([]*storage.AuditRecord, error) {
+	rows, err := s.db.QueryContext(
+		ctx,
+		`SELECT id, time, actor, action, object, before, after
+		FROM registry.audit ORDER BY id DESC LIMIT $1`,
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*storage.AuditRecord
+	for rows.Next() {
+		var (
+			r             storage.AuditRecord
+			before, after []byte
+		)
+		if err := rows.Scan(&r.ID, &r.Time, &r.Actor, &r.Action, &r.Object, &before, &after); err != nil {
+			return nil, err
+		}
+		r.Before = json.RawMessage(before)
+		r.After = json.RawMessage(after)
+		result = append(result, &r)
+	}
+	return result, rows.Err()
+}
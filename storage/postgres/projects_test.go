@@ -0,0 +1,104 @@
+package postgres
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/vitalyisaev2/buildgraph/config"
+	"github.com/vitalyisaev2/buildgraph/storage"
+)
+
+func (s *storageSuite) TestProjects() {
+	build := &config.BuildSpec{Command: "make", Timeout: 10 * time.Minute, Env: map[string]string{"TOKEN": "s3cr3t"}}
+	cfg := &config.ProjectsConfig{
+		Import: config.ImportOverwrite,
+		Descriptions: []*config.Description{
+			{ID: "p1", Namespace: "namespace1", Name: "project1", Build: build},
+			{ID: "p2", Namespace: "namespace1", Name: "project2"},
+		},
+		Relations: map[string][]string{"p1": {"p2"}},
+	}
+	imported, err := s.storage.ImportProjects(s.ctx, cfg, "test")
+	s.Require().NoError(err)
+	s.True(imported)
+
+	// seed doesn't touch projects that are already stored
+	seed := &config.ProjectsConfig{
+		Import:       config.ImportSeed,
+		Descriptions: []*config.Description{{ID: "p3", Namespace: "namespace2", Name: "project1"}},
+	}
+	imported, err = s.storage.ImportProjects(s.ctx, seed, "test")
+	s.Require().NoError(err)
+	s.False(imported)
+
+	stored, err := s.storage.LoadProjects(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Len(stored.Descriptions, 2)
+	s.Equal(build, stored.Descriptions[0].Build)
+	s.Nil(stored.Descriptions[1].Build)
+	s.Equal(cfg.Relations, stored.Relations)
+
+	p3 := &config.Description{ID: "p3", Namespace: "namespace2", Name: "project1"}
+	s.Require().NoError(s.storage.SaveProject(s.ctx, p3, "alice"))
+	p3.Name = "project3"
+	s.Require().NoError(s.storage.SaveProject(s.ctx, p3, "bob"))
+	s.Require().NoError(s.storage.SaveRelation(s.ctx, "p2", "p3", "alice"))
+
+	// changes introducing cycles are rejected as a whole
+	err = s.storage.SaveRelation(s.ctx, "p3", "p1", "alice")
+	s.IsType(&storage.ConflictError{}, err)
+	err = s.storage.SaveRelation(s.ctx, "p3", "missing", "alice")
+	s.IsType(&storage.ConflictError{}, err)
+	cyclic := &config.ProjectsConfig{
+		Import:       config.ImportOverwrite,
+		Descriptions: cfg.Descriptions,
+		Relations:    map[string][]string{"p1": {"p2"}, "p2": {"p1"}},
+	}
+	_, err = s.storage.ImportProjects(s.ctx, cyclic, "test")
+	s.IsType(&storage.ConflictError{}, err)
+
+	stored, err = s.storage.LoadProjects(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Len(stored.Descriptions, 3)
+	s.Equal("project3", stored.Descriptions[2].Name)
+	s.Equal(map[string][]string{"p1": {"p2"}, "p2": {"p3"}}, stored.Relations)
+
+	// relations are removed along with the project
+	s.Equal(storage.ErrNotFound, s.storage.DeleteRelation(s.ctx, "p3", "p1", "alice"))
+	s.Require().NoError(s.storage.DeleteProject(s.ctx, "p3", "alice"))
+	s.Equal(storage.ErrNotFound, s.storage.DeleteProject(s.ctx, "p3", "alice"))
+	stored, err = s.storage.LoadProjects(s.ctx)
+	s.Require().NoError(err)
+	s.Len(stored.Descriptions, 2)
+	s.Equal(cfg.Relations, stored.Relations)
+
+	// every change is audited, the rejected ones are not
+	records, err := s.storage.ListAuditRecords(s.ctx, 5)
+	s.Require().NoError(err)
+	s.Require().Len(records, 5)
+	for i, expected := range []struct{ actor, action, object string }{
+		{"alice", "project.delete", "p3"},
+		{"alice", "relation.create", "p2 -> p3"},
+		{"bob", "project.update", "p3"},
+		{"alice", "project.create", "p3"},
+		{"test", "projects.import", "*"},
+	} {
+		s.Equal(expected.actor, records[i].Actor)
+		s.Equal(expected.action, records[i].Action)
+		s.Equal(expected.object, records[i].Object)
+	}
+
+	var before, after config.Description
+	s.Require().NoError(json.Unmarshal(records[2].Before, &before))
+	s.Require().NoError(json.Unmarshal(records[2].After, &after))
+	s.Equal("project1", before.Name)
+	s.Equal("project3", after.Name)
+	s.Nil(records[0].After)
+	s.Nil(records[3].Before)
+
+	// while values of build environment are kept out of it
+	var snapshot config.ProjectsConfig
+	s.Require().NoError(json.Unmarshal(records[4].After, &snapshot))
+	s.Require().Len(snapshot.Descriptions, 2)
+	s.Equal(map[string]string{"TOKEN": "<redacted>"}, snapshot.Descriptions[0].Build.Env)
+}
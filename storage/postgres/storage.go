@@ -4,33 +4,42 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	_ "github.com/lib/pq"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/sirupsen/logrus"
 
 	"github.com/mattes/migrate"
 	_ "github.com/mattes/migrate/database/postgres"
 	bindata "github.com/mattes/migrate/source/go-bindata"
 
+	"github.com/vitalyisaev2/buildgraph/common"
 	"github.com/vitalyisaev2/buildgraph/config"
+	"github.com/vitalyisaev2/buildgraph/metrics"
 	"github.com/vitalyisaev2/buildgraph/storage"
 	"github.com/vitalyisaev2/buildgraph/storage/postgres/migrations"
 	"github.com/vitalyisaev2/buildgraph/vcs"
 )
 
-//var _ storage.Storage = (*defaultStorage)(nil)
+var _ storage.Storage = (*defaultStorage)(nil)
 
 var (
 	readOnlyTransaction = &sql.TxOptions{ReadOnly: true}
 )
 
 type defaultStorage struct {
-	db     *sql.DB
-	logger *logrus.Logger
+	db        *sql.DB
+	logger    *logrus.Logger
+	poolStats prometheus.Collector // exports sql.DB.Stats()
+
+	// version of the latest migration
+	schemaVersion uint
 }
 
 func (s *defaultStorage) SavePushEvent(ctx context.Context, event vcs.PushEvent) error {
-	ex, err := s.makeExecutor(ctx, nil)
+	ex, err := s.makeExecutor(ctx, "save_push_event", nil)
 	if err != nil {
 		return err
 	}
@@ -44,36 +53,69 @@ func (s *defaultStorage) SavePushEvent(ctx context.Context, event vcs.PushEvent)
 	return ex.finalize()
 }
 
-/*
-func (s *defaultStorage) GetAuthor(ctx context.Context, name, email string) (storage.Author, error) {
+func (s *defaultStorage) SaveTagPushEvent(ctx context.Context, event vcs.TagPushEvent) error {
+	ex, err := s.makeExecutor(ctx, "save_tag_push_event", nil)
+	if err != nil {
+		return err
+	}
 
-	var result *author
+	ex.saveProject(event.GetProject())
+	ex.saveTagPushEvent(event)
+	return ex.finalize()
+}
 
-	f := func(tx *sql.Tx) error {
-		var id int
-		err := tx.QueryRowContext(
-			ctx,
-			`SELECT id FROM vcs.authors WHERE name = $1 AND email = $2;`,
-			name, email).Scan(&id)
-		if err != nil {
-			return err
-		}
-		result = &author{
-			model: model{id: id},
-			name:  name,
-			email: email,
-		}
-		return nil
+func (s *defaultStorage) SaveMergeRequestEvent(ctx context.Context, event vcs.MergeRequestEvent) error {
+	ex, err := s.makeExecutor(ctx, "save_merge_request_event", nil)
+	if err != nil {
+		return err
 	}
 
-	if err := s.performTransaction(ctx, f, readOnlyTransaction); err != nil {
-		return nil, err
+	ex.saveProject(event.GetProject())
+	ex.saveMergeRequestEvent(event)
+	return ex.finalize()
+}
+
+func (s *defaultStorage) SavePipelineEvent(ctx context.Context, event vcs.PipelineEvent) error {
+	ex, err := s.makeExecutor(ctx, "save_pipeline_event", nil)
+	if err != nil {
+		return err
+	}
+
+	ex.saveProject(event.GetProject())
+	ex.savePipelineEvent(event)
+	return ex.finalize()
+}
+
+func (s *defaultStorage) SaveJobEvent(ctx context.Context, event vcs.JobEvent) error {
+	ex, err := s.makeExecutor(ctx, "save_job_event", nil)
+	if err != nil {
+		return err
 	}
-	return result, nil
+
+	ex.saveProject(event.GetProject())
+	ex.saveJobEvent(event)
+	return ex.finalize()
+}
+
+func (s *defaultStorage) SaveDeliveryEvents(ctx context.Context, deliveryID common.ObjectID, events []vcs.Event) (bool, error) {
+	ex, err := s.makeExecutor(ctx, "save_delivery_events", nil)
+	if err != nil {
+		return false, err
+	}
+
+	var saved bool
+	ex.claimDeliveryEvents(deliveryID, &saved)
+	for _, event := range events {
+		ex.saveAnyEvent(event)
+	}
+	if err := ex.finalize(); err != nil {
+		return false, err
+	}
+	return saved, nil
 }
-*/
 
 func (s *defaultStorage) Stop() {
+	prometheus.Unregister(s.poolStats)
 	if err := s.db.Close(); err != nil {
 		s.logger.WithError(err).Error("Failed to close database")
 	}
@@ -83,19 +125,24 @@ func (s *defaultStorage) Stop() {
 // executes it, than commits or rolls back
 func (s *defaultStorage) makeExecutor(
 	ctx context.Context,
+	operation string,
 	options *sql.TxOptions,
 ) (*executor, error) {
+	started := time.Now()
 
 	// start tx
 	tx, err := s.db.BeginTx(ctx, options)
 	if err != nil {
+		metrics.StorageTransactionFailures.WithLabelValues(operation, beginStep).Inc()
 		return nil, err
 	}
 
 	// wrap transaction into executor
 	ex := &executor{
-		tx:     tx,
-		logger: s.logger,
+		tx:        tx,
+		logger:    s.logger,
+		operation: operation,
+		started:   started,
 	}
 
 	return ex, nil
@@ -103,6 +150,9 @@ func (s *defaultStorage) makeExecutor(
 
 func NewStorage(logger *logrus.Logger, cfg *config.PostgresConfig) (storage.Storage, error) {
 
+	// Both migrations and client share the same connection settings (including TLS)
+	dsn := cfg.URL()
+
 	// Prepare migrations
 	resource := bindata.Resource(
 		migrations.AssetNames(),
@@ -111,6 +161,11 @@ func NewStorage(logger *logrus.Logger, cfg *config.PostgresConfig) (storage.Stor
 		},
 	)
 
+	schemaVersion, err := latestMigration(migrations.AssetNames())
+	if err != nil {
+		return nil, err
+	}
+
 	// Create migration driver
 	driver, err := bindata.WithInstance(resource)
 	if err != nil {
@@ -118,7 +173,7 @@ func NewStorage(logger *logrus.Logger, cfg *config.PostgresConfig) (storage.Stor
 	}
 
 	// Create new source with instance
-	m, err := migrate.NewWithSourceInstance("go-bindata", driver, cfg.URL())
+	m, err := migrate.NewWithSourceInstance("go-bindata", driver, dsn)
 	if err != nil {
 		return nil, fmt.Errorf("failed to prepare migration: %v", err)
 	}
@@ -134,14 +189,19 @@ func NewStorage(logger *logrus.Logger, cfg *config.PostgresConfig) (storage.Stor
 	}
 
 	// Prepare connection
-	db, err := sql.Open("postgres", cfg.URL())
+	db, err := sql.Open("postgres", dsn)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize DB client: %v", err)
 	}
 
 	s := &defaultStorage{
-		db:     db,
-		logger: logger,
+		db:            db,
+		logger:        logger,
+		poolStats:     collectors.NewDBStatsCollector(db, "buildgraph"),
+		schemaVersion: schemaVersion,
+	}
+	if err = prometheus.Register(s.poolStats); err != nil {
+		logger.WithError(err).Warn("failed to register connection pool metrics")
 	}
 	return s, nil
 }
@@ -0,0 +1,135 @@
+package bitbucket
+
+import (
+	"strings"
+
+	"github.com/vitalyisaev2/buildgraph/common"
+	"github.com/vitalyisaev2/buildgraph/vcs"
+)
+
+// RefsChangedEvent is a payload of repo:refs_changed hook;
+// it describes several branch or tag updates at once
+type RefsChangedEvent struct {
+	EventKey   string      `json:"eventKey"`
+	Date       string      `json:"date"`
+	Actor      *User       `json:"actor"`
+	Repository *Repository `json:"repository"`
+	Changes    []*Change   `json:"changes"`
+}
+
+// Change describes update of a single ref
+type Change struct {
+	Ref      *Ref   `json:"ref"`
+	RefID    string `json:"refId"`
+	FromHash string `json:"fromHash"`
+	ToHash   string `json:"toHash"`
+	Type     string `json:"type"` // ADD, UPDATE or DELETE
+}
+
+type Ref struct {
+	ID        string `json:"id"`
+	DisplayID string `json:"displayId"`
+	Type      string `json:"type"` // BRANCH or TAG
+}
+
+// PushEvent is a branch update; refs_changed payloads
+// don't list pushed commits, so there are none
+
+var _ vcs.PushEvent = (*PushEvent)(nil)
+
+type PushEvent struct {
+	common.Object
+	repository *Repository
+	change     *Change
+}
+
+func (e *PushEvent) GetRef() string { return e.change.RefID }
+
+func (e *PushEvent) GetProject() vcs.Project { return e.repository }
+
+func (e *PushEvent) GetCommits() []vcs.Commit { return []vcs.Commit{} }
+
+// TagPushEvent is a tag update
+
+var _ vcs.TagPushEvent = (*TagPushEvent)(nil)
+
+type TagPushEvent struct {
+	common.Object
+	repository *Repository
+	change     *Change
+}
+
+func (e *TagPushEvent) GetProject() vcs.Project { return e.repository }
+
+func (e *TagPushEvent) GetTag() string {
+	if e.change.Ref != nil && e.change.Ref.DisplayID != "" {
+		return e.change.Ref.DisplayID
+	}
+	return strings.TrimPrefix(e.change.RefID, tagRefPrefix)
+}
+
+func (e *TagPushEvent) GetCommit() string {
+	if e.change.Type == changeDelete {
+		return ""
+	}
+	return e.change.ToHash
+}
+
+// Repository
+
+var _ vcs.Project = (*Repository)(nil)
+
+type Repository struct {
+	common.Object
+	ID      int      `json:"id"`
+	Slug    string   `json:"slug"`
+	Name    string   `json:"name"`
+	Project *Project `json:"project"`
+	Links   *Links   `json:"links"`
+}
+
+type Project struct {
+	Key  string `json:"key"`
+	Name string `json:"name"`
+}
+
+type Links struct {
+	Clone []*Link `json:"clone"`
+}
+
+type Link struct {
+	Href string `json:"href"`
+	Name string `json:"name"`
+}
+
+func (r *Repository) GetProvider() string { return ProviderName }
+
+func (r *Repository) GetName() string { return r.Slug }
+
+// GetNamespace returns key of Bitbucket project the repository belongs to
+func (r *Repository) GetNamespace() string {
+	if r.Project == nil {
+		return ""
+	}
+	return r.Project.Key
+}
+
+func (r *Repository) GetHTTPURL() string {
+	if r.Links == nil {
+		return ""
+	}
+	for _, link := range r.Links.Clone {
+		if link.Name == "http" {
+			return link.Href
+		}
+	}
+	return ""
+}
+
+// User is an account of Bitbucket
+
+type User struct {
+	Name         string `json:"name"`
+	EmailAddress string `json:"emailAddress"`
+	DisplayName  string `json:"displayName"`
+}
@@ -0,0 +1,126 @@
+package bitbucket
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/vitalyisaev2/buildgraph/config"
+	"github.com/vitalyisaev2/buildgraph/vcs"
+)
+
+const (
+	// EventHeader contains name of the hook
+	EventHeader = "X-Event-Key"
+	// SignatureHeader contains HMAC-SHA256 of the payload
+	SignatureHeader = "X-Hub-Signature"
+	// RequestIDHeader contains UUID of the delivery
+	RequestIDHeader = "X-Request-Id"
+)
+
+// Values of X-Event-Key header
+const (
+	RefsChangedHook = "repo:refs_changed"
+	PingHook        = "diagnostics:ping"
+)
+
+const (
+	tagRefPrefix    = "refs/tags/"
+	signaturePrefix = "sha256="
+	refTypeTag      = "TAG"
+	changeDelete    = "DELETE"
+)
+
+// ProviderName is the name Bitbucket provider is registered with
+const ProviderName = "bitbucket"
+
+var _ vcs.Provider = (*Provider)(nil)
+
+// Provider accepts Bitbucket Server (Data Center) webhook deliveries
+type Provider struct {
+	cfg func() *config.SignedVCSConfig
+}
+
+// NewProvider returns provider reading webhook secrets
+// from the section returned by cfg (which may be nil)
+func NewProvider(cfg func() *config.SignedVCSConfig) *Provider {
+	return &Provider{cfg: cfg}
+}
+
+func (p *Provider) Name() string { return ProviderName }
+
+// Detect checks presence of X-Event-Key header
+func (p *Provider) Detect(header http.Header) bool { return header.Get(EventHeader) != "" }
+
+// DeliveryID returns value of X-Request-Id header
+func (p *Provider) DeliveryID(header http.Header) string { return header.Get(RequestIDHeader) }
+
+func (p *Provider) DeliveryHeaders() []string { return []string{RequestIDHeader} }
+
+// Secured returns false only if deliveries are accepted without authentication
+// explicitly; otherwise deliveries are rejected until secrets are configured
+func (p *Provider) Secured() bool {
+	return !p.section().Insecure()
+}
+
+func (p *Provider) Authenticate(d *vcs.Delivery) error {
+	secrets := p.section().Secrets()
+	if len(secrets) == 0 {
+		if p.section().Insecure() {
+			return nil
+		}
+		return vcs.ErrNoSecrets
+	}
+
+	signature := d.Header.Get(SignatureHeader)
+	values := make([]string, 0, len(secrets))
+	for _, secret := range secrets {
+		values = append(values, secret.Value())
+	}
+	if !strings.HasPrefix(signature, signaturePrefix) ||
+		!vcs.VerifyHMAC(d.Payload, strings.TrimPrefix(signature, signaturePrefix), values) {
+		return fmt.Errorf("wrong %s header value", SignatureHeader)
+	}
+	return nil
+}
+
+// SecretHeaders returns nothing, the secret is used only to sign payloads
+func (p *Provider) SecretHeaders() []string { return nil }
+
+func (p *Provider) RestoreSecrets(*vcs.Delivery) {}
+
+// Decode turns every ref change of the delivery into a separate event
+func (p *Provider) Decode(d *vcs.Delivery) ([]vcs.Event, error) {
+	switch hook := d.Header.Get(EventHeader); hook {
+	case PingHook:
+		return nil, nil
+	case RefsChangedHook:
+		var payload RefsChangedEvent
+		if err := json.Unmarshal(d.Payload, &payload); err != nil {
+			return nil, err
+		}
+		if payload.Repository == nil {
+			return nil, fmt.Errorf("%s payload has no repository", hook)
+		}
+
+		events := make([]vcs.Event, 0, len(payload.Changes))
+		for _, change := range payload.Changes {
+			if change.Ref != nil && change.Ref.Type == refTypeTag {
+				events = append(events, &TagPushEvent{repository: payload.Repository, change: change})
+			} else {
+				events = append(events, &PushEvent{repository: payload.Repository, change: change})
+			}
+		}
+		return events, nil
+	default:
+		return nil, fmt.Errorf("unsupported Bitbucket hook: '%s'", hook)
+	}
+}
+
+func (p *Provider) section() *config.SignedVCSConfig {
+	if p.cfg == nil {
+		return nil
+	}
+	return p.cfg()
+}
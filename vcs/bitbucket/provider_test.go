@@ -0,0 +1,81 @@
+package bitbucket
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vitalyisaev2/buildgraph/config"
+	"github.com/vitalyisaev2/buildgraph/vcs"
+)
+
+func readDelivery(t *testing.T, path string, header http.Header) *vcs.Delivery {
+	payload, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &vcs.Delivery{Header: header, Payload: payload}
+}
+
+func TestProviderDecode(t *testing.T) {
+	p := &Provider{}
+
+	d := readDelivery(t, "./test/refs_changed.json", http.Header{EventHeader: {RefsChangedHook}})
+	events, err := p.Decode(d)
+	assert.NoError(t, err)
+	if assert.Len(t, events, 2) {
+		push := events[0].(vcs.PushEvent)
+		assert.Equal(t, "refs/heads/master", push.GetRef())
+		assert.Empty(t, push.GetCommits())
+		assert.Equal(t, "NS1", push.GetProject().GetNamespace())
+		assert.Equal(t, "project1", push.GetProject().GetName())
+		assert.Equal(t, "https://bitbucket.example.com/scm/ns1/project1.git", push.GetProject().GetHTTPURL())
+		assert.Equal(t, ProviderName, push.GetProject().GetProvider())
+
+		tag := events[1].(vcs.TagPushEvent)
+		assert.Equal(t, "v1.0.0", tag.GetTag())
+		assert.Equal(t, "178864a7d521b6f5e720b386b2c2b0ef8563e0dc", tag.GetCommit())
+	}
+
+	d = readDelivery(t, "./test/ping.json", http.Header{EventHeader: {PingHook}})
+	events, err = p.Decode(d)
+	assert.NoError(t, err)
+	assert.Empty(t, events)
+
+	d.Header.Set(EventHeader, "pr:opened")
+	_, err = p.Decode(d)
+	assert.Error(t, err)
+}
+
+func TestProviderAuthenticate(t *testing.T) {
+	var cfg *config.SignedVCSConfig
+	p := NewProvider(func() *config.SignedVCSConfig { return cfg })
+	d := readDelivery(t, "./test/refs_changed.json", http.Header{})
+
+	// no secrets configured: deliveries are rejected unless accepted explicitly
+	assert.True(t, p.Secured())
+	assert.Equal(t, vcs.ErrNoSecrets, p.Authenticate(d))
+	cfg = &config.SignedVCSConfig{Webhook: &config.SignedWebhookConfig{Insecure: true}}
+	assert.False(t, p.Secured())
+	assert.NoError(t, p.Authenticate(d))
+
+	// secrets are read on every call
+	cfg = &config.SignedVCSConfig{Webhook: &config.SignedWebhookConfig{Secret: "secret"}}
+	assert.True(t, p.Secured())
+	assert.Error(t, p.Authenticate(d))
+
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write(d.Payload)
+	signature := hex.EncodeToString(mac.Sum(nil))
+
+	// prefix is mandatory
+	d.Header.Set(SignatureHeader, signature)
+	assert.Error(t, p.Authenticate(d))
+	d.Header.Set(SignatureHeader, signaturePrefix+signature)
+	assert.NoError(t, p.Authenticate(d))
+}
@@ -0,0 +1,150 @@
+package gitea
+
+import (
+	"strings"
+	"time"
+
+	"github.com/vitalyisaev2/buildgraph/common"
+	"github.com/vitalyisaev2/buildgraph/vcs"
+)
+
+// PushEvent
+
+var _ vcs.PushEvent = (*PushEvent)(nil)
+
+type PushEvent struct {
+	common.Object
+	Ref        string      `json:"ref"`
+	Before     string      `json:"before"`
+	After      string      `json:"after"`
+	CompareURL string      `json:"compare_url"`
+	Repository *Repository `json:"repository"`
+	Pusher     *User       `json:"pusher"`
+	Commits    []*Commit   `json:"commits"`
+}
+
+func (e *PushEvent) GetRef() string { return e.Ref }
+
+func (e *PushEvent) GetProject() vcs.Project { return e.Repository }
+
+func (e *PushEvent) GetCommits() []vcs.Commit {
+	results := make([]vcs.Commit, 0, len(e.Commits))
+	for _, c := range e.Commits {
+		results = append(results, c)
+	}
+	return results
+}
+
+// TagPushEvent is a push event related to tag
+
+var _ vcs.TagPushEvent = (*TagPushEvent)(nil)
+
+type TagPushEvent struct {
+	common.Object
+	push *PushEvent
+}
+
+func (e *TagPushEvent) GetProject() vcs.Project { return e.push.Repository }
+
+func (e *TagPushEvent) GetTag() string { return strings.TrimPrefix(e.push.Ref, tagRefPrefix) }
+
+func (e *TagPushEvent) GetCommit() string {
+	if e.push.After == zeroSHA {
+		return ""
+	}
+	return e.push.After
+}
+
+// Repository
+
+var _ vcs.Project = (*Repository)(nil)
+
+type Repository struct {
+	common.Object
+	ID            int    `json:"id"`
+	Owner         *User  `json:"owner"`
+	Name          string `json:"name"`
+	FullName      string `json:"full_name"`
+	HTMLURL       string `json:"html_url"`
+	CloneURL      string `json:"clone_url"`
+	SSHURL        string `json:"ssh_url"`
+	DefaultBranch string `json:"default_branch"`
+}
+
+func (r *Repository) GetProvider() string { return ProviderName }
+
+func (r *Repository) GetName() string { return r.Name }
+
+// GetNamespace returns owner of the repository (user or organization)
+func (r *Repository) GetNamespace() string {
+	if r.Owner != nil && r.Owner.Username != "" {
+		return r.Owner.Username
+	}
+	if i := strings.LastIndex(r.FullName, "/"); i >= 0 {
+		return r.FullName[:i]
+	}
+	return ""
+}
+
+func (r *Repository) GetHTTPURL() string { return r.CloneURL }
+
+// Commit
+
+var _ vcs.Commit = (*Commit)(nil)
+
+type Commit struct {
+	common.Object
+	Hash      string   `json:"id"`
+	Message   string   `json:"message"`
+	Timestamp string   `json:"timestamp"`
+	URL       string   `json:"url"`
+	Author    *Author  `json:"author"`
+	Added     []string `json:"added"`
+	Modified  []string `json:"modified"`
+	Removed   []string `json:"removed"`
+}
+
+func (c *Commit) GetHash() string { return c.Hash }
+
+func (c *Commit) GetMessage() string { return c.Message }
+
+// GetTimestamp returns commit time; payloads are validated
+// on decoding, so the timestamp is always well-formed
+func (c *Commit) GetTimestamp() time.Time {
+	t, _ := time.Parse(time.RFC3339, c.Timestamp)
+	return t
+}
+
+func (c *Commit) GetAuthor() vcs.Author { return c.Author }
+
+func (c *Commit) GetURL() string { return c.URL }
+
+func (c *Commit) GetAdded() []string { return c.Added }
+
+func (c *Commit) GetModified() []string { return c.Modified }
+
+func (c *Commit) GetRemoved() []string { return c.Removed }
+
+// Author
+
+var _ vcs.Author = (*Author)(nil)
+
+type Author struct {
+	common.Object
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Username string `json:"username"`
+}
+
+func (a *Author) GetName() string  { return a.Name }
+func (a *Author) GetEmail() string { return a.Email }
+
+// User is an account of Gitea
+
+type User struct {
+	ID       int    `json:"id"`
+	Login    string `json:"login"`
+	Username string `json:"username"`
+	FullName string `json:"full_name"`
+	Email    string `json:"email"`
+}
@@ -0,0 +1,140 @@
+package gitea
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/vitalyisaev2/buildgraph/config"
+	"github.com/vitalyisaev2/buildgraph/vcs"
+)
+
+// Forgejo sends both its own headers and the Gitea ones,
+// the former are preferred when present
+const (
+	EventHeader            = "X-Gitea-Event"
+	SignatureHeader        = "X-Gitea-Signature"
+	ForgejoEventHeader     = "X-Forgejo-Event"
+	ForgejoSignatureHeader = "X-Forgejo-Signature"
+	DeliveryHeader         = "X-Gitea-Delivery"
+	ForgejoDeliveryHeader  = "X-Forgejo-Delivery"
+)
+
+// Values of event header
+const (
+	PushHook = "push"
+)
+
+const (
+	tagRefPrefix = "refs/tags/"
+	zeroSHA      = "0000000000000000000000000000000000000000"
+)
+
+// ProviderName is the name Gitea provider is registered with
+const ProviderName = "gitea"
+
+var _ vcs.Provider = (*Provider)(nil)
+
+// Provider accepts Gitea and Forgejo webhook deliveries
+type Provider struct {
+	cfg func() *config.SignedVCSConfig
+}
+
+// NewProvider returns provider reading webhook secrets
+// from the section returned by cfg (which may be nil)
+func NewProvider(cfg func() *config.SignedVCSConfig) *Provider {
+	return &Provider{cfg: cfg}
+}
+
+func (p *Provider) Name() string { return ProviderName }
+
+// Detect checks presence of Gitea or Forgejo event header
+func (p *Provider) Detect(header http.Header) bool {
+	return header.Get(EventHeader) != "" || header.Get(ForgejoEventHeader) != ""
+}
+
+// DeliveryID returns value of X-Forgejo-Delivery or X-Gitea-Delivery header
+func (p *Provider) DeliveryID(header http.Header) string {
+	return headerValue(&vcs.Delivery{Header: header}, ForgejoDeliveryHeader, DeliveryHeader)
+}
+
+func (p *Provider) DeliveryHeaders() []string {
+	return []string{ForgejoDeliveryHeader, DeliveryHeader}
+}
+
+// Secured returns false only if deliveries are accepted without authentication
+// explicitly; otherwise deliveries are rejected until secrets are configured
+func (p *Provider) Secured() bool {
+	return !p.section().Insecure()
+}
+
+// Authenticate checks hex-encoded HMAC-SHA256 of the payload
+func (p *Provider) Authenticate(d *vcs.Delivery) error {
+	secrets := p.section().Secrets()
+	if len(secrets) == 0 {
+		if p.section().Insecure() {
+			return nil
+		}
+		return vcs.ErrNoSecrets
+	}
+
+	signature := headerValue(d, ForgejoSignatureHeader, SignatureHeader)
+	values := make([]string, 0, len(secrets))
+	for _, secret := range secrets {
+		values = append(values, secret.Value())
+	}
+	if !vcs.VerifyHMAC(d.Payload, signature, values) {
+		return fmt.Errorf("wrong %s header value", SignatureHeader)
+	}
+	return nil
+}
+
+// SecretHeaders returns nothing, Gitea and Forgejo send only HMAC signatures
+func (p *Provider) SecretHeaders() []string { return nil }
+
+func (p *Provider) RestoreSecrets(*vcs.Delivery) {}
+
+// Decode turns delivery into events; pushes of tags are turned into TagPushEvent
+func (p *Provider) Decode(d *vcs.Delivery) ([]vcs.Event, error) {
+	hook := headerValue(d, ForgejoEventHeader, EventHeader)
+	if hook != PushHook {
+		return nil, fmt.Errorf("unsupported Gitea hook: '%s'", hook)
+	}
+
+	var push PushEvent
+	if err := json.Unmarshal(d.Payload, &push); err != nil {
+		return nil, err
+	}
+	if push.Repository == nil {
+		return nil, fmt.Errorf("%s payload has no repository", hook)
+	}
+	for _, c := range push.Commits {
+		if _, err := time.Parse(time.RFC3339, c.Timestamp); err != nil {
+			return nil, fmt.Errorf("commit %s: invalid timestamp: %v", c.Hash, err)
+		}
+	}
+
+	if strings.HasPrefix(push.Ref, tagRefPrefix) {
+		return []vcs.Event{&TagPushEvent{push: &push}}, nil
+	}
+	return []vcs.Event{&push}, nil
+}
+
+func (p *Provider) section() *config.SignedVCSConfig {
+	if p.cfg == nil {
+		return nil
+	}
+	return p.cfg()
+}
+
+// headerValue returns value of the first header present in delivery
+func headerValue(d *vcs.Delivery, names ...string) string {
+	for _, name := range names {
+		if value := d.Header.Get(name); value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,93 @@
+package gitea
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vitalyisaev2/buildgraph/config"
+	"github.com/vitalyisaev2/buildgraph/vcs"
+)
+
+func readDelivery(t *testing.T, path string, header http.Header) *vcs.Delivery {
+	payload, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &vcs.Delivery{Header: header, Payload: payload}
+}
+
+func sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestProviderDecode(t *testing.T) {
+	p := &Provider{}
+
+	d := readDelivery(t, "./test/push.json", http.Header{EventHeader: {PushHook}})
+	events, err := p.Decode(d)
+	assert.NoError(t, err)
+	if assert.Len(t, events, 1) {
+		push := events[0].(vcs.PushEvent)
+		assert.Equal(t, "refs/heads/develop", push.GetRef())
+		assert.Equal(t, "namespace1", push.GetProject().GetNamespace())
+		assert.Equal(t, "project1", push.GetProject().GetName())
+		assert.Equal(t, "http://localhost:3000/namespace1/project1.git", push.GetProject().GetHTTPURL())
+		assert.Equal(t, ProviderName, push.GetProject().GetProvider())
+		if assert.Len(t, push.GetCommits(), 1) {
+			commit := push.GetCommits()[0]
+			assert.Equal(t, "bffeb74224043ba2feb48d137756c8a9331c449a", commit.GetHash())
+			assert.Equal(t, "someone@gitea.io", commit.GetAuthor().GetEmail())
+			assert.False(t, commit.GetTimestamp().IsZero())
+		}
+	}
+
+	// Forgejo headers
+	d = readDelivery(t, "./test/push_tag.json", http.Header{ForgejoEventHeader: {PushHook}})
+	events, err = p.Decode(d)
+	assert.NoError(t, err)
+	if assert.Len(t, events, 1) {
+		tag := events[0].(vcs.TagPushEvent)
+		assert.Equal(t, "v1.0.0", tag.GetTag())
+		assert.Equal(t, "bffeb74224043ba2feb48d137756c8a9331c449a", tag.GetCommit())
+	}
+
+	d.Header = http.Header{EventHeader: {"issues"}}
+	_, err = p.Decode(d)
+	assert.Error(t, err)
+}
+
+func TestProviderAuthenticate(t *testing.T) {
+	var cfg *config.SignedVCSConfig
+	p := NewProvider(func() *config.SignedVCSConfig { return cfg })
+	d := readDelivery(t, "./test/push.json", http.Header{})
+
+	// no secrets configured: deliveries are rejected unless accepted explicitly
+	assert.True(t, p.Secured())
+	assert.Equal(t, vcs.ErrNoSecrets, p.Authenticate(d))
+	cfg = &config.SignedVCSConfig{Webhook: &config.SignedWebhookConfig{Insecure: true}}
+	assert.False(t, p.Secured())
+	assert.NoError(t, p.Authenticate(d))
+
+	// secrets are read on every call
+	cfg = &config.SignedVCSConfig{
+		Webhook: &config.SignedWebhookConfig{Secret: "current", Secrets: []config.Secret{"previous"}},
+	}
+	assert.True(t, p.Secured())
+	assert.Error(t, p.Authenticate(d))
+
+	d.Header.Set(SignatureHeader, sign("previous", d.Payload))
+	assert.NoError(t, p.Authenticate(d))
+
+	d.Header.Set(ForgejoSignatureHeader, sign("other", d.Payload))
+	assert.Error(t, p.Authenticate(d))
+	d.Header.Set(ForgejoSignatureHeader, sign("current", d.Payload))
+	assert.NoError(t, p.Authenticate(d))
+}
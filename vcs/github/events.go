@@ -0,0 +1,214 @@
+package github
+
+import (
+	"strings"
+	"time"
+
+	"github.com/vitalyisaev2/buildgraph/common"
+	"github.com/vitalyisaev2/buildgraph/vcs"
+)
+
+// PushEvent
+
+var _ vcs.PushEvent = (*PushEvent)(nil)
+
+type PushEvent struct {
+	common.Object
+	Ref        string      `json:"ref"`
+	Before     string      `json:"before"`
+	After      string      `json:"after"`
+	Created    bool        `json:"created"`
+	Deleted    bool        `json:"deleted"`
+	Forced     bool        `json:"forced"`
+	Repository *Repository `json:"repository"`
+	Pusher     *Author     `json:"pusher"`
+	Commits    []*Commit   `json:"commits"`
+	HeadCommit *Commit     `json:"head_commit"`
+}
+
+func (e *PushEvent) GetRef() string { return e.Ref }
+
+func (e *PushEvent) GetProject() vcs.Project { return e.Repository }
+
+func (e *PushEvent) GetCommits() []vcs.Commit {
+	results := make([]vcs.Commit, 0, len(e.Commits))
+	for _, c := range e.Commits {
+		results = append(results, c)
+	}
+	return results
+}
+
+// TagPushEvent is a push event related to tag
+
+var _ vcs.TagPushEvent = (*TagPushEvent)(nil)
+
+type TagPushEvent struct {
+	common.Object
+	push *PushEvent
+}
+
+func (e *TagPushEvent) GetProject() vcs.Project { return e.push.Repository }
+
+func (e *TagPushEvent) GetTag() string { return strings.TrimPrefix(e.push.Ref, tagRefPrefix) }
+
+func (e *TagPushEvent) GetCommit() string {
+	if e.push.Deleted {
+		return ""
+	}
+	return e.push.After
+}
+
+// PullRequestEvent
+
+var _ vcs.MergeRequestEvent = (*PullRequestEvent)(nil)
+
+type PullRequestEvent struct {
+	common.Object
+	Action      string       `json:"action"`
+	Number      int          `json:"number"`
+	PullRequest *PullRequest `json:"pull_request"`
+	Repository  *Repository  `json:"repository"`
+}
+
+type PullRequest struct {
+	Number  int     `json:"number"`
+	Title   string  `json:"title"`
+	State   string  `json:"state"`
+	Merged  bool    `json:"merged"`
+	HTMLURL string  `json:"html_url"`
+	Head    *Branch `json:"head"`
+	Base    *Branch `json:"base"`
+}
+
+type Branch struct {
+	Ref string `json:"ref"`
+	SHA string `json:"sha"`
+}
+
+func (e *PullRequestEvent) GetProject() vcs.Project { return e.Repository }
+
+func (e *PullRequestEvent) GetIID() int { return e.Number }
+
+func (e *PullRequestEvent) GetTitle() string { return e.pullRequest().Title }
+
+func (e *PullRequestEvent) GetAction() string { return e.Action }
+
+func (e *PullRequestEvent) GetState() string {
+	if pr := e.pullRequest(); pr.Merged {
+		return "merged"
+	}
+	return e.pullRequest().State
+}
+
+func (e *PullRequestEvent) GetSourceBranch() string { return e.pullRequest().Head.Ref }
+
+func (e *PullRequestEvent) GetTargetBranch() string { return e.pullRequest().Base.Ref }
+
+func (e *PullRequestEvent) GetLastCommit() string { return e.pullRequest().Head.SHA }
+
+func (e *PullRequestEvent) GetURL() string { return e.pullRequest().HTMLURL }
+
+func (e *PullRequestEvent) pullRequest() *PullRequest {
+	pr := PullRequest{Head: &Branch{}, Base: &Branch{}}
+	if e.PullRequest != nil {
+		pr = *e.PullRequest
+		if pr.Head == nil {
+			pr.Head = &Branch{}
+		}
+		if pr.Base == nil {
+			pr.Base = &Branch{}
+		}
+	}
+	return &pr
+}
+
+// PingEvent is sent when webhook is created
+
+type PingEvent struct {
+	common.Object
+	Zen        string      `json:"zen"`
+	HookID     int         `json:"hook_id"`
+	Repository *Repository `json:"repository"`
+}
+
+func (e *PingEvent) GetProject() vcs.Project { return e.Repository }
+
+// Repository
+
+var _ vcs.Project = (*Repository)(nil)
+
+type Repository struct {
+	common.Object
+	ID            int    `json:"id"`
+	Name          string `json:"name"`
+	FullName      string `json:"full_name"`
+	HTMLURL       string `json:"html_url"`
+	CloneURL      string `json:"clone_url"`
+	SSHURL        string `json:"ssh_url"`
+	DefaultBranch string `json:"default_branch"`
+}
+
+func (r *Repository) GetProvider() string { return ProviderName }
+
+func (r *Repository) GetName() string { return r.Name }
+
+// GetNamespace returns owner of the repository (user or organization)
+func (r *Repository) GetNamespace() string {
+	if i := strings.LastIndex(r.FullName, "/"); i >= 0 {
+		return r.FullName[:i]
+	}
+	return ""
+}
+
+func (r *Repository) GetHTTPURL() string { return r.CloneURL }
+
+// Commit
+
+var _ vcs.Commit = (*Commit)(nil)
+
+type Commit struct {
+	common.Object
+	Hash      string   `json:"id"`
+	Message   string   `json:"message"`
+	Timestamp string   `json:"timestamp"`
+	URL       string   `json:"url"`
+	Author    *Author  `json:"author"`
+	Added     []string `json:"added"`
+	Modified  []string `json:"modified"`
+	Removed   []string `json:"removed"`
+}
+
+func (c *Commit) GetHash() string { return c.Hash }
+
+func (c *Commit) GetMessage() string { return c.Message }
+
+// GetTimestamp returns commit time; payloads are validated
+// on decoding, so the timestamp is always well-formed
+func (c *Commit) GetTimestamp() time.Time {
+	t, _ := time.Parse(time.RFC3339, c.Timestamp)
+	return t
+}
+
+func (c *Commit) GetAuthor() vcs.Author { return c.Author }
+
+func (c *Commit) GetURL() string { return c.URL }
+
+func (c *Commit) GetAdded() []string { return c.Added }
+
+func (c *Commit) GetModified() []string { return c.Modified }
+
+func (c *Commit) GetRemoved() []string { return c.Removed }
+
+// Author
+
+var _ vcs.Author = (*Author)(nil)
+
+type Author struct {
+	common.Object
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Username string `json:"username"`
+}
+
+func (a *Author) GetName() string  { return a.Name }
+func (a *Author) GetEmail() string { return a.Email }
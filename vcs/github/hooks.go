@@ -0,0 +1,88 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/vitalyisaev2/buildgraph/config"
+	"github.com/vitalyisaev2/buildgraph/vcs"
+)
+
+const (
+	// EventHeader contains name of the hook
+	EventHeader = "X-GitHub-Event"
+	// SignatureHeader contains HMAC-SHA256 of the payload
+	SignatureHeader = "X-Hub-Signature-256"
+	// DeliveryHeader contains GUID of the delivery, which is kept on redelivery
+	DeliveryHeader = "X-GitHub-Delivery"
+)
+
+// Values of X-GitHub-Event header
+const (
+	PushHook        = "push"
+	PullRequestHook = "pull_request"
+	PingHook        = "ping"
+)
+
+const (
+	tagRefPrefix    = "refs/tags/"
+	signaturePrefix = "sha256="
+)
+
+// DecodeEvent decodes payload of the hook named in X-GitHub-Event header;
+// pushes of tags are turned into TagPushEvent
+func DecodeEvent(hook string, r io.Reader) (vcs.Event, error) {
+	switch hook {
+	case PushHook:
+		var push PushEvent
+		if err := json.NewDecoder(r).Decode(&push); err != nil {
+			return nil, err
+		}
+		if push.Repository == nil {
+			return nil, fmt.Errorf("%s payload has no repository", hook)
+		}
+		for _, c := range push.Commits {
+			if _, err := time.Parse(time.RFC3339, c.Timestamp); err != nil {
+				return nil, fmt.Errorf("commit %s: invalid timestamp: %v", c.Hash, err)
+			}
+		}
+		if strings.HasPrefix(push.Ref, tagRefPrefix) {
+			return &TagPushEvent{push: &push}, nil
+		}
+		return &push, nil
+	case PullRequestHook:
+		var event PullRequestEvent
+		if err := json.NewDecoder(r).Decode(&event); err != nil {
+			return nil, err
+		}
+		if event.Repository == nil || event.PullRequest == nil {
+			return nil, fmt.Errorf("%s payload has no repository or pull request", hook)
+		}
+		return &event, nil
+	case PingHook:
+		var event PingEvent
+		if err := json.NewDecoder(r).Decode(&event); err != nil {
+			return nil, err
+		}
+		return &event, nil
+	default:
+		return nil, fmt.Errorf("unsupported Github hook: '%s'", hook)
+	}
+}
+
+// VerifySignature checks X-Hub-Signature-256 header value against the payload;
+// if no secrets are configured, no payload is accepted
+func VerifySignature(secrets []config.Secret, payload []byte, header string) bool {
+	if len(secrets) == 0 || !strings.HasPrefix(header, signaturePrefix) {
+		return false
+	}
+
+	values := make([]string, 0, len(secrets))
+	for _, secret := range secrets {
+		values = append(values, secret.Value())
+	}
+	return vcs.VerifyHMAC(payload, strings.TrimPrefix(header, signaturePrefix), values)
+}
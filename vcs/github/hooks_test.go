@@ -0,0 +1,86 @@
+package github
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vitalyisaev2/buildgraph/config"
+	"github.com/vitalyisaev2/buildgraph/vcs"
+)
+
+func decodeFixture(t *testing.T, hook, path string) vcs.Event {
+	payload, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	event, err := DecodeEvent(hook, bytes.NewReader(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "namespace1", event.GetProject().GetNamespace())
+	assert.Equal(t, "project1", event.GetProject().GetName())
+	assert.Equal(t, "https://github.com/namespace1/project1.git", event.GetProject().GetHTTPURL())
+	assert.Equal(t, ProviderName, event.GetProject().GetProvider())
+	return event
+}
+
+func TestDecodeEvent(t *testing.T) {
+	push := decodeFixture(t, PushHook, "./test/push.json").(vcs.PushEvent)
+	assert.Equal(t, "refs/heads/main", push.GetRef())
+	assert.Len(t, push.GetCommits(), 1)
+	commit := push.GetCommits()[0]
+	assert.Equal(t, "0d1a26e67d8f5eaf1f6ba5c57fc3c7d91ac0fd1c", commit.GetHash())
+	assert.Equal(t, "john@example.com", commit.GetAuthor().GetEmail())
+	assert.Equal(t, []string{"README.md"}, commit.GetModified())
+	assert.False(t, commit.GetTimestamp().IsZero())
+
+	tag := decodeFixture(t, PushHook, "./test/push_tag.json").(vcs.TagPushEvent)
+	assert.Equal(t, "v1.0.0", tag.GetTag())
+	assert.Equal(t, "0d1a26e67d8f5eaf1f6ba5c57fc3c7d91ac0fd1c", tag.GetCommit())
+
+	pr := decodeFixture(t, PullRequestHook, "./test/pull_request.json").(vcs.MergeRequestEvent)
+	assert.Equal(t, 2, pr.GetIID())
+	assert.Equal(t, "merged", pr.GetState())
+	assert.Equal(t, "changes", pr.GetSourceBranch())
+	assert.Equal(t, "main", pr.GetTargetBranch())
+	assert.Equal(t, "34c5c7793cb3b279e22454cb6750c80560547b3a", pr.GetLastCommit())
+
+	_, ok := decodeFixture(t, PingHook, "./test/ping.json").(*PingEvent)
+	assert.True(t, ok)
+
+	_, err := DecodeEvent("issues", bytes.NewReader([]byte("{}")))
+	assert.Error(t, err)
+	_, err = DecodeEvent(PushHook, bytes.NewReader([]byte("{}")))
+	assert.Error(t, err)
+}
+
+func TestVerifySignature(t *testing.T) {
+	payload, err := ioutil.ReadFile("./test/push.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sign := func(secret string) string {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(payload)
+		return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+	}
+
+	// no secrets configured
+	assert.False(t, VerifySignature(nil, payload, ""))
+	assert.False(t, VerifySignature(nil, payload, sign("")))
+
+	cfg := &config.SignedWebhookConfig{Secret: "current", Secrets: []config.Secret{"previous"}}
+	assert.True(t, VerifySignature(cfg.All(), payload, sign("current")))
+	assert.True(t, VerifySignature(cfg.All(), payload, sign("previous")))
+	assert.False(t, VerifySignature(cfg.All(), payload, sign("other")))
+	assert.False(t, VerifySignature(cfg.All(), payload, ""))
+	assert.False(t, VerifySignature(cfg.All(), payload, "sha256=zz"))
+	assert.False(t, VerifySignature(cfg.All(), append(payload, ' '), sign("current")))
+}
@@ -0,0 +1,84 @@
+package github
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+
+	"github.com/vitalyisaev2/buildgraph/config"
+	"github.com/vitalyisaev2/buildgraph/vcs"
+)
+
+// ProviderName is the name Github provider is registered with
+const ProviderName = "github"
+
+var _ vcs.Provider = (*Provider)(nil)
+
+// Provider accepts Github webhook deliveries
+type Provider struct {
+	cfg func() *config.SignedVCSConfig
+}
+
+// NewProvider returns provider reading webhook secrets
+// from the section returned by cfg (which may be nil)
+func NewProvider(cfg func() *config.SignedVCSConfig) *Provider {
+	return &Provider{cfg: cfg}
+}
+
+func (p *Provider) Name() string { return ProviderName }
+
+// Detect checks presence of X-GitHub-Event header; Gitea sends it as well,
+// so Gitea provider has to be checked first
+func (p *Provider) Detect(header http.Header) bool { return header.Get(EventHeader) != "" }
+
+// DeliveryID returns value of X-GitHub-Delivery header
+func (p *Provider) DeliveryID(header http.Header) string { return header.Get(DeliveryHeader) }
+
+func (p *Provider) DeliveryHeaders() []string { return []string{DeliveryHeader} }
+
+// Secured returns false only if deliveries are accepted without authentication
+// explicitly; otherwise deliveries are rejected until secrets are configured
+func (p *Provider) Secured() bool {
+	return !p.section().Insecure()
+}
+
+func (p *Provider) Authenticate(d *vcs.Delivery) error {
+	secrets := p.section().Secrets()
+	if len(secrets) == 0 {
+		if p.section().Insecure() {
+			return nil
+		}
+		return vcs.ErrNoSecrets
+	}
+	if !VerifySignature(secrets, d.Payload, d.Header.Get(SignatureHeader)) {
+		return fmt.Errorf("wrong %s header value", SignatureHeader)
+	}
+	return nil
+}
+
+// SecretHeaders returns nothing: X-Hub-Signature-256 is HMAC of the payload,
+// which doesn't reveal the secret
+func (p *Provider) SecretHeaders() []string { return nil }
+
+// RestoreSecrets does nothing, since signature is never removed
+func (p *Provider) RestoreSecrets(*vcs.Delivery) {}
+
+// Decode turns delivery into events; ping is sent once
+// on webhook creation and contains nothing to save
+func (p *Provider) Decode(d *vcs.Delivery) ([]vcs.Event, error) {
+	event, err := DecodeEvent(d.Header.Get(EventHeader), bytes.NewReader(d.Payload))
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := event.(*PingEvent); ok {
+		return nil, nil
+	}
+	return []vcs.Event{event}, nil
+}
+
+func (p *Provider) section() *config.SignedVCSConfig {
+	if p.cfg == nil {
+		return nil
+	}
+	return p.cfg()
+}
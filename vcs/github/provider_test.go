@@ -0,0 +1,41 @@
+package github
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vitalyisaev2/buildgraph/config"
+	"github.com/vitalyisaev2/buildgraph/vcs"
+)
+
+func TestProviderAuthenticate(t *testing.T) {
+	payload, err := ioutil.ReadFile("./test/push.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := &vcs.Delivery{Header: http.Header{}, Payload: payload}
+	var cfg *config.SignedVCSConfig
+	p := NewProvider(func() *config.SignedVCSConfig { return cfg })
+
+	// no secrets configured: deliveries are rejected unless accepted explicitly
+	assert.True(t, p.Secured())
+	assert.Equal(t, vcs.ErrNoSecrets, p.Authenticate(d))
+	cfg = &config.SignedVCSConfig{Webhook: &config.SignedWebhookConfig{Insecure: true}}
+	assert.False(t, p.Secured())
+	assert.NoError(t, p.Authenticate(d))
+
+	cfg = &config.SignedVCSConfig{Webhook: &config.SignedWebhookConfig{Secret: "secret"}}
+	assert.True(t, p.Secured())
+	assert.Error(t, p.Authenticate(d))
+
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write(payload)
+	d.Header.Set(SignatureHeader, signaturePrefix+hex.EncodeToString(mac.Sum(nil)))
+	assert.NoError(t, p.Authenticate(d))
+}
@@ -0,0 +1,106 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"time"
+
+	gitlab "github.com/xanzy/go-gitlab"
+
+	"github.com/vitalyisaev2/buildgraph/common"
+	"github.com/vitalyisaev2/buildgraph/config"
+)
+
+const (
+	pathAPI     string = "/api/v3"
+	pathSession string = pathAPI + "/session"
+	pathUser    string = pathAPI + "/user"
+
+	// carries access token in API requests
+	privateTokenHeader = "PRIVATE-TOKEN"
+)
+
+// NewClient builds Gitlab API client authenticated with the configured access token;
+// no requests are made, so Gitlab doesn't have to be available at startup
+func NewClient(cfg *config.GitlabConfig) (*gitlab.Client, error) {
+	httpClient, err := newHTTPClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	client := gitlab.NewClient(httpClient, cfg.Token.Value())
+	if err := client.SetBaseURL(cfg.Endpoint + pathAPI); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// NewToken obtains new personal access token with user credentials, so that
+// it could be put into config; connection settings are taken from config
+func NewToken(cfg *config.GitlabConfig, login, password string, lifetime time.Duration) (string, error) {
+	httpClient, err := newHTTPClient(cfg)
+	if err != nil {
+		return "", err
+	}
+
+	// session is kept in cookies during authentication
+	if httpClient.Jar, err = cookiejar.New(nil); err != nil {
+		return "", err
+	}
+
+	name := "buildgraph" + time.Now().Format("2006-01-02_15.04.05")
+	return newPersonalAccessToken(httpClient, cfg.Endpoint, login, password, name, time.Now().Add(lifetime))
+}
+
+// newHTTPClient prepares HTTP client with respect to timeout and TLS settings
+func newHTTPClient(cfg *config.GitlabConfig) (*http.Client, error) {
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = gitlabAPITimeout
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if cfg.TLS != nil {
+		tlsConfig, err := cfg.TLS.Build()
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	client := &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+	}
+	return client, nil
+}
+
+// NewHealthChecker reports whether Gitlab API is reachable and accepts the configured token
+func NewHealthChecker(cfg *config.GitlabConfig) (common.HealthChecker, error) {
+	httpClient, err := newHTTPClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	check := func(ctx context.Context) error {
+		req, err := http.NewRequest("GET", cfg.Endpoint+pathUser, nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set(privateTokenHeader, cfg.Token.Value())
+
+		resp, err := httpClient.Do(req.WithContext(ctx))
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("Gitlab API replied with %s", resp.Status)
+		}
+		return nil
+	}
+	return common.HealthCheckerFunc(check), nil
+}
@@ -1,27 +1,18 @@
 package gitlab
 
 import (
+	"context"
 	"net/http"
-	"time"
+	"net/http/httptest"
+	"testing"
 
 	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
 	gitlab "github.com/xanzy/go-gitlab"
-)
 
-const (
-	pathAPI     string = "/api/v3"
-	pathSession string = pathAPI + "/session"
+	"github.com/vitalyisaev2/buildgraph/config"
 )
 
-// gitlabConfig provides parameters to access Gitlab API
-type gitlabConfig struct {
-	endpoint string
-	login    string
-	password string
-	timeout  time.Duration
-	projects map[string]string
-}
-
 // this interface contains a limited set of methods used only in integration tests
 type gitlabAPI interface {
 	CreateGroup(name string) error
@@ -31,9 +22,7 @@ type gitlabAPI interface {
 // gitlabAPIImpl implements methods required in tests
 type gitlabAPIImpl struct {
 	client *gitlab.Client
-	token  string
 	logger *logrus.Logger
-	cfg    *gitlabConfig
 }
 
 // Create
@@ -57,37 +46,48 @@ func (api *gitlabAPIImpl) GetGroup(name string) error {
 	return nil
 }
 
-func newGitlabAPI(cfg *gitlabConfig) (gitlabAPI, error) {
-
-	// prepare http client
-	httpClient := &http.Client{
-		Timeout: cfg.timeout,
-	}
-
-	// prepare logger
-	logger := logrus.New()
-
-	// obtain API token
-	tokenName := "test" + time.Now().Format("2006-01-02_15.04.02")
-	tokenExpirationDate := time.Now().Add(48 * time.Hour)
-	token, err := NewPersonalAccessToken(
-		cfg.endpoint,
-		cfg.login,
-		cfg.password,
-		tokenName,
-		tokenExpirationDate,
-	)
+func newGitlabAPI(cfg *config.GitlabConfig) (gitlabAPI, error) {
+	client, err := NewClient(cfg)
 	if err != nil {
 		return nil, err
 	}
-	// prepare Gitlab API client
-	gitlabClient := gitlab.NewClient(httpClient, token)
-	gitlabClient.SetBaseURL(cfg.endpoint + pathAPI)
 
 	api := &gitlabAPIImpl{
-		cfg:    cfg,
-		client: gitlabClient,
-		logger: logger,
+		client: client,
+		logger: logrus.New(),
 	}
 	return api, nil
 }
+
+func TestNewClient(t *testing.T) {
+	cfg := &config.GitlabConfig{Endpoint: "http://127.0.0.1:1", Token: "token"}
+
+	// Gitlab is not requested until client is used
+	_, err := NewClient(cfg)
+	assert.NoError(t, err)
+
+	cfg.TLS = &config.TLSClientConfig{CAFile: "./test/nonexistent.pem"}
+	_, err = NewClient(cfg)
+	assert.Error(t, err)
+}
+
+func TestNewHealthChecker(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != pathUser || r.Header.Get(privateTokenHeader) != "token" {
+			http.Error(w, "401 Unauthorized", 401)
+			return
+		}
+		w.Write([]byte(`{"id": 1}`))
+	}))
+	defer ts.Close()
+
+	checker, err := NewHealthChecker(&config.GitlabConfig{Endpoint: ts.URL, Token: "token"})
+	if assert.NoError(t, err) {
+		assert.NoError(t, checker.CheckHealth(context.Background()))
+	}
+
+	checker, err = NewHealthChecker(&config.GitlabConfig{Endpoint: ts.URL, Token: "revoked"})
+	if assert.NoError(t, err) {
+		assert.EqualError(t, checker.CheckHealth(context.Background()), "Gitlab API replied with 401 Unauthorized")
+	}
+}
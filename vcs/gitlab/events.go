@@ -28,6 +28,8 @@ type PushEvent struct {
 	TotalCommitsCount int         `json:"total_commits_count"`
 }
 
+func (p *PushEvent) GetRef() string { return p.Ref }
+
 func (p *PushEvent) GetProject() vcs.Project { return p.Project }
 
 func (p *PushEvent) GetCommits() []vcs.Commit {
@@ -61,11 +63,20 @@ type Project struct {
 	HTTPURL           string `json:"http_url"`
 }
 
+func (p *Project) GetProvider() string { return ProviderName }
+
 func (p *Project) GetName() string { return p.Name }
 
 func (p *Project) GetNamespace() string { return p.Namespace }
 
-func (p *Project) GetHTTPURL() string { return p.HTTPURL }
+// GetHTTPURL returns clone URL; deprecated http_url field
+// is missing in payloads of most hooks except push ones
+func (p *Project) GetHTTPURL() string {
+	if p.HTTPURL != "" {
+		return p.HTTPURL
+	}
+	return p.GitHTTPURL
+}
 
 // Repository
 
@@ -0,0 +1,240 @@
+package gitlab
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/vitalyisaev2/buildgraph/common"
+	"github.com/vitalyisaev2/buildgraph/vcs"
+)
+
+// Values of X-Gitlab-Event header
+const (
+	PushHook         = "Push Hook"
+	TagPushHook      = "Tag Push Hook"
+	MergeRequestHook = "Merge Request Hook"
+	PipelineHook     = "Pipeline Hook"
+	JobHook          = "Job Hook"
+)
+
+const (
+	tagRefPrefix = "refs/tags/"
+	// checkout_sha value of removed tag
+	zeroSHA = "0000000000000000000000000000000000000000"
+)
+
+// DecodeEvent decodes payload of the hook named in X-Gitlab-Event header
+func DecodeEvent(hook string, r io.Reader) (vcs.Event, error) {
+	var event vcs.Event
+	switch hook {
+	case PushHook:
+		event = &PushEvent{}
+	case TagPushHook:
+		event = &TagPushEvent{}
+	case MergeRequestHook:
+		event = &MergeRequestEvent{}
+	case PipelineHook:
+		event = &PipelineEvent{}
+	case JobHook:
+		event = &JobEvent{}
+	default:
+		return nil, fmt.Errorf("unsupported Gitlab hook: '%s'", hook)
+	}
+
+	if err := json.NewDecoder(r).Decode(event); err != nil {
+		return nil, err
+	}
+	if ProjectPath(event) == "" {
+		return nil, fmt.Errorf("%s payload has no project", hook)
+	}
+	return event, nil
+}
+
+// ProjectPath returns path with namespace of the project event belongs to
+func ProjectPath(e vcs.Event) string {
+	if p, ok := e.GetProject().(*Project); ok && p != nil {
+		return p.PathWithNamespace
+	}
+	return ""
+}
+
+// TagPushEvent
+
+var _ vcs.TagPushEvent = (*TagPushEvent)(nil)
+
+type TagPushEvent struct {
+	common.Object
+	ObjectKind   string      `json:"object_kind"`
+	Before       string      `json:"before"`
+	After        string      `json:"after"`
+	Ref          string      `json:"ref"`
+	CheckoutSHA  string      `json:"checkout_sha"`
+	UserName     string      `json:"user_name"`
+	UserUsername string      `json:"user_username"`
+	ProjectID    int         `json:"project_id"`
+	Project      *Project    `json:"project"`
+	Repository   *Repository `json:"repository"`
+}
+
+func (e *TagPushEvent) GetProject() vcs.Project { return e.Project }
+
+func (e *TagPushEvent) GetTag() string { return strings.TrimPrefix(e.Ref, tagRefPrefix) }
+
+func (e *TagPushEvent) GetCommit() string {
+	if e.After == zeroSHA {
+		return ""
+	}
+	return e.CheckoutSHA
+}
+
+// MergeRequestEvent
+
+var _ vcs.MergeRequestEvent = (*MergeRequestEvent)(nil)
+
+type MergeRequestEvent struct {
+	common.Object
+	ObjectKind       string                  `json:"object_kind"`
+	User             *User                   `json:"user"`
+	Project          *Project                `json:"project"`
+	Repository       *Repository             `json:"repository"`
+	ObjectAttributes *MergeRequestAttributes `json:"object_attributes"`
+}
+
+type MergeRequestAttributes struct {
+	ID              int     `json:"id"`
+	IID             int     `json:"iid"`
+	Title           string  `json:"title"`
+	State           string  `json:"state"`
+	MergeStatus     string  `json:"merge_status"`
+	Action          string  `json:"action"`
+	SourceBranch    string  `json:"source_branch"`
+	SourceProjectID int     `json:"source_project_id"`
+	TargetBranch    string  `json:"target_branch"`
+	TargetProjectID int     `json:"target_project_id"`
+	URL             string  `json:"url"`
+	LastCommit      *Commit `json:"last_commit"`
+}
+
+func (e *MergeRequestEvent) GetProject() vcs.Project { return e.Project }
+
+func (e *MergeRequestEvent) GetIID() int { return e.attributes().IID }
+
+func (e *MergeRequestEvent) GetTitle() string { return e.attributes().Title }
+
+func (e *MergeRequestEvent) GetAction() string { return e.attributes().Action }
+
+func (e *MergeRequestEvent) GetState() string { return e.attributes().State }
+
+func (e *MergeRequestEvent) GetSourceBranch() string { return e.attributes().SourceBranch }
+
+func (e *MergeRequestEvent) GetTargetBranch() string { return e.attributes().TargetBranch }
+
+func (e *MergeRequestEvent) GetLastCommit() string {
+	if c := e.attributes().LastCommit; c != nil {
+		return c.Hash
+	}
+	return ""
+}
+
+func (e *MergeRequestEvent) GetURL() string { return e.attributes().URL }
+
+func (e *MergeRequestEvent) attributes() *MergeRequestAttributes {
+	if e.ObjectAttributes == nil {
+		return &MergeRequestAttributes{}
+	}
+	return e.ObjectAttributes
+}
+
+// PipelineEvent
+
+var _ vcs.PipelineEvent = (*PipelineEvent)(nil)
+
+type PipelineEvent struct {
+	common.Object
+	ObjectKind       string              `json:"object_kind"`
+	User             *User               `json:"user"`
+	Project          *Project            `json:"project"`
+	Commit           *Commit             `json:"commit"`
+	ObjectAttributes *PipelineAttributes `json:"object_attributes"`
+}
+
+type PipelineAttributes struct {
+	ID         int      `json:"id"`
+	Ref        string   `json:"ref"`
+	Tag        bool     `json:"tag"`
+	SHA        string   `json:"sha"`
+	BeforeSHA  string   `json:"before_sha"`
+	Source     string   `json:"source"`
+	Status     string   `json:"status"`
+	Stages     []string `json:"stages"`
+	CreatedAt  string   `json:"created_at"`
+	FinishedAt string   `json:"finished_at"`
+	Duration   int      `json:"duration"`
+}
+
+func (e *PipelineEvent) GetProject() vcs.Project { return e.Project }
+
+func (e *PipelineEvent) GetPipelineID() int { return e.attributes().ID }
+
+func (e *PipelineEvent) GetRef() string { return e.attributes().Ref }
+
+func (e *PipelineEvent) GetCommit() string { return e.attributes().SHA }
+
+func (e *PipelineEvent) GetStatus() string { return e.attributes().Status }
+
+func (e *PipelineEvent) attributes() *PipelineAttributes {
+	if e.ObjectAttributes == nil {
+		return &PipelineAttributes{}
+	}
+	return e.ObjectAttributes
+}
+
+// JobEvent
+
+var _ vcs.JobEvent = (*JobEvent)(nil)
+
+type JobEvent struct {
+	common.Object
+	ObjectKind    string   `json:"object_kind"`
+	Ref           string   `json:"ref"`
+	Tag           bool     `json:"tag"`
+	BeforeSHA     string   `json:"before_sha"`
+	SHA           string   `json:"sha"`
+	BuildID       int      `json:"build_id"`
+	BuildName     string   `json:"build_name"`
+	BuildStage    string   `json:"build_stage"`
+	BuildStatus   string   `json:"build_status"`
+	BuildDuration float64  `json:"build_duration"`
+	PipelineID    int      `json:"pipeline_id"`
+	ProjectID     int      `json:"project_id"`
+	ProjectName   string   `json:"project_name"`
+	User          *User    `json:"user"`
+	Project       *Project `json:"project"`
+}
+
+func (e *JobEvent) GetProject() vcs.Project { return e.Project }
+
+func (e *JobEvent) GetJobID() int { return e.BuildID }
+
+func (e *JobEvent) GetPipelineID() int { return e.PipelineID }
+
+func (e *JobEvent) GetName() string { return e.BuildName }
+
+func (e *JobEvent) GetStage() string { return e.BuildStage }
+
+func (e *JobEvent) GetRef() string { return e.Ref }
+
+func (e *JobEvent) GetCommit() string { return e.SHA }
+
+func (e *JobEvent) GetStatus() string { return e.BuildStatus }
+
+// User triggered the event
+
+type User struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
@@ -0,0 +1,58 @@
+package gitlab
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vitalyisaev2/buildgraph/vcs"
+)
+
+func decodeFixture(t *testing.T, hook, path string) vcs.Event {
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	event, err := DecodeEvent(hook, f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "namespace1/project1", ProjectPath(event))
+	assert.Equal(t, "namespace1", event.GetProject().GetNamespace())
+	assert.Equal(t, "http://example.com/namespace1/project1.git", event.GetProject().GetHTTPURL())
+	assert.Equal(t, ProviderName, event.GetProject().GetProvider())
+	return event
+}
+
+func TestDecodeEvent(t *testing.T) {
+	push := decodeFixture(t, PushHook, "./test/push.json").(vcs.PushEvent)
+	assert.Equal(t, "refs/heads/master", push.GetRef())
+	assert.Len(t, push.GetCommits(), 1)
+
+	tag := decodeFixture(t, TagPushHook, "./test/tag_push.json").(vcs.TagPushEvent)
+	assert.Equal(t, "v1.0.0", tag.GetTag())
+	assert.Equal(t, "82b3d5ae55f7080f1e6022629cdb57bfae7cccc7", tag.GetCommit())
+
+	mr := decodeFixture(t, MergeRequestHook, "./test/merge_request.json").(vcs.MergeRequestEvent)
+	assert.Equal(t, 1, mr.GetIID())
+	assert.Equal(t, "open", mr.GetAction())
+	assert.Equal(t, "ms-viewport", mr.GetSourceBranch())
+	assert.Equal(t, "master", mr.GetTargetBranch())
+	assert.Equal(t, "da1560886d4f094c3e6c9ef40349f7d38b5d27d7", mr.GetLastCommit())
+
+	pipeline := decodeFixture(t, PipelineHook, "./test/pipeline.json").(vcs.PipelineEvent)
+	assert.Equal(t, 31, pipeline.GetPipelineID())
+	assert.Equal(t, "success", pipeline.GetStatus())
+	assert.Equal(t, "bcbb5ec396a2c0f828686f14fac9b80b780504f2", pipeline.GetCommit())
+
+	job := decodeFixture(t, JobHook, "./test/job.json").(vcs.JobEvent)
+	assert.Equal(t, 1977, job.GetJobID())
+	assert.Equal(t, 2366, job.GetPipelineID())
+	assert.Equal(t, "failed", job.GetStatus())
+
+	_, err := DecodeEvent("Wiki Page Hook", nil)
+	assert.Error(t, err)
+}
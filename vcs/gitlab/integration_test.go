@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/suite"
+
+	"github.com/vitalyisaev2/buildgraph/config"
 )
 
 const (
@@ -17,16 +19,18 @@ type gitlabSuite struct {
 }
 
 func (s *gitlabSuite) SetupSuite() {
-	config := &gitlabConfig{
-		login:    "root",
-		password: "password",
-		timeout:  10 * time.Second,
+	cfg := &config.GitlabConfig{
+		Endpoint: "http://localhost:10080",
+		Timeout:  10 * time.Second,
 	}
 
-	config.endpoint = "http://localhost:10080"
+	token, err := NewToken(cfg, "root", "password", 24*time.Hour)
+	if err != nil {
+		s.T().Fatal(err)
+	}
+	cfg.Token = config.Secret(token)
 
-	var err error
-	s.api, err = newGitlabAPI(config)
+	s.api, err = newGitlabAPI(cfg)
 	if err != nil {
 		s.T().Fatal(err)
 	}
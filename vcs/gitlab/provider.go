@@ -0,0 +1,114 @@
+package gitlab
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/vitalyisaev2/buildgraph/config"
+	"github.com/vitalyisaev2/buildgraph/vcs"
+)
+
+const (
+	// EventHeader contains name of the hook
+	EventHeader = "X-Gitlab-Event"
+	// TokenHeader contains secret token of the webhook
+	TokenHeader = "X-Gitlab-Token"
+	// EventUUIDHeader identifies event; all the webhooks triggered by the event share it
+	EventUUIDHeader = "X-Gitlab-Event-UUID"
+)
+
+// ProviderName is the name Gitlab provider is registered with
+const ProviderName = "gitlab"
+
+var _ vcs.Provider = (*Provider)(nil)
+
+// Provider accepts Gitlab webhook deliveries
+type Provider struct {
+	cfg func() *config.GitlabConfig
+}
+
+// NewProvider returns provider reading webhook settings
+// from the section returned by cfg (which may be nil)
+func NewProvider(cfg func() *config.GitlabConfig) *Provider {
+	return &Provider{cfg: cfg}
+}
+
+func (p *Provider) Name() string { return ProviderName }
+
+// Detect checks presence of X-Gitlab-Event header
+func (p *Provider) Detect(header http.Header) bool { return header.Get(EventHeader) != "" }
+
+// DeliveryID returns value of X-Gitlab-Event-UUID header
+func (p *Provider) DeliveryID(header http.Header) string { return header.Get(EventUUIDHeader) }
+
+func (p *Provider) DeliveryHeaders() []string { return []string{EventUUIDHeader} }
+
+func (p *Provider) Secured() bool {
+	return p.verifier().Enabled()
+}
+
+// Authenticate checks secret token; project is needed to choose the token,
+// so the payload is partially decoded first
+func (p *Provider) Authenticate(d *vcs.Delivery) error {
+	verifier := p.verifier()
+	if !verifier.Enabled() {
+		return nil
+	}
+
+	project, err := deliveryProject(d)
+	if err != nil {
+		return err
+	}
+	if !verifier.Verify(project, d.Header.Get(TokenHeader)) {
+		return fmt.Errorf("wrong %s header value (project '%s')", TokenHeader, project)
+	}
+	return nil
+}
+
+// SecretHeaders returns X-Gitlab-Token, since the token is sent as is
+func (p *Provider) SecretHeaders() []string { return []string{TokenHeader} }
+
+// RestoreSecrets sets X-Gitlab-Token to the token of delivery's project
+func (p *Provider) RestoreSecrets(d *vcs.Delivery) {
+	project, err := deliveryProject(d)
+	if err != nil {
+		return
+	}
+	if token := p.verifier().Token(project); token != "" {
+		d.Header.Set(TokenHeader, token)
+	}
+}
+
+func (p *Provider) Decode(d *vcs.Delivery) ([]vcs.Event, error) {
+	event, err := DecodeEvent(d.Header.Get(EventHeader), bytes.NewReader(d.Payload))
+	if err != nil {
+		return nil, err
+	}
+	return []vcs.Event{event}, nil
+}
+
+// deliveryProject returns path with namespace of the project delivery is related to
+func deliveryProject(d *vcs.Delivery) (string, error) {
+	var payload struct {
+		Project *Project `json:"project"`
+	}
+	if err := json.Unmarshal(d.Payload, &payload); err != nil {
+		return "", err
+	}
+	if payload.Project == nil {
+		return "", nil
+	}
+	return payload.Project.PathWithNamespace, nil
+}
+
+func (p *Provider) verifier() *WebhookVerifier {
+	if p.cfg == nil {
+		return NewWebhookVerifier(nil)
+	}
+	if cfg := p.cfg(); cfg != nil {
+		return NewWebhookVerifier(cfg.Webhook)
+	}
+	return NewWebhookVerifier(nil)
+}
@@ -42,6 +42,27 @@ func NewPersonalAccessToken(
 	tokenName string, tokenExpiresAt time.Time,
 ) (string, error) {
 
+	cookieJar, err := cookiejar.New(nil)
+	if err != nil {
+		return "", err
+	}
+
+	client := &http.Client{
+		Timeout: gitlabAPITimeout,
+		Jar:     cookieJar,
+	}
+
+	return newPersonalAccessToken(client, endpoint, login, password, tokenName, tokenExpiresAt)
+}
+
+// newPersonalAccessToken obtains personal access token with the provided HTTP client;
+// client must have cookie jar to keep session between requests
+func newPersonalAccessToken(
+	client *http.Client,
+	endpoint, login, password string,
+	tokenName string, tokenExpiresAt time.Time,
+) (string, error) {
+
 	endpointURL, err := url.Parse(endpoint)
 	if err != nil {
 		return "", err
@@ -55,16 +76,6 @@ func NewPersonalAccessToken(
 		expiresAt:   tokenExpiresAt,
 	}
 
-	cookieJar, err := cookiejar.New(nil)
-	if err != nil {
-		return "", nil
-	}
-
-	client := &http.Client{
-		Timeout: gitlabAPITimeout,
-		Jar:     cookieJar,
-	}
-
 	csrf1, err := obtainRootCSRFToken(client, p)
 	if err != nil {
 		return "", err
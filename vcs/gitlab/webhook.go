@@ -0,0 +1,70 @@
+package gitlab
+
+import (
+	"crypto/subtle"
+
+	"github.com/vitalyisaev2/buildgraph/config"
+)
+
+// WebhookVerifier checks secret tokens of incoming webhook deliveries
+type WebhookVerifier struct {
+	tokens   []config.Secret
+	projects map[string][]config.Secret
+}
+
+// NewWebhookVerifier builds verifier from webhook settings (which may be nil)
+func NewWebhookVerifier(cfg *config.GitlabWebhookConfig) *WebhookVerifier {
+	v := &WebhookVerifier{}
+	if cfg == nil {
+		return v
+	}
+	if cfg.Token != "" {
+		v.tokens = append(v.tokens, cfg.Token)
+	}
+	v.tokens = append(v.tokens, cfg.Tokens...)
+	v.projects = cfg.Projects
+	return v
+}
+
+// Enabled returns false if no tokens are configured,
+// so every delivery is accepted
+func (v *WebhookVerifier) Enabled() bool {
+	return len(v.tokens) != 0 || len(v.projects) != 0
+}
+
+// Verify checks the token of delivery related to the project (path with namespace);
+// project's own tokens are used instead of the common ones if configured
+func (v *WebhookVerifier) Verify(project, token string) bool {
+	if !v.Enabled() {
+		return true
+	}
+	if token == "" {
+		return false
+	}
+
+	candidates := v.candidates(project)
+
+	// compare with every candidate so that timing doesn't reveal which one matched
+	matched := 0
+	for _, candidate := range candidates {
+		matched |= subtle.ConstantTimeCompare([]byte(token), []byte(candidate.Value()))
+	}
+	return matched == 1
+}
+
+// Token returns the token Gitlab is expected to send for the project;
+// empty string if tokens are not configured
+func (v *WebhookVerifier) Token(project string) string {
+	if candidates := v.candidates(project); len(candidates) != 0 {
+		return candidates[0].Value()
+	}
+	return ""
+}
+
+// candidates returns tokens accepted for the project
+func (v *WebhookVerifier) candidates(project string) []config.Secret {
+	if tokens, exists := v.projects[project]; exists {
+		return tokens
+	}
+	return v.tokens
+}
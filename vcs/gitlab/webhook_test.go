@@ -0,0 +1,74 @@
+package gitlab
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vitalyisaev2/buildgraph/config"
+	"github.com/vitalyisaev2/buildgraph/vcs"
+)
+
+func TestWebhookVerifier(t *testing.T) {
+	v := NewWebhookVerifier(nil)
+	assert.False(t, v.Enabled())
+	assert.True(t, v.Verify("group/project", ""))
+
+	v = NewWebhookVerifier(&config.GitlabWebhookConfig{
+		Token:    "current",
+		Tokens:   []config.Secret{"previous"},
+		Projects: map[string][]config.Secret{"group/private": {"private"}},
+	})
+	assert.True(t, v.Enabled())
+
+	// common tokens are accepted during rotation
+	assert.True(t, v.Verify("group/project", "current"))
+	assert.True(t, v.Verify("group/project", "previous"))
+	assert.False(t, v.Verify("group/project", "private"))
+	assert.False(t, v.Verify("group/project", ""))
+
+	// project tokens replace the common ones
+	assert.True(t, v.Verify("group/private", "private"))
+	assert.False(t, v.Verify("group/private", "current"))
+}
+
+func TestProviderAuthenticate(t *testing.T) {
+	var cfg *config.GitlabConfig
+	p := NewProvider(func() *config.GitlabConfig { return cfg })
+	d := &vcs.Delivery{
+		Header:  http.Header{},
+		Payload: []byte(`{"project": {"path_with_namespace": "group/private"}}`),
+	}
+
+	assert.False(t, p.Secured())
+	assert.NoError(t, p.Authenticate(d))
+
+	// settings are read on every call
+	cfg = &config.GitlabConfig{
+		Webhook: &config.GitlabWebhookConfig{
+			Token:    "current",
+			Projects: map[string][]config.Secret{"group/private": {"private"}},
+		},
+	}
+	assert.True(t, p.Secured())
+
+	d.Header.Set(TokenHeader, "current")
+	assert.Error(t, p.Authenticate(d))
+	d.Header.Set(TokenHeader, "private")
+	assert.NoError(t, p.Authenticate(d))
+
+	// replayed deliveries get the token back from config
+	d.Header.Del(TokenHeader)
+	p.RestoreSecrets(d)
+	assert.Equal(t, "private", d.Header.Get(TokenHeader))
+	assert.NoError(t, p.Authenticate(d))
+}
+
+func TestProviderDeliveryID(t *testing.T) {
+	p := &Provider{}
+	header := http.Header{}
+	assert.Equal(t, "", p.DeliveryID(header))
+	header.Set(EventUUIDHeader, "9cbd6cc4-7e0c-4b4b-a3e5-cc8bcda5b0c8")
+	assert.Equal(t, "9cbd6cc4-7e0c-4b4b-a3e5-cc8bcda5b0c8", p.DeliveryID(header))
+}
@@ -8,12 +8,61 @@ import (
 
 type PushEvent interface {
 	common.Model
+	GetRef() string
 	GetProject() Project
 	GetCommits() []Commit
 }
 
+// TagPushEvent is sent when a tag is created or removed
+type TagPushEvent interface {
+	common.Model
+	GetProject() Project
+	GetTag() string    // tag name without refs/tags/ prefix
+	GetCommit() string // hash of the tagged commit, empty if tag was removed
+}
+
+// MergeRequestEvent is sent when a merge request is opened, updated, merged or closed
+type MergeRequestEvent interface {
+	common.Model
+	GetProject() Project
+	GetIID() int // merge request number within the project
+	GetTitle() string
+	GetAction() string
+	GetState() string
+	GetSourceBranch() string
+	GetTargetBranch() string
+	GetLastCommit() string
+	GetURL() string
+}
+
+// PipelineEvent is sent when CI pipeline status changes
+type PipelineEvent interface {
+	common.Model
+	GetProject() Project
+	GetPipelineID() int
+	GetRef() string
+	GetCommit() string
+	GetStatus() string
+}
+
+// JobEvent is sent when CI job status changes
+type JobEvent interface {
+	common.Model
+	GetProject() Project
+	GetJobID() int
+	GetPipelineID() int
+	GetName() string
+	GetStage() string
+	GetRef() string
+	GetCommit() string
+	GetStatus() string
+}
+
 type Project interface {
 	common.Model
+	// GetProvider returns name of VCS provider, since the same
+	// namespace and name may be found in several VCS (e.g. mirrors)
+	GetProvider() string
 	GetName() string
 	GetNamespace() string
 	GetHTTPURL() string
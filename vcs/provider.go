@@ -0,0 +1,48 @@
+package vcs
+
+import (
+	"net/http"
+
+	"github.com/vitalyisaev2/buildgraph/common"
+)
+
+// Event is implemented by all the events coming from VCS
+// (PushEvent, TagPushEvent, MergeRequestEvent, PipelineEvent, JobEvent)
+type Event interface {
+	common.Model
+	GetProject() Project
+}
+
+// Delivery is a webhook request received from VCS
+type Delivery struct {
+	Header  http.Header
+	Payload []byte
+}
+
+// Provider adapts webhook deliveries of a particular VCS; provider is given
+// its own section of VCS config on registration and reads the section
+// on every call, so that settings could be changed on config reload
+type Provider interface {
+	// Name is used in URL of the webhook endpoint
+	Name() string
+	// Detect returns true if request headers look like the ones sent by VCS
+	Detect(header http.Header) bool
+	// DeliveryID returns ID assigned to delivery by VCS, which is kept when
+	// delivery is resent; empty string if VCS doesn't identify deliveries
+	DeliveryID(header http.Header) string
+	// DeliveryHeaders returns names of the headers carrying delivery ID
+	DeliveryHeaders() []string
+	// Secured returns false if deliveries are accepted without authentication
+	Secured() bool
+	// Authenticate makes sure that delivery was sent by VCS
+	Authenticate(d *Delivery) error
+	// SecretHeaders returns names of the headers carrying secrets in plain text;
+	// they are removed from deliveries before saving
+	SecretHeaders() []string
+	// RestoreSecrets puts secrets from config into delivery which secret
+	// headers have been removed, so that it could be authenticated again
+	RestoreSecrets(d *Delivery)
+	// Decode turns delivery into events; deliveries
+	// like pings may contain no events at all
+	Decode(d *Delivery) ([]Event, error)
+}
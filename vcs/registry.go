@@ -0,0 +1,87 @@
+package vcs
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// credentialHeaders are removed from saved requests regardless of provider
+var credentialHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie"}
+
+// Registry keeps webhook providers
+type Registry interface {
+	// Get returns provider by name
+	Get(name string) (Provider, bool)
+	// Detect returns the first provider recognizing request headers
+	Detect(header http.Header) (Provider, bool)
+	// Providers returns all the providers in the order of registration
+	Providers() []Provider
+	// Redact returns copy of the headers without secrets of any provider
+	// and credentials, so that the headers could be saved
+	Redact(header http.Header) http.Header
+	// Anonymize returns copy of the headers without delivery IDs of any
+	// provider, so that the request is not taken for resent delivery
+	Anonymize(header http.Header) http.Header
+}
+
+var _ Registry = (*defaultRegistry)(nil)
+
+type defaultRegistry struct {
+	providers []Provider
+	byName    map[string]Provider
+}
+
+func (r *defaultRegistry) Get(name string) (Provider, bool) {
+	p, exists := r.byName[name]
+	return p, exists
+}
+
+func (r *defaultRegistry) Detect(header http.Header) (Provider, bool) {
+	for _, p := range r.providers {
+		if p.Detect(header) {
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+func (r *defaultRegistry) Providers() []Provider {
+	return append([]Provider{}, r.providers...)
+}
+
+func (r *defaultRegistry) Redact(header http.Header) http.Header {
+	result := header.Clone()
+	for _, name := range credentialHeaders {
+		result.Del(name)
+	}
+	for _, p := range r.providers {
+		for _, name := range p.SecretHeaders() {
+			result.Del(name)
+		}
+	}
+	return result
+}
+
+func (r *defaultRegistry) Anonymize(header http.Header) http.Header {
+	result := header.Clone()
+	for _, p := range r.providers {
+		for _, name := range p.DeliveryHeaders() {
+			result.Del(name)
+		}
+	}
+	return result
+}
+
+// NewRegistry builds registry of the given providers; since some VCS
+// imitate headers of the others, more specific providers should go first
+func NewRegistry(providers ...Provider) (Registry, error) {
+	r := &defaultRegistry{byName: make(map[string]Provider, len(providers))}
+	for _, p := range providers {
+		if _, exists := r.byName[p.Name()]; exists {
+			return nil, fmt.Errorf("duplicate VCS provider '%s'", p.Name())
+		}
+		r.byName[p.Name()] = p
+		r.providers = append(r.providers, p)
+	}
+	return r, nil
+}
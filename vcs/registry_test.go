@@ -0,0 +1,81 @@
+package vcs
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stubProvider struct {
+	name     string
+	header   string
+	secret   string
+	delivery string
+}
+
+func (p *stubProvider) Name() string { return p.name }
+
+func (p *stubProvider) Detect(header http.Header) bool { return header.Get(p.header) != "" }
+
+func (p *stubProvider) DeliveryID(header http.Header) string { return header.Get(p.delivery) }
+
+func (p *stubProvider) DeliveryHeaders() []string { return []string{p.delivery} }
+
+func (p *stubProvider) Secured() bool { return false }
+
+func (p *stubProvider) Authenticate(*Delivery) error { return nil }
+
+func (p *stubProvider) SecretHeaders() []string { return []string{p.secret} }
+
+func (p *stubProvider) RestoreSecrets(*Delivery) {}
+
+func (p *stubProvider) Decode(*Delivery) ([]Event, error) { return nil, nil }
+
+func TestRegistry(t *testing.T) {
+	// the second provider imitates headers of the first one
+	first := &stubProvider{name: "first", header: "X-First-Event", secret: "X-First-Token", delivery: "X-First-Delivery"}
+	second := &stubProvider{name: "second", header: "X-Second-Event", secret: "X-Second-Token", delivery: "X-Second-Delivery"}
+
+	r, err := NewRegistry(second, first)
+	assert.NoError(t, err)
+	assert.Len(t, r.Providers(), 2)
+
+	p, ok := r.Get("first")
+	assert.True(t, ok)
+	assert.Equal(t, first, p)
+	_, ok = r.Get("third")
+	assert.False(t, ok)
+
+	p, ok = r.Detect(http.Header{"X-First-Event": {"push"}})
+	assert.True(t, ok)
+	assert.Equal(t, first, p)
+	p, ok = r.Detect(http.Header{"X-First-Event": {"push"}, "X-Second-Event": {"push"}})
+	assert.True(t, ok)
+	assert.Equal(t, second, p)
+	_, ok = r.Detect(http.Header{})
+	assert.False(t, ok)
+
+	// secrets of every provider are redacted
+	header := http.Header{}
+	header.Set("X-First-Event", "push")
+	header.Set("X-First-Token", "secret")
+	header.Set("X-Second-Token", "secret")
+	header.Set("Authorization", "Basic secret")
+	redacted := r.Redact(header)
+	assert.Equal(t, http.Header{"X-First-Event": {"push"}}, redacted)
+	assert.Len(t, header, 4)
+
+	// delivery IDs of every provider are removed
+	header = http.Header{}
+	header.Set("X-First-Event", "push")
+	header.Set("X-First-Delivery", "1")
+	header.Set("X-Second-Delivery", "2")
+	anonymized := r.Anonymize(header)
+	assert.Equal(t, http.Header{"X-First-Event": {"push"}}, anonymized)
+	assert.Empty(t, first.DeliveryID(anonymized))
+	assert.Len(t, header, 3)
+
+	_, err = NewRegistry(first, first)
+	assert.Error(t, err)
+}
@@ -0,0 +1,32 @@
+package vcs
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+)
+
+// ErrNoSecrets is returned by providers of signed deliveries
+// when they are neither given secrets nor allowed to skip authentication
+var ErrNoSecrets = errors.New("webhook secrets are not configured ('insecure: true' accepts unsigned deliveries)")
+
+// VerifyHMAC checks that hex-encoded signature matches HMAC-SHA256
+// of the payload computed with any of the secrets
+func VerifyHMAC(payload []byte, signature string, secrets []string) bool {
+	expected, err := hex.DecodeString(signature)
+	if err != nil || len(expected) != sha256.Size {
+		return false
+	}
+
+	// check every secret so that timing doesn't reveal which one matched
+	matched := false
+	for _, secret := range secrets {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(payload)
+		if hmac.Equal(mac.Sum(nil), expected) {
+			matched = true
+		}
+	}
+	return matched
+}
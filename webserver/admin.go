@@ -0,0 +1,18 @@
+package webserver
+
+import (
+	"net/http"
+)
+
+// ReloadConfig re-reads configuration file and replies with the list
+// of changes applied to project graph and the ones ignored in 'seed' import mode
+func (s *server) ReloadConfig(w http.ResponseWriter, r *http.Request) {
+	report, err := s.services.ReloadConfig()
+	if err != nil {
+		s.services.Logger.WithError(err).Error("failed to reload config")
+		http.Error(w, err.Error(), 422)
+		return
+	}
+
+	s.writeJSON(w, report)
+}
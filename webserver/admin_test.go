@@ -0,0 +1,38 @@
+package webserver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vitalyisaev2/buildgraph/config"
+	"github.com/vitalyisaev2/buildgraph/service"
+)
+
+func TestReloadConfig(t *testing.T) {
+	ts := newTestServer(t, &memStorage{})
+
+	var report service.ReloadReport
+	decode(t, call(t, ts, "POST", "/admin/config/reload", "admin-key", nil), &report)
+	assert.Equal(t, config.ImportSeed, report.Import)
+	assert.True(t, report.Applied.Empty())
+	assert.Nil(t, report.Ignored)
+	assert.Empty(t, report.Warning)
+
+	// once projects are changed through API, config no longer matches the storage,
+	// but in seed mode the stored projects are kept
+	project := map[string]interface{}{"namespace": "namespace1", "name": "project3"}
+	assert.Equal(t, 200, call(t, ts, "PUT", "/projects/n1_p3", "operator-key", project).StatusCode)
+
+	report = service.ReloadReport{}
+	decode(t, call(t, ts, "POST", "/admin/config/reload", "admin-key", nil), &report)
+	assert.True(t, report.Applied.Empty())
+	if assert.NotNil(t, report.Ignored) {
+		assert.Equal(t, []string{"n1_p3"}, report.Ignored.Removed)
+	}
+	assert.Contains(t, report.Warning, "import: overwrite")
+
+	var d config.Description
+	decode(t, call(t, ts, "GET", "/projects/n1_p3", "viewer-key", nil), &d)
+	assert.Equal(t, "project3", d.Name)
+}
@@ -0,0 +1,85 @@
+package webserver
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/negroni"
+
+	"github.com/vitalyisaev2/buildgraph/auth"
+	"github.com/vitalyisaev2/buildgraph/config"
+)
+
+// authenticate makes negroni middleware identifying API clients;
+// requests to public routes are passed through as is
+func (s *server) authenticate(router *mux.Router) negroni.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+		if publicRoutes[routeName(router, r)] {
+			next(w, r)
+			return
+		}
+
+		p, err := s.services.Authenticator().Authenticate(r)
+		if err != nil {
+			s.services.Logger.WithError(err).WithFields(logrus.Fields{
+				"remote_addr": r.RemoteAddr,
+				"path":        r.URL.Path,
+			}).Warn("API request rejected")
+			w.Header().Set("WWW-Authenticate", `Bearer realm="buildgraph"`)
+			http.Error(w, err.Error(), 401)
+			return
+		}
+		next(w, r.WithContext(auth.NewContext(r.Context(), p)))
+	}
+}
+
+// require allows handler for clients having role within any namespace
+func require(role string, h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !principal(r).CanAny(role) {
+			http.Error(w, fmt.Sprintf("role '%s' is required", role), 403)
+			return
+		}
+		h(w, r)
+	}
+}
+
+// requireGlobal allows handler for clients having role within every namespace
+func requireGlobal(role string, h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !principal(r).Can(role, config.AllNamespaces) {
+			http.Error(w, fmt.Sprintf("role '%s' is required for all namespaces", role), 403)
+			return
+		}
+		h(w, r)
+	}
+}
+
+// authorize checks that client has role within namespace; replies with error otherwise
+func authorize(w http.ResponseWriter, r *http.Request, role, namespace string) bool {
+	if principal(r).Can(role, namespace) {
+		return true
+	}
+	http.Error(w, fmt.Sprintf("role '%s' is required for namespace '%s'", role, namespace), 403)
+	return false
+}
+
+// principal returns client who sent the request
+func principal(r *http.Request) *auth.Principal {
+	if p := auth.FromContext(r.Context()); p != nil {
+		return p
+	}
+	// no permissions at all
+	return &auth.Principal{}
+}
+
+// projectNamespace returns namespace of the described project; undescribed
+// projects are treated as belonging to every namespace
+func (s *server) projectNamespace(id string) string {
+	if d, exists := s.services.Projects.Description(id); exists {
+		return d.Namespace
+	}
+	return config.AllNamespaces
+}
@@ -0,0 +1,100 @@
+package webserver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vitalyisaev2/buildgraph/config"
+)
+
+func TestAuthenticate(t *testing.T) {
+	ts := newTestServer(t, &memStorage{})
+
+	resp := call(t, ts, "GET", "/projects", "", nil)
+	assert.Equal(t, 401, resp.StatusCode)
+	assert.Contains(t, resp.Header.Get("WWW-Authenticate"), "Bearer")
+
+	resp = call(t, ts, "GET", "/projects", "unknown-key", nil)
+	assert.Equal(t, 401, resp.StatusCode)
+
+	resp = call(t, ts, "GET", "/projects", "viewer-key", nil)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	// public routes don't require credentials
+	resp = call(t, ts, "GET", "/healthz", "", nil)
+	assert.Equal(t, 200, resp.StatusCode)
+	resp = call(t, ts, "POST", "/hooks", "", map[string]string{})
+	assert.Equal(t, 400, resp.StatusCode)
+}
+
+func TestAuthorize(t *testing.T) {
+	ts := newTestServer(t, &memStorage{})
+
+	for _, tc := range []struct {
+		method, path, key string
+		status            int
+	}{
+		// roles are checked by routes
+		{"GET", "/audit", "operator-key", 403},
+		{"POST", "/admin/config/reload", "viewer-key", 403},
+		{"GET", "/inbox", "operator-key", 403},
+		// and by handlers within namespace of the project
+		{"GET", "/projects/n1_p1", "viewer-key", 200},
+		{"GET", "/projects/n2_p1", "viewer-key", 403},
+		{"GET", "/projects/n2_p1", "admin-key", 200},
+		{"DELETE", "/projects/n1_p1", "viewer-key", 403},
+		{"DELETE", "/projects/n2_p1", "operator-key", 403},
+		{"GET", "/projects/n2_p1/downstream", "viewer-key", 403},
+		{"PUT", "/relations/n1_p1/n2_p1", "operator-key", 403},
+	} {
+		resp := call(t, ts, tc.method, tc.path, tc.key, nil)
+		assert.Equal(t, tc.status, resp.StatusCode, "%s %s as %s", tc.method, tc.path, tc.key)
+	}
+}
+
+func TestNamespaceFiltering(t *testing.T) {
+	ts := newTestServer(t, &memStorage{})
+
+	var projects []*config.Description
+	decode(t, call(t, ts, "GET", "/projects", "viewer-key", nil), &projects)
+	assert.Len(t, projects, 2)
+	for _, d := range projects {
+		assert.Equal(t, "namespace1", d.Namespace)
+	}
+
+	var relations []relation
+	decode(t, call(t, ts, "GET", "/relations", "viewer-key", nil), &relations)
+	assert.Equal(t, []relation{{Dependency: "n1_p1", Dependent: "n1_p2"}}, relations)
+	decode(t, call(t, ts, "GET", "/relations", "admin-key", nil), &relations)
+	assert.Len(t, relations, 3)
+
+	var g graphView
+	decode(t, call(t, ts, "GET", "/graph", "viewer-key", nil), &g)
+	if assert.Len(t, g.Nodes, 2) {
+		assert.Equal(t, "n1_p1", g.Nodes[0].ID)
+		assert.Equal(t, "n1_p2", g.Nodes[1].ID)
+	}
+	assert.Equal(t, []*relation{{Dependency: "n1_p1", Dependent: "n1_p2"}}, g.Edges)
+
+	resp := call(t, ts, "GET", "/graph?format=dot", "viewer-key", nil)
+	assert.Equal(t, 200, resp.StatusCode)
+	dot := readBody(t, resp)
+	assert.Contains(t, dot, "n1_p2")
+	assert.NotContains(t, dot, "n2_p1")
+
+	decode(t, call(t, ts, "GET", "/graph", "admin-key", nil), &g)
+	assert.Len(t, g.Nodes, 3)
+
+	// projects of other namespaces are omitted from plans
+	var phases phasesView
+	decode(t, call(t, ts, "POST", "/plan", "viewer-key", &planRequest{Projects: []string{"n1_p1"}}), &phases)
+	assert.Equal(t, [][]string{{"n1_p1"}, {"n1_p2"}}, phases.Phases)
+	decode(t, call(t, ts, "GET", "/projects/n1_p1/downstream", "viewer-key", nil), &phases)
+	assert.Equal(t, [][]string{{"n1_p1"}, {"n1_p2"}}, phases.Phases)
+	decode(t, call(t, ts, "POST", "/plan", "admin-key", &planRequest{Projects: []string{"n1_p1"}}), &phases)
+	assert.Equal(t, [][]string{{"n1_p1"}, {"n2_p1"}, {"n1_p2"}}, phases.Phases)
+
+	resp = call(t, ts, "POST", "/plan", "viewer-key", &planRequest{Projects: []string{"n1_p1", "n2_p1"}})
+	assert.Equal(t, 403, resp.StatusCode)
+}
@@ -0,0 +1,141 @@
+package webserver
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+
+	"github.com/vitalyisaev2/buildgraph/metrics"
+	"github.com/vitalyisaev2/buildgraph/storage"
+	"github.com/vitalyisaev2/buildgraph/vcs"
+)
+
+// VCSEvent accepts webhook deliveries of the provider named in URL
+func (s *server) VCSEvent(w http.ResponseWriter, r *http.Request) {
+	logged, ok := s.readWebhook(w, r)
+	if !ok {
+		return
+	}
+
+	name := mux.Vars(r)["provider"]
+	p, exists := s.services.VCS.Get(name)
+	if !exists {
+		err := fmt.Errorf("unknown VCS provider '%s'", name)
+		s.replyWebhook(w, r, logged, 404, storage.RequestRejected, err)
+		return
+	}
+	s.acceptDelivery(w, r, logged, p)
+}
+
+// Hook accepts webhook deliveries of any provider recognized by request headers
+func (s *server) Hook(w http.ResponseWriter, r *http.Request) {
+	logged, ok := s.readWebhook(w, r)
+	if !ok {
+		return
+	}
+
+	p, detected := s.services.VCS.Detect(r.Header)
+	if !detected {
+		err := errors.New("unable to detect VCS provider by request headers")
+		s.replyWebhook(w, r, logged, 400, storage.RequestRejected, err)
+		return
+	}
+	s.acceptDelivery(w, r, logged, p)
+}
+
+// readWebhook reads raw webhook request for the request log; secrets are
+// removed from the logged headers, and requests which body could not be
+// read are rejected at once
+func (s *server) readWebhook(w http.ResponseWriter, r *http.Request) (*storage.WebhookRequest, bool) {
+	logged := &storage.WebhookRequest{
+		Received:   time.Now(),
+		RemoteAddr: r.RemoteAddr,
+		URL:        r.URL.RequestURI(),
+		Header:     s.services.VCS.Redact(r.Header),
+	}
+	if r.Body == nil {
+		s.replyWebhook(w, r, logged, 400, storage.RequestRejected, errors.New("please send request body"))
+		return nil, false
+	}
+
+	// signatures are computed over raw payload, so it is read as is
+	payload, err := ioutil.ReadAll(r.Body)
+	logged.Payload = payload
+	if err != nil {
+		s.replyWebhook(w, r, logged, bodyErrorStatus(routeWebhook, err), storage.RequestRejected, err)
+		return nil, false
+	}
+	return logged, true
+}
+
+// acceptDelivery authenticates delivery and puts it into the inbox without secrets;
+// events are decoded and saved later by the inbox workers
+func (s *server) acceptDelivery(w http.ResponseWriter, r *http.Request, logged *storage.WebhookRequest, p vcs.Provider) {
+	logged.Provider = p.Name()
+
+	if err := p.Authenticate(&vcs.Delivery{Header: r.Header, Payload: logged.Payload}); err != nil {
+		metrics.WebhookDeliveries.WithLabelValues(p.Name(), metrics.EventUnknown, metrics.OutcomeRejected).Inc()
+		s.services.Logger.WithError(err).WithFields(logrus.Fields{
+			"remote_addr": logged.RemoteAddr,
+			"provider":    p.Name(),
+		}).Warn("webhook delivery rejected")
+		s.replyWebhook(w, r, logged, 401, storage.RequestRejected, err)
+		return
+	}
+	if delay := s.limitProvider(p); delay > 0 {
+		retryAfter(w, routeWebhook, delay)
+		s.replyWebhook(w, r, logged, 429, storage.RequestRejected, errRateLimited)
+		return
+	}
+
+	// VCS is expected to resend delivery that hasn't been acknowledged
+	d := &vcs.Delivery{Header: logged.Header, Payload: logged.Payload}
+	stored, saved, err := s.services.Inbox.Enqueue(r.Context(), p, d)
+	if err != nil {
+		metrics.WebhookDeliveries.WithLabelValues(p.Name(), metrics.EventUnknown, metrics.OutcomeFailed).Inc()
+		s.services.Logger.WithError(err).Error("failed to put delivery into the inbox")
+		s.replyWebhook(w, r, logged, 500, storage.RequestFailed, err)
+		return
+	}
+	logged.DeliveryID = &stored.ID
+	if !saved {
+		metrics.WebhookDeliveries.WithLabelValues(p.Name(), metrics.EventUnknown, metrics.OutcomeDuplicate).Inc()
+		s.services.Logger.WithFields(logrus.Fields{
+			"delivery": stored.ID,
+			"uuid":     stored.UUID,
+			"provider": p.Name(),
+		}).Debug("webhook delivery has been received before, skipping")
+		s.replyWebhook(w, r, logged, 200, storage.RequestDuplicate, nil)
+		return
+	}
+
+	s.replyWebhook(w, r, logged, 202, storage.RequestAccepted, nil)
+}
+
+// replyWebhook replies to webhook request and saves it into the request log;
+// failure to save the log doesn't affect processing of delivery
+func (s *server) replyWebhook(
+	w http.ResponseWriter,
+	r *http.Request,
+	logged *storage.WebhookRequest,
+	status int,
+	outcome string,
+	err error,
+) {
+	logged.Status, logged.Outcome = status, outcome
+	if err != nil {
+		logged.Error = err.Error()
+		http.Error(w, err.Error(), status)
+	} else {
+		w.WriteHeader(status)
+	}
+
+	if err := s.services.Storage.SaveWebhookRequest(r.Context(), logged); err != nil {
+		s.services.Logger.WithError(err).Error("failed to save webhook request into the log")
+	}
+}
@@ -0,0 +1,212 @@
+package webserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/vitalyisaev2/buildgraph/config"
+	"github.com/vitalyisaev2/buildgraph/graph"
+	"github.com/vitalyisaev2/buildgraph/metrics"
+)
+
+const (
+	// name of the graph in DOT output
+	dotGraphName = "buildgraph"
+	// maximal time of SVG rendering
+	svgRenderingTimeout = 10 * time.Second
+)
+
+type graphView struct {
+	Nodes []*graphNodeView `json:"nodes"`
+	Edges []*relation      `json:"edges"`
+}
+
+type graphNodeView struct {
+	ID          string              `json:"id"`
+	Description *config.Description `json:"description,omitempty"` // missing if project is not described
+}
+
+// phasesView contains projects grouped by phases; projects of
+// the same phase don't depend on each other
+type phasesView struct {
+	Phases [][]string `json:"phases"`
+}
+
+type planRequest struct {
+	Projects []string `json:"projects"` // IDs of changed projects
+}
+
+// Graph replies with dependency graph of the projects visible to client in the format
+// requested with 'format' parameter: json (default), dot or svg
+func (s *server) Graph(w http.ResponseWriter, r *http.Request) {
+	g := s.visibleGraph(r, s.services.Projects.Graph())
+
+	switch format := r.URL.Query().Get("format"); format {
+	case "", "json":
+		s.writeJSON(w, newGraphView(g))
+	case "dot":
+		w.Header().Set("Content-Type", "text/vnd.graphviz")
+		if err := graph.WriteDOT(w, g, dotGraphName); err != nil {
+			s.services.Logger.WithError(err).Error("failed to render graph")
+		}
+	case "svg":
+		var dot bytes.Buffer
+		if err := graph.WriteDOT(&dot, g, dotGraphName); err != nil {
+			http.Error(w, err.Error(), 500)
+			return
+		}
+		svg, err := renderSVG(r.Context(), dot.Bytes())
+		if err != nil {
+			s.services.Logger.WithError(err).Error("failed to render graph")
+			http.Error(w, err.Error(), 501)
+			return
+		}
+		w.Header().Set("Content-Type", "image/svg+xml")
+		w.Write(svg)
+	default:
+		http.Error(w, fmt.Sprintf("unsupported format '%s' (json, dot or svg expected)", format), 400)
+	}
+}
+
+// Downstream replies with projects depending on the given one (including itself),
+// grouped by phases of rebuilding
+func (s *server) Downstream(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	if !authorize(w, r, config.RoleViewer, s.projectNamespace(id)) {
+		return
+	}
+	s.replyPhases(w, r, s.services.Projects.Graph(), id)
+}
+
+// Upstream replies with projects the given one depends on (including itself),
+// grouped by phases starting from the project itself
+func (s *server) Upstream(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	if !authorize(w, r, config.RoleViewer, s.projectNamespace(id)) {
+		return
+	}
+	s.replyPhases(w, r, s.services.Projects.Graph().Reverse(), id)
+}
+
+// Plan replies with merged rebuild plan for the list of changed projects;
+// every changed project has to be visible to client
+func (s *server) Plan(w http.ResponseWriter, r *http.Request) {
+	if r.Body == nil {
+		http.Error(w, "please send request body", 400)
+		return
+	}
+
+	var req planRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		replyBodyError(w, routeAPI, err)
+		return
+	}
+	if len(req.Projects) == 0 {
+		http.Error(w, "list of changed projects is empty", 400)
+		return
+	}
+	for _, id := range req.Projects {
+		if !authorize(w, r, config.RoleViewer, s.projectNamespace(id)) {
+			return
+		}
+	}
+
+	s.replyPhases(w, r, s.services.Projects.Graph(), req.Projects...)
+}
+
+// replyPhases sorts subgraph built from the given roots; projects
+// that are not visible to client are omitted from the phases
+func (s *server) replyPhases(w http.ResponseWriter, r *http.Request, g graph.Graph, roots ...string) {
+	for _, root := range roots {
+		if _, err := g.GetNode(root); err != nil {
+			http.Error(w, err.Error(), 404)
+			return
+		}
+	}
+
+	timer := prometheus.NewTimer(metrics.PlanDuration)
+	pts, err := g.PhasicTopologicalSortFromNodes(roots...)
+	timer.ObserveDuration()
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+	s.writeJSON(w, newPhasesView(pts, s.visible(r)))
+}
+
+// visible returns predicate telling whether client may view project
+func (s *server) visible(r *http.Request) func(id string) bool {
+	p := principal(r)
+	return func(id string) bool { return p.Can(config.RoleViewer, s.projectNamespace(id)) }
+}
+
+// visibleGraph returns subgraph of the projects visible to client;
+// relations with projects of other namespaces are omitted as well
+func (s *server) visibleGraph(r *http.Request, g graph.Graph) graph.Graph {
+	if principal(r).Can(config.RoleViewer, config.AllNamespaces) {
+		return g
+	}
+
+	visible := s.visible(r)
+	result := graph.NewGraph()
+	for _, name := range g.SortedKeys() {
+		if n, _ := g.GetNode(name); visible(name) {
+			result.CreateNode(name, n.Value())
+		}
+	}
+	for _, name := range result.SortedKeys() {
+		n, _ := g.GetNode(name)
+		for _, successor := range n.Successors() {
+			if visible(successor.Name()) {
+				result.Link(name, successor.Name())
+			}
+		}
+	}
+	return result
+}
+
+func newGraphView(g graph.Graph) *graphView {
+	v := &graphView{Nodes: []*graphNodeView{}, Edges: []*relation{}}
+	for _, name := range g.SortedKeys() {
+		n, _ := g.GetNode(name)
+		d, _ := n.Value().(*config.Description)
+		v.Nodes = append(v.Nodes, &graphNodeView{ID: name, Description: d})
+		for _, successor := range n.Successors() {
+			v.Edges = append(v.Edges, &relation{Dependency: name, Dependent: successor.Name()})
+		}
+	}
+	return v
+}
+
+// newPhasesView keeps only the projects matching the filter; phases left empty are dropped
+func newPhasesView(pts graph.PhasicTopologicalSort, filter func(id string) bool) *phasesView {
+	v := &phasesView{Phases: graph.PhaseNames(pts, filter)}
+	if v.Phases == nil {
+		v.Phases = [][]string{}
+	}
+	return v
+}
+
+// renderSVG converts DOT into SVG with Graphviz
+func renderSVG(ctx context.Context, dot []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, svgRenderingTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "dot", "-Tsvg")
+	cmd.Stdin = bytes.NewReader(dot)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("failed to run Graphviz: %v %s", err, stderr.String())
+	}
+	return stdout.Bytes(), nil
+}
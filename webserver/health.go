@@ -0,0 +1,27 @@
+package webserver
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/vitalyisaev2/buildgraph/service"
+)
+
+// Healthz replies as long as the process is able to serve HTTP requests
+func (s *server) Healthz(w http.ResponseWriter, r *http.Request) {
+	s.writeJSON(w, map[string]string{"status": service.HealthOK})
+}
+
+// Readyz checks the services the process depends on and replies
+// with 503 if any critical one is failing
+func (s *server) Readyz(w http.ResponseWriter, r *http.Request) {
+	report := s.services.CheckReadiness(r.Context())
+
+	w.Header().Set("Content-Type", "application/json")
+	if !report.Ready {
+		w.WriteHeader(503)
+	}
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		s.services.Logger.WithError(err).Error("failed to encode readiness report")
+	}
+}
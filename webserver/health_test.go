@@ -0,0 +1,50 @@
+package webserver
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vitalyisaev2/buildgraph/service"
+)
+
+// unreachableStorage fails health checks
+type unreachableStorage struct {
+	memStorage
+}
+
+func (s *unreachableStorage) CheckHealth(ctx context.Context) error {
+	return errors.New("database is unreachable")
+}
+
+func TestReadyz(t *testing.T) {
+	// probes don't require authentication
+	var report service.HealthReport
+	resp := call(t, newTestServer(t, &memStorage{}), "GET", "/readyz", "", nil)
+	assert.Equal(t, 200, resp.StatusCode)
+	decode(t, resp, &report)
+	assert.True(t, report.Ready)
+	for _, name := range []string{"storage", "hub", "config", "inbox"} {
+		if assert.Contains(t, report.Components, name) {
+			assert.Equal(t, service.HealthOK, report.Components[name].Status, name)
+		}
+	}
+
+	// failure of a critical component makes the whole service unready
+	report = service.HealthReport{}
+	resp = call(t, newTestServer(t, &unreachableStorage{}), "GET", "/readyz", "", nil)
+	assert.Equal(t, 503, resp.StatusCode)
+	decode(t, resp, &report)
+	assert.False(t, report.Ready)
+	if assert.Contains(t, report.Components, "storage") {
+		assert.Equal(t, service.HealthFailing, report.Components["storage"].Status)
+		assert.Equal(t, "database is unreachable", report.Components["storage"].Error)
+		assert.True(t, report.Components["storage"].Critical)
+	}
+	if assert.Contains(t, report.Components, "inbox") {
+		assert.Equal(t, service.HealthOK, report.Components["inbox"].Status)
+		assert.False(t, report.Components["inbox"].Critical)
+	}
+}
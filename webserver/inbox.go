@@ -0,0 +1,270 @@
+package webserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+
+	"github.com/vitalyisaev2/buildgraph/common"
+	"github.com/vitalyisaev2/buildgraph/storage"
+	"github.com/vitalyisaev2/buildgraph/vcs"
+)
+
+// deliveryView shows payload as is when it is JSON (and it almost always is)
+type deliveryView struct {
+	*storage.Delivery
+	Payload interface{} `json:"payload,omitempty"` // omitted in lists
+}
+
+func newDeliveryView(d *storage.Delivery, withPayload bool) *deliveryView {
+	v := &deliveryView{Delivery: d}
+	if withPayload {
+		v.Payload = payloadView(d.Payload)
+	}
+	return v
+}
+
+// webhookRequestView shows logged request the same way as delivery
+type webhookRequestView struct {
+	*storage.WebhookRequest
+	Payload interface{} `json:"payload,omitempty"` // omitted in lists
+}
+
+func newWebhookRequestView(r *storage.WebhookRequest, withPayload bool) *webhookRequestView {
+	v := &webhookRequestView{WebhookRequest: r}
+	if withPayload {
+		v.Payload = payloadView(r.Payload)
+	}
+	return v
+}
+
+// payloadView returns JSON payload as is and the rest of payloads base64 encoded
+func payloadView(payload []byte) interface{} {
+	if json.Valid(payload) {
+		return json.RawMessage(payload)
+	}
+	return payload
+}
+
+// ListDeliveries replies with webhook deliveries kept in the inbox,
+// optionally filtered by state (e.g. dead ones)
+func (s *server) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	page, err := parsePage(r)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+
+	state := r.URL.Query().Get("state")
+	switch state {
+	case "", storage.DeliveryPending, storage.DeliveryDone, storage.DeliveryDead:
+	default:
+		http.Error(w, fmt.Sprintf("invalid state value: %s", state), 400)
+		return
+	}
+
+	deliveries, err := s.services.Storage.ListDeliveries(r.Context(), state, page)
+	if err != nil {
+		s.replyStorageError(w, err)
+		return
+	}
+
+	items := make([]*deliveryView, 0, len(deliveries))
+	var last common.Model
+	for _, d := range deliveries {
+		items = append(items, newDeliveryView(d, false))
+		last = d
+	}
+	s.writeJSON(w, &pageView{Items: items, NextCursor: nextCursor(page, len(items), last)})
+}
+
+// GetDelivery replies with a single delivery including its payload
+func (s *server) GetDelivery(w http.ResponseWriter, r *http.Request) {
+	id, ok := deliveryID(w, r)
+	if !ok {
+		return
+	}
+
+	d, err := s.services.Storage.GetDelivery(r.Context(), id)
+	if err != nil {
+		s.replyStorageError(w, err)
+		return
+	}
+	s.writeJSON(w, newDeliveryView(d, true))
+}
+
+// RetryDelivery returns dead delivery into processing
+func (s *server) RetryDelivery(w http.ResponseWriter, r *http.Request) {
+	id, ok := deliveryID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := s.services.Inbox.Requeue(r.Context(), id); err != nil {
+		s.replyStorageError(w, err)
+		return
+	}
+	s.services.Logger.WithField("delivery", id).WithField("actor", actor(r)).Info("dead webhook delivery requeued")
+	w.WriteHeader(202)
+}
+
+// ListWebhookRequests replies with raw webhook requests kept in the log,
+// optionally filtered by provider, outcome and receive time
+func (s *server) ListWebhookRequests(w http.ResponseWriter, r *http.Request) {
+	filter, ok := parseWebhookRequestFilter(w, r)
+	if !ok {
+		return
+	}
+	page, err := parsePage(r)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+
+	requests, err := s.services.Storage.ListWebhookRequests(r.Context(), filter, page)
+	if err != nil {
+		s.replyStorageError(w, err)
+		return
+	}
+
+	items := make([]*webhookRequestView, 0, len(requests))
+	var last common.Model
+	for _, logged := range requests {
+		items = append(items, newWebhookRequestView(logged, false))
+		last = logged
+	}
+	s.writeJSON(w, &pageView{Items: items, NextCursor: nextCursor(page, len(items), last)})
+}
+
+// ExportWebhookRequests streams logged requests matching the filter as JSON lines,
+// the newest first; payloads are base64 encoded so that they are kept byte for byte
+// (see 'buildgraph replay')
+func (s *server) ExportWebhookRequests(w http.ResponseWriter, r *http.Request) {
+	filter, ok := parseWebhookRequestFilter(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	encoder := json.NewEncoder(w)
+	page := storage.Page{Limit: maxPageLimit}
+	for {
+		requests, err := s.services.Storage.ListWebhookRequests(r.Context(), filter, page)
+		if err != nil {
+			// the reply may have been started already, so it is just cut off
+			s.services.Logger.WithError(err).Error("failed to export webhook requests")
+			return
+		}
+		for _, logged := range requests {
+			if err := encoder.Encode(logged); err != nil {
+				s.services.Logger.WithError(err).Error("failed to encode response")
+				return
+			}
+		}
+		if len(requests) < page.Limit {
+			return
+		}
+		page.Before = requests[len(requests)-1].ID
+	}
+}
+
+// GetWebhookRequest replies with a single logged request including its payload
+func (s *server) GetWebhookRequest(w http.ResponseWriter, r *http.Request) {
+	id, ok := deliveryID(w, r)
+	if !ok {
+		return
+	}
+
+	logged, err := s.services.Storage.GetWebhookRequest(r.Context(), id)
+	if err != nil {
+		s.replyStorageError(w, err)
+		return
+	}
+	s.writeJSON(w, newWebhookRequestView(logged, true))
+}
+
+// ReplayWebhookRequest puts logged request into the inbox as a new delivery;
+// secrets are not logged, so it can't be authenticated again, and only
+// the requests that have passed authentication once may be replayed
+func (s *server) ReplayWebhookRequest(w http.ResponseWriter, r *http.Request) {
+	id, ok := deliveryID(w, r)
+	if !ok {
+		return
+	}
+
+	logged, err := s.services.Storage.GetWebhookRequest(r.Context(), id)
+	if err != nil {
+		s.replyStorageError(w, err)
+		return
+	}
+	if logged.Outcome == storage.RequestRejected {
+		http.Error(w, fmt.Sprintf("webhook request %d has been rejected, it can't be replayed", id), 409)
+		return
+	}
+
+	var (
+		p     vcs.Provider
+		known bool
+	)
+	if logged.Provider != "" {
+		p, known = s.services.VCS.Get(logged.Provider)
+	} else {
+		p, known = s.services.VCS.Detect(logged.Header)
+	}
+	if !known {
+		http.Error(w, fmt.Sprintf("VCS provider of webhook request %d is unknown", id), 409)
+		return
+	}
+
+	d, err := s.services.Inbox.Replay(r.Context(), p, &vcs.Delivery{Header: logged.Header, Payload: logged.Payload})
+	if err != nil {
+		s.replyStorageError(w, err)
+		return
+	}
+	s.services.Logger.WithFields(logrus.Fields{
+		"request":  id,
+		"delivery": d.ID,
+		"actor":    actor(r),
+	}).Info("webhook request replayed")
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(202)
+	s.writeJSON(w, newDeliveryView(d, false))
+}
+
+// parseWebhookRequestFilter reads filter of request log; replies with error if it is invalid
+func parseWebhookRequestFilter(w http.ResponseWriter, r *http.Request) (*storage.WebhookRequestFilter, bool) {
+	query := r.URL.Query()
+	filter := &storage.WebhookRequestFilter{
+		Provider: query.Get("provider"),
+		Outcome:  query.Get("outcome"),
+	}
+	switch filter.Outcome {
+	case "", storage.RequestAccepted, storage.RequestDuplicate, storage.RequestRejected, storage.RequestFailed:
+	default:
+		http.Error(w, fmt.Sprintf("invalid outcome value: %s", filter.Outcome), 400)
+		return nil, false
+	}
+	if value := query.Get("since"); value != "" {
+		var err error
+		if filter.Since, err = time.Parse(time.RFC3339, value); err != nil {
+			http.Error(w, fmt.Sprintf("invalid since value (RFC3339 expected): %s", value), 400)
+			return nil, false
+		}
+	}
+	return filter, true
+}
+
+// deliveryID reads ID of delivery or webhook request from URL; replies with error if it is invalid
+func deliveryID(w http.ResponseWriter, r *http.Request) (common.ObjectID, bool) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "invalid ID", 400)
+		return 0, false
+	}
+	return id, true
+}
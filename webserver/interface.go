@@ -8,5 +8,35 @@ import (
 
 type Webserver interface {
 	common.Service
-	GitlabPushEvent(http.ResponseWriter, *http.Request)
+	common.HealthChecker
+	VCSEvent(http.ResponseWriter, *http.Request)
+	Hook(http.ResponseWriter, *http.Request)
+	ReloadConfig(http.ResponseWriter, *http.Request)
+	ListProjects(http.ResponseWriter, *http.Request)
+	GetProject(http.ResponseWriter, *http.Request)
+	SaveProject(http.ResponseWriter, *http.Request)
+	DeleteProject(http.ResponseWriter, *http.Request)
+	ListRelations(http.ResponseWriter, *http.Request)
+	SaveRelation(http.ResponseWriter, *http.Request)
+	DeleteRelation(http.ResponseWriter, *http.Request)
+	ListAuditRecords(http.ResponseWriter, *http.Request)
+	ListVCSProjects(http.ResponseWriter, *http.Request)
+	GetVCSProject(http.ResponseWriter, *http.Request)
+	ListPushEvents(http.ResponseWriter, *http.Request)
+	ListCommits(http.ResponseWriter, *http.Request)
+	Graph(http.ResponseWriter, *http.Request)
+	Downstream(http.ResponseWriter, *http.Request)
+	Upstream(http.ResponseWriter, *http.Request)
+	Plan(http.ResponseWriter, *http.Request)
+	EventStream(http.ResponseWriter, *http.Request)
+	EventSocket(http.ResponseWriter, *http.Request)
+	ListDeliveries(http.ResponseWriter, *http.Request)
+	GetDelivery(http.ResponseWriter, *http.Request)
+	RetryDelivery(http.ResponseWriter, *http.Request)
+	ListWebhookRequests(http.ResponseWriter, *http.Request)
+	ExportWebhookRequests(http.ResponseWriter, *http.Request)
+	GetWebhookRequest(http.ResponseWriter, *http.Request)
+	ReplayWebhookRequest(http.ResponseWriter, *http.Request)
+	Healthz(http.ResponseWriter, *http.Request)
+	Readyz(http.ResponseWriter, *http.Request)
 }
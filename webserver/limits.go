@@ -0,0 +1,190 @@
+package webserver
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/urfave/negroni"
+	"golang.org/x/time/rate"
+
+	"github.com/vitalyisaev2/buildgraph/config"
+	"github.com/vitalyisaev2/buildgraph/metrics"
+	"github.com/vitalyisaev2/buildgraph/vcs"
+)
+
+const (
+	// limiters of the clients that haven't sent requests for this period are dropped
+	limiterIdleTimeout = 10 * time.Minute
+)
+
+var errRateLimited = errors.New("too many requests")
+
+// limitBody makes negroni middleware rejecting requests with bodies
+// larger than allowed for the route group; bodies of unknown length
+// are cut off while being read, so handlers should reply with replyBodyError
+func limitBody(router *mux.Router, cfg *config.LimitsConfig) negroni.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+		route := routeName(router, r)
+		limit := cfg.MaxBodySize[route]
+		if r.ContentLength > limit {
+			metrics.RejectedRequests.WithLabelValues(route, metrics.ReasonBodyTooLarge).Inc()
+			http.Error(w, fmt.Sprintf("request body is larger than %d bytes", limit), 413)
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, limit)
+		next(w, r)
+	}
+}
+
+// replyBodyError replies to request which body could not be read or decoded
+func replyBodyError(w http.ResponseWriter, route string, err error) {
+	http.Error(w, err.Error(), bodyErrorStatus(route, err))
+}
+
+// bodyErrorStatus returns status of reply to request which body could not be read or decoded
+func bodyErrorStatus(route string, err error) int {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		metrics.RejectedRequests.WithLabelValues(route, metrics.ReasonBodyTooLarge).Inc()
+		return 413
+	}
+	return 400
+}
+
+// limitRate makes negroni middleware applying token bucket rate limiters
+// to route groups; groups without limiters are passed through as is.
+// Requests are not authenticated yet, so they are told apart by client address
+// even if the group is limited by provider (see limitProvider)
+func limitRate(router *mux.Router, cfg *config.LimitsConfig) negroni.HandlerFunc {
+	limiters := make(map[string]*rateLimiter, len(cfg.RateLimits))
+	for route, limit := range cfg.RateLimits {
+		limiters[route] = newRateLimiter(limit)
+	}
+
+	return func(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+		route := routeName(router, r)
+		limiter, exists := limiters[route]
+		if !exists {
+			next(w, r)
+			return
+		}
+
+		if delay := limiter.reserve(clientAddress(r)); delay > 0 {
+			retryAfter(w, route, delay)
+			http.Error(w, errRateLimited.Error(), 429)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// limitProvider applies rate limit of webhook route group keyed by provider
+// to authenticated deliveries, so that requests pretending to come from VCS
+// don't hold back the real ones; it returns the time till the next token,
+// zero if delivery is allowed or the group is not limited by provider
+func (s *server) limitProvider(p vcs.Provider) time.Duration {
+	if s.providerLimiter == nil {
+		return 0
+	}
+	return s.providerLimiter.reserve(p.Name())
+}
+
+// retryAfter counts rate limited request and tells client
+// when the next token is available
+func retryAfter(w http.ResponseWriter, route string, delay time.Duration) {
+	metrics.RejectedRequests.WithLabelValues(route, metrics.ReasonRateLimited).Inc()
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(delay.Seconds()))))
+}
+
+// clientAddress returns host of the client without port
+func clientAddress(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// rateLimiter keeps separate token bucket for every source of requests
+type rateLimiter struct {
+	limit rate.Limit
+	burst int
+
+	mutex   sync.Mutex
+	buckets map[string]*bucket
+	swept   time.Time // the last time idle buckets were dropped
+}
+
+type bucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// reserve takes token from the bucket of the source; returns zero if request
+// is allowed, or the time till the next token otherwise
+func (l *rateLimiter) reserve(source string) time.Duration {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := time.Now()
+	if now.Sub(l.swept) >= limiterIdleTimeout {
+		for key, b := range l.buckets {
+			if now.Sub(b.lastSeen) >= limiterIdleTimeout {
+				delete(l.buckets, key)
+			}
+		}
+		l.swept = now
+	}
+
+	b, exists := l.buckets[source]
+	if !exists {
+		b = &bucket{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.buckets[source] = b
+	}
+	b.lastSeen = now
+
+	reservation := b.limiter.ReserveN(now, 1)
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		// rejected request must not take the token of the next one
+		reservation.CancelAt(now)
+		return delay
+	}
+	return 0
+}
+
+func newRateLimiter(cfg *config.RateLimitConfig) *rateLimiter {
+	return &rateLimiter{
+		limit:   rate.Limit(cfg.Rate),
+		burst:   cfg.Burst,
+		buckets: make(map[string]*bucket),
+		swept:   time.Now(),
+	}
+}
+
+// unlimitStreams lifts connection deadlines for event streams, which are
+// expected to outlive read and write timeouts; it has to wrap negroni,
+// since deadlines can't be reached through negroni's response writer
+func unlimitStreams(router *mux.Router, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if routeName(router, r) == routeStream {
+			rc := http.NewResponseController(w)
+			// there is nothing to lift if connection doesn't support deadlines
+			if err := rc.SetReadDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+				http.Error(w, err.Error(), 500)
+				return
+			}
+			if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+				http.Error(w, err.Error(), 500)
+				return
+			}
+		}
+		h.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,64 @@
+package webserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vitalyisaev2/buildgraph/config"
+	"github.com/vitalyisaev2/buildgraph/storage"
+	"github.com/vitalyisaev2/buildgraph/vcs/gitlab"
+)
+
+func TestLimitProvider(t *testing.T) {
+	s := &memStorage{}
+	ts := newTestServer(t, s, func(cfg *config.Config) {
+		cfg.Webserver.Limits.RateLimits = map[string]*config.RateLimitConfig{
+			config.RouteGroupWebhook: {Rate: 0.001, Burst: 2, Key: config.RateLimitByProvider},
+		}
+		cfg.VCS = &config.VCSConfig{
+			Gitlab: &config.GitlabConfig{
+				Endpoint: "http://127.0.0.1:1",
+				Token:    "api-token",
+				Webhook:  &config.GitlabWebhookConfig{Token: "webhook-secret"},
+			},
+		}
+	})
+
+	send := func(remoteAddr, path, token string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("POST", path, strings.NewReader(`{"project": {"path_with_namespace": "namespace1/project1"}}`))
+		r.RemoteAddr = remoteAddr
+		r.Header.Set(gitlab.EventHeader, "Push Hook")
+		r.Header.Set(gitlab.TokenHeader, token)
+		w := httptest.NewRecorder()
+		ts.Config.Handler.ServeHTTP(w, r)
+		return w
+	}
+
+	// forged deliveries are limited by client address only
+	assert.Equal(t, 401, send("192.0.2.1:40000", "/vcs/gitlab/events", "forged").Code)
+	assert.Equal(t, 401, send("192.0.2.1:40001", "/hooks", "forged").Code)
+	w := send("192.0.2.1:40002", "/vcs/gitlab/events", "forged")
+	assert.Equal(t, 429, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+	// and made up provider names don't get buckets of their own
+	assert.Equal(t, 429, send("192.0.2.1:40003", "/vcs/made-up/events", "forged").Code)
+
+	// authenticated deliveries are limited by provider regardless of address
+	assert.Equal(t, 202, send("192.0.2.2:40000", "/vcs/gitlab/events", "webhook-secret").Code)
+	assert.Equal(t, 202, send("192.0.2.3:40000", "/hooks", "webhook-secret").Code)
+	w = send("192.0.2.4:40000", "/vcs/gitlab/events", "webhook-secret")
+	assert.Equal(t, 429, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	assert.Len(t, s.deliveries, 2)
+	if assert.Len(t, s.requests, 5) {
+		assert.Equal(t, storage.RequestRejected, s.requests[4].Outcome)
+		assert.Equal(t, http.StatusTooManyRequests, s.requests[4].Status)
+	}
+}
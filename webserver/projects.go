@@ -0,0 +1,202 @@
+package webserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/vitalyisaev2/buildgraph/config"
+	"github.com/vitalyisaev2/buildgraph/storage"
+)
+
+const (
+	// default number of audit records returned at once
+	defaultAuditLimit = 100
+)
+
+// relation describes a link between two projects
+type relation struct {
+	Dependency string `json:"dependency"`
+	Dependent  string `json:"dependent"`
+}
+
+// ListProjects replies with descriptions of the registered projects visible to client
+func (s *server) ListProjects(w http.ResponseWriter, r *http.Request) {
+	descriptions := []*config.Description{}
+	for _, d := range s.services.Projects.Config().Descriptions {
+		if principal(r).Can(config.RoleViewer, d.Namespace) {
+			descriptions = append(descriptions, d.Redacted())
+		}
+	}
+	sort.Slice(descriptions, func(i, j int) bool { return descriptions[i].ID < descriptions[j].ID })
+	s.writeJSON(w, descriptions)
+}
+
+// GetProject replies with description of a single project
+func (s *server) GetProject(w http.ResponseWriter, r *http.Request) {
+	d, exists := s.services.Projects.Description(mux.Vars(r)["id"])
+	if !exists {
+		http.Error(w, storage.ErrNotFound.Error(), 404)
+		return
+	}
+	if !authorize(w, r, config.RoleViewer, d.Namespace) {
+		return
+	}
+	s.writeJSON(w, d.Redacted())
+}
+
+// SaveProject creates or updates project description
+func (s *server) SaveProject(w http.ResponseWriter, r *http.Request) {
+	if r.Body == nil {
+		http.Error(w, "please send request body", 400)
+		return
+	}
+
+	var d config.Description
+	if err := json.NewDecoder(r.Body).Decode(&d); err != nil {
+		replyBodyError(w, routeAPI, err)
+		return
+	}
+	d.ID = mux.Vars(r)["id"]
+	if err := d.Validate(); err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	// moving project between namespaces requires permissions within both of them
+	prev, exists := s.services.Projects.Description(d.ID)
+	if exists && !authorize(w, r, config.RoleOperator, prev.Namespace) {
+		return
+	}
+	if exists {
+		d.RestoreRedacted(prev)
+	}
+	if !authorize(w, r, config.RoleOperator, d.Namespace) {
+		return
+	}
+
+	err := s.services.Storage.SaveProject(r.Context(), &d, actor(r))
+	s.replyProjectsChange(w, r, err)
+}
+
+// DeleteProject removes project with all its relations
+func (s *server) DeleteProject(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	if !authorize(w, r, config.RoleOperator, s.projectNamespace(id)) {
+		return
+	}
+	err := s.services.Storage.DeleteProject(r.Context(), id, actor(r))
+	s.replyProjectsChange(w, r, err)
+}
+
+// ListRelations replies with the relations between projects visible to client
+func (s *server) ListRelations(w http.ResponseWriter, r *http.Request) {
+	visible := s.visible(r)
+	result := []relation{}
+	for dependency, dependents := range s.services.Projects.Config().Relations {
+		for _, dependent := range dependents {
+			if visible(dependency) && visible(dependent) {
+				result = append(result, relation{Dependency: dependency, Dependent: dependent})
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Dependency != result[j].Dependency {
+			return result[i].Dependency < result[j].Dependency
+		}
+		return result[i].Dependent < result[j].Dependent
+	})
+	s.writeJSON(w, result)
+}
+
+// SaveRelation makes one project depend on another; relations
+// are managed by owners of the dependent project
+func (s *server) SaveRelation(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	if !authorize(w, r, config.RoleOperator, s.projectNamespace(vars["dependent"])) {
+		return
+	}
+	err := s.services.Storage.SaveRelation(r.Context(), vars["dependency"], vars["dependent"], actor(r))
+	s.replyProjectsChange(w, r, err)
+}
+
+// DeleteRelation removes relation between projects
+func (s *server) DeleteRelation(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	if !authorize(w, r, config.RoleOperator, s.projectNamespace(vars["dependent"])) {
+		return
+	}
+	err := s.services.Storage.DeleteRelation(r.Context(), vars["dependency"], vars["dependent"], actor(r))
+	s.replyProjectsChange(w, r, err)
+}
+
+// ListAuditRecords replies with the latest changes of projects
+func (s *server) ListAuditRecords(w http.ResponseWriter, r *http.Request) {
+	limit := defaultAuditLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		var err error
+		if limit, err = strconv.Atoi(value); err != nil || limit <= 0 {
+			http.Error(w, "invalid limit value", 400)
+			return
+		}
+	}
+
+	records, err := s.services.Storage.ListAuditRecords(r.Context(), limit)
+	if err != nil {
+		s.replyStorageError(w, err)
+		return
+	}
+	if records == nil {
+		records = []*storage.AuditRecord{}
+	}
+	s.writeJSON(w, records)
+}
+
+// replyProjectsChange refreshes project registry after successful change
+// and replies with the list of changes applied to project graph
+func (s *server) replyProjectsChange(w http.ResponseWriter, r *http.Request, err error) {
+	if err != nil {
+		s.replyStorageError(w, err)
+		return
+	}
+
+	diff, err := s.services.RefreshProjects(r.Context())
+	if err != nil {
+		s.services.Logger.WithError(err).Error("failed to refresh project registry")
+		http.Error(w, err.Error(), 500)
+		return
+	}
+	s.writeJSON(w, diff)
+}
+
+// replyStorageError maps storage errors onto HTTP status codes
+func (s *server) replyStorageError(w http.ResponseWriter, err error) {
+	switch err.(type) {
+	case *storage.ConflictError:
+		http.Error(w, err.Error(), 409)
+		return
+	}
+	if err == storage.ErrNotFound {
+		http.Error(w, err.Error(), 404)
+		return
+	}
+	s.services.Logger.WithError(err).Error("storage request failed")
+	http.Error(w, err.Error(), 500)
+}
+
+func (s *server) writeJSON(w http.ResponseWriter, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		s.services.Logger.WithError(err).Error("failed to encode response")
+	}
+}
+
+// actor returns the name changes are made on behalf of
+func actor(r *http.Request) string {
+	if name := principal(r).Name; name != "" {
+		return name
+	}
+	return r.RemoteAddr
+}
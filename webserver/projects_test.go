@@ -0,0 +1,87 @@
+package webserver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vitalyisaev2/buildgraph/config"
+	"github.com/vitalyisaev2/buildgraph/storage"
+)
+
+func TestProjectsAPI(t *testing.T) {
+	s := &memStorage{}
+	ts := newTestServer(t, s)
+
+	project := map[string]interface{}{
+		"namespace": "namespace1",
+		"name":      "project3",
+		"build": map[string]interface{}{
+			"command": "make",
+			"timeout": "10m",
+			"env":     map[string]string{"TOKEN": "s3cr3t"},
+		},
+	}
+	var diff config.ProjectsDiff
+	decode(t, call(t, ts, "PUT", "/projects/n1_p3", "operator-key", project), &diff)
+	assert.Equal(t, []string{"n1_p3"}, diff.Added)
+
+	var d config.Description
+	decode(t, call(t, ts, "GET", "/projects/n1_p3", "viewer-key", nil), &d)
+	assert.Equal(t, "project3", d.Name)
+	if assert.NotNil(t, d.Build) {
+		assert.Equal(t, 10*time.Minute, d.Build.Timeout)
+		assert.Equal(t, map[string]string{"TOKEN": "<redacted>"}, d.Build.Env)
+	}
+
+	// description can be saved back as it has been read
+	d.Build.Command = "make all"
+	decode(t, call(t, ts, "PUT", "/projects/n1_p3", "operator-key", &d), &diff)
+	assert.Equal(t, []string{"n1_p3"}, diff.Changed)
+	stored, err := s.LoadProjects(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last := stored.Descriptions[len(stored.Descriptions)-1]; assert.Equal(t, "n1_p3", last.ID) {
+		assert.Equal(t, "make all", last.Build.Command)
+		assert.Equal(t, map[string]string{"TOKEN": "s3cr3t"}, last.Build.Env)
+	}
+
+	// project can't be moved into namespace of other owners
+	project["namespace"] = "namespace2"
+	resp := call(t, ts, "PUT", "/projects/n1_p3", "operator-key", project)
+	assert.Equal(t, 403, resp.StatusCode)
+	resp = call(t, ts, "PUT", "/projects/n1_p3", "operator-key", []string{"project3"})
+	assert.Equal(t, 400, resp.StatusCode)
+	resp = call(t, ts, "PUT", "/projects/n1_p3", "operator-key", map[string]string{"namespace": "namespace1"})
+	assert.Equal(t, 400, resp.StatusCode)
+
+	decode(t, call(t, ts, "PUT", "/relations/n1_p3/n1_p1", "operator-key", nil), &diff)
+	assert.Equal(t, []string{"n1_p3 -> n1_p1"}, diff.LinksAdded)
+
+	// changes breaking project graph are rejected
+	resp = call(t, ts, "PUT", "/relations/n1_p2/n1_p3", "operator-key", nil)
+	assert.Equal(t, 409, resp.StatusCode)
+	resp = call(t, ts, "PUT", "/relations/missing/n1_p3", "operator-key", nil)
+	assert.Equal(t, 409, resp.StatusCode)
+	resp = call(t, ts, "DELETE", "/relations/n1_p2/n1_p3", "operator-key", nil)
+	assert.Equal(t, 404, resp.StatusCode)
+
+	decode(t, call(t, ts, "DELETE", "/projects/n1_p3", "operator-key", nil), &diff)
+	assert.Equal(t, []string{"n1_p3"}, diff.Removed)
+	assert.Equal(t, []string{"n1_p3 -> n1_p1"}, diff.LinksRemoved)
+	resp = call(t, ts, "GET", "/projects/n1_p3", "viewer-key", nil)
+	assert.Equal(t, 404, resp.StatusCode)
+
+	var records []*storage.AuditRecord
+	decode(t, call(t, ts, "GET", "/audit?limit=2", "admin-key", nil), &records)
+	if assert.Len(t, records, 2) {
+		assert.Equal(t, "project.delete", records[0].Action)
+		assert.Equal(t, "relation.create", records[1].Action)
+		assert.Equal(t, "operator", records[1].Actor)
+	}
+	resp = call(t, ts, "GET", "/audit?limit=0", "admin-key", nil)
+	assert.Equal(t, 400, resp.StatusCode)
+}
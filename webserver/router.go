@@ -1,57 +1,71 @@
 package webserver
 
 import (
-	"context"
-	"encoding/json"
-	"fmt"
 	"net/http"
 
 	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 
-	"github.com/vitalyisaev2/buildgraph/vcs/gitlab"
+	"github.com/vitalyisaev2/buildgraph/config"
 )
 
+// names of the routes treated specially by middleware; they are
+// also the route groups server limits are configured for
 const (
-	gitlabEventHeader = "X-Gitlab-Event"
-	gitlabPushHook    = "Push Hook"
+	routeAPI     = config.RouteGroupAPI     // the rest of routes, left unnamed
+	routeWebhook = config.RouteGroupWebhook // authenticated with VCS specific settings instead of API credentials
+	routeProbe   = config.RouteGroupProbe   // health checks and metrics, open to everyone
+	routeAdmin   = config.RouteGroupAdmin   // may additionally require client certificate
+	routeStream  = config.RouteGroupStream  // long-living connections, not limited by timeouts
 )
 
-var (
-	defaultCtx = context.Background()
-)
+var publicRoutes = map[string]bool{routeWebhook: true, routeProbe: true}
 
-// newRouter builds new router instance
-func newRouter(s Webserver) http.Handler {
+// newRouter builds new router instance; handlers that are specific
+// to namespace check permissions of the client themselves
+func newRouter(s Webserver) *mux.Router {
 	router := mux.NewRouter()
-	router.HandleFunc("/vcs/gitlab/events/push", s.GitlabPushEvent)
+	router.HandleFunc("/vcs/{provider}/events", s.VCSEvent).Methods("POST").Name(routeWebhook)
+	// kept for webhooks registered before other Gitlab events were supported
+	router.HandleFunc("/vcs/{provider:gitlab}/events/push", s.VCSEvent).Methods("POST").Name(routeWebhook)
+	router.HandleFunc("/hooks", s.Hook).Methods("POST").Name(routeWebhook)
+	router.HandleFunc("/admin/config/reload", requireGlobal(config.RoleAdmin, s.ReloadConfig)).Methods("POST").Name(routeAdmin)
+	router.HandleFunc("/projects", require(config.RoleViewer, s.ListProjects)).Methods("GET")
+	router.HandleFunc("/projects/{id}", require(config.RoleViewer, s.GetProject)).Methods("GET")
+	router.HandleFunc("/projects/{id}", require(config.RoleOperator, s.SaveProject)).Methods("PUT")
+	router.HandleFunc("/projects/{id}", require(config.RoleOperator, s.DeleteProject)).Methods("DELETE")
+	router.HandleFunc("/projects/{id}/downstream", require(config.RoleViewer, s.Downstream)).Methods("GET")
+	router.HandleFunc("/projects/{id}/upstream", require(config.RoleViewer, s.Upstream)).Methods("GET")
+	router.HandleFunc("/relations", require(config.RoleViewer, s.ListRelations)).Methods("GET")
+	router.HandleFunc("/graph", require(config.RoleViewer, s.Graph)).Methods("GET")
+	router.HandleFunc("/plan", require(config.RoleViewer, s.Plan)).Methods("POST")
+	router.HandleFunc("/relations/{dependency}/{dependent}", require(config.RoleOperator, s.SaveRelation)).Methods("PUT")
+	router.HandleFunc("/relations/{dependency}/{dependent}", require(config.RoleOperator, s.DeleteRelation)).Methods("DELETE")
+	router.HandleFunc("/audit", requireGlobal(config.RoleAdmin, s.ListAuditRecords)).Methods("GET").Name(routeAdmin)
+	router.HandleFunc("/vcs/projects", require(config.RoleViewer, s.ListVCSProjects)).Methods("GET")
+	router.HandleFunc("/vcs/projects/{id:[0-9]+}", require(config.RoleViewer, s.GetVCSProject)).Methods("GET")
+	router.HandleFunc("/vcs/projects/{id:[0-9]+}/events", require(config.RoleViewer, s.ListPushEvents)).Methods("GET")
+	router.HandleFunc("/vcs/projects/{id:[0-9]+}/commits", require(config.RoleViewer, s.ListCommits)).Methods("GET")
+	router.HandleFunc("/events/stream", require(config.RoleViewer, s.EventStream)).Methods("GET").Name(routeStream)
+	router.HandleFunc("/events/ws", require(config.RoleViewer, s.EventSocket)).Methods("GET").Name(routeStream)
+	router.HandleFunc("/inbox", requireGlobal(config.RoleAdmin, s.ListDeliveries)).Methods("GET").Name(routeAdmin)
+	router.HandleFunc("/inbox/{id:[0-9]+}", requireGlobal(config.RoleAdmin, s.GetDelivery)).Methods("GET").Name(routeAdmin)
+	router.HandleFunc("/inbox/{id:[0-9]+}/retry", requireGlobal(config.RoleAdmin, s.RetryDelivery)).Methods("POST").Name(routeAdmin)
+	router.HandleFunc("/inbox/requests", requireGlobal(config.RoleAdmin, s.ListWebhookRequests)).Methods("GET").Name(routeAdmin)
+	router.HandleFunc("/inbox/requests/export", requireGlobal(config.RoleAdmin, s.ExportWebhookRequests)).Methods("GET").Name(routeAdmin)
+	router.HandleFunc("/inbox/requests/{id:[0-9]+}", requireGlobal(config.RoleAdmin, s.GetWebhookRequest)).Methods("GET").Name(routeAdmin)
+	router.HandleFunc("/inbox/requests/{id:[0-9]+}/replay", requireGlobal(config.RoleAdmin, s.ReplayWebhookRequest)).Methods("POST").Name(routeAdmin)
+	router.HandleFunc("/healthz", s.Healthz).Methods("GET").Name(routeProbe)
+	router.HandleFunc("/readyz", s.Readyz).Methods("GET").Name(routeProbe)
+	router.Handle("/metrics", promhttp.Handler()).Methods("GET").Name(routeProbe)
 	return router
 }
 
-func (s *server) GitlabPushEvent(w http.ResponseWriter, r *http.Request) {
-
-	if r.Header.Get(gitlabEventHeader) != gitlabPushHook {
-		http.Error(w, fmt.Sprintf("wrong %s header value", gitlabEventHeader), 400)
-		return
+// routeName returns name of the route matching request
+func routeName(router *mux.Router, r *http.Request) string {
+	var match mux.RouteMatch
+	if router.Match(r, &match) && match.Route != nil && match.Route.GetName() != "" {
+		return match.Route.GetName()
 	}
-
-	if r.Body == nil {
-		http.Error(w, "please send request body", 400)
-		return
-	}
-
-	var event gitlab.PushEvent
-	err := json.NewDecoder(r.Body).Decode(&event)
-	if err != nil {
-		http.Error(w, err.Error(), 400)
-		return
-	}
-
-	err = s.services.Storage.SavePushEvent(defaultCtx, &event)
-	if err != nil {
-		s.services.Logger.WithError(err).Error("failed to save event")
-		http.Error(w, err.Error(), 500)
-		return
-	}
-
-	w.WriteHeader(200)
+	return routeAPI
 }
@@ -2,10 +2,15 @@ package webserver
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net"
 	"net/http"
+	"sync"
 	"time"
 
 	negronilogrus "github.com/meatballhat/negroni-logrus"
+	"github.com/sirupsen/logrus"
 	"github.com/urfave/negroni"
 	"github.com/vitalyisaev2/buildgraph/common"
 	"github.com/vitalyisaev2/buildgraph/config"
@@ -28,8 +33,16 @@ type server struct {
 	// long-running server subsystems
 	services *service.Collection
 
+	// limits authenticated webhook deliveries by provider, if configured
+	providerLimiter *rateLimiter
+
 	// channel to dump fatal error to
 	errChan chan<- error
+
+	// state of the listener reported by health check
+	listenerMutex sync.Mutex
+	listening     bool
+	listenerErr   error
 }
 
 func (s *server) Stop() {
@@ -38,28 +51,101 @@ func (s *server) Stop() {
 	s.httpServer.Shutdown(ctx)
 }
 
-func NewWebServer(services *service.Collection, cfg *config.WebserverConfig, errChan chan<- error) common.Service {
+// CheckHealth reports whether the listener accepts connections
+func (s *server) CheckHealth(context.Context) error {
+	s.listenerMutex.Lock()
+	defer s.listenerMutex.Unlock()
+	if s.listenerErr != nil {
+		return s.listenerErr
+	}
+	if !s.listening {
+		return errors.New("listener is not started yet")
+	}
+	return nil
+}
+
+func (s *server) setListenerState(listening bool, err error) {
+	s.listenerMutex.Lock()
+	defer s.listenerMutex.Unlock()
+	s.listening, s.listenerErr = listening, err
+}
+
+// handler composes multiplexor from gorilla router and negroni middleware
+func (s *server) handler() http.Handler {
+	router := newRouter(s)
+	n := negroni.New()
+	n.Use(negronilogrus.NewMiddlewareFromLogger(s.services.Logger, "webserver"))
+	n.Use(limitRate(router, s.cfg.Limits))
+	if limit := s.cfg.Limits.RateLimits[routeWebhook]; limit != nil && limit.Key == config.RateLimitByProvider {
+		s.providerLimiter = newRateLimiter(limit)
+	}
+	n.Use(limitBody(router, s.cfg.Limits))
+	if s.cfg.TLS != nil && s.cfg.TLS.ClientCAFile != "" {
+		n.Use(requireClientCert(router))
+	}
+	n.Use(s.authenticate(router))
+	n.UseHandler(router)
+	return unlimitStreams(router, n)
+}
+
+func NewWebServer(services *service.Collection, cfg *config.WebserverConfig, errChan chan<- error) (common.Service, error) {
 	s := &server{
 		httpServer: &http.Server{
-			Addr: cfg.Endpoint,
+			Addr:              cfg.Endpoint,
+			ReadHeaderTimeout: cfg.Timeouts.ReadHeader,
+			ReadTimeout:       cfg.Timeouts.Read,
+			WriteTimeout:      cfg.Timeouts.Write,
+			IdleTimeout:       cfg.Timeouts.Idle,
 		},
+		cfg:      cfg,
 		services: services,
 		errChan:  errChan,
 	}
 
-	// compose multiplexor from gorilla router and negroni middleware
-	router := newRouter(s)
-	n := negroni.New()
-	n.Use(negronilogrus.NewMiddlewareFromLogger(services.Logger, "webserver"))
-	n.UseHandler(router)
-	s.httpServer.Handler = n
+	if cfg.TLS != nil {
+		certs, err := newCertReloader(cfg.TLS, services.Logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load TLS certificate: %v", err)
+		}
+		if s.httpServer.TLSConfig, err = cfg.TLS.Build(certs.GetCertificate); err != nil {
+			return nil, fmt.Errorf("failed to prepare TLS settings: %v", err)
+		}
+	}
+
+	if !services.Authenticator().Enabled() {
+		services.Logger.Warn("API authentication is not configured, every client is an admin")
+	}
+	for _, p := range services.VCS.Providers() {
+		if !p.Secured() {
+			services.Logger.WithField("provider", p.Name()).Warn(
+				"webhook secrets are not configured, deliveries are not authenticated")
+		}
+	}
+
+	s.httpServer.Handler = s.handler()
+	services.AddHealthCheck("listener", s, true)
 
 	go func() {
-		services.Logger.WithField("endpoint", cfg.Endpoint).Debug("starting listener")
-		if err := s.httpServer.ListenAndServe(); err != nil {
+		services.Logger.WithFields(logrus.Fields{
+			"endpoint": cfg.Endpoint,
+			"tls":      cfg.TLS != nil,
+		}).Debug("starting listener")
+
+		ln, err := net.Listen("tcp", s.httpServer.Addr)
+		if err == nil {
+			s.setListenerState(true, nil)
+			if cfg.TLS != nil {
+				// certificate is provided by TLSConfig.GetCertificate
+				err = s.httpServer.ServeTLS(ln, "", "")
+			} else {
+				err = s.httpServer.Serve(ln)
+			}
+		}
+		s.setListenerState(false, err)
+		if err != nil {
 			s.errChan <- err
 		}
 	}()
 
-	return s
+	return s, nil
 }
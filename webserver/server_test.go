@@ -0,0 +1,272 @@
+package webserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/vitalyisaev2/buildgraph/auth"
+	"github.com/vitalyisaev2/buildgraph/common"
+	"github.com/vitalyisaev2/buildgraph/config"
+	"github.com/vitalyisaev2/buildgraph/service"
+	"github.com/vitalyisaev2/buildgraph/storage"
+)
+
+// memStorage keeps projects, deliveries and webhook requests in memory;
+// calls of the other storage methods panic
+type memStorage struct {
+	storage.Storage
+
+	mutex      sync.Mutex
+	projects   *config.ProjectsConfig
+	audit      []*storage.AuditRecord
+	deliveries []*storage.Delivery
+	requests   []*storage.WebhookRequest
+}
+
+func (s *memStorage) LoadProjects(ctx context.Context) (*config.ProjectsConfig, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.projects == nil {
+		return &config.ProjectsConfig{}, nil
+	}
+	return s.projects, nil
+}
+
+func (s *memStorage) ImportProjects(ctx context.Context, cfg *config.ProjectsConfig, actor string) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.projects != nil && cfg.Import != config.ImportOverwrite {
+		return false, nil
+	}
+	s.projects = cfg
+	return true, nil
+}
+
+func (s *memStorage) SaveDelivery(ctx context.Context, d *storage.Delivery) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	d.ID = len(s.deliveries) + 1
+	s.deliveries = append(s.deliveries, d)
+	return true, nil
+}
+
+// change applies f to copy of the stored projects; like the real storage,
+// it rejects changes breaking project graph and audits the rest
+func (s *memStorage) change(actor, action, object string, f func(cfg *config.ProjectsConfig) error) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	next := &config.ProjectsConfig{Relations: map[string][]string{}}
+	if s.projects != nil {
+		next.Descriptions = append(next.Descriptions, s.projects.Descriptions...)
+		for dependency, dependents := range s.projects.Relations {
+			next.Relations[dependency] = append([]string{}, dependents...)
+		}
+	}
+	if err := f(next); err != nil {
+		return err
+	}
+	if err := next.Check(); err != nil {
+		return &storage.ConflictError{Reason: err.Error()}
+	}
+
+	s.projects = next
+	s.audit = append(s.audit, &storage.AuditRecord{ID: len(s.audit) + 1, Actor: actor, Action: action, Object: object})
+	return nil
+}
+
+func (s *memStorage) SaveProject(ctx context.Context, d *config.Description, actor string) error {
+	return s.change(actor, "project.save", d.ID, func(cfg *config.ProjectsConfig) error {
+		for i, prev := range cfg.Descriptions {
+			if prev.ID == d.ID {
+				cfg.Descriptions[i] = d
+				return nil
+			}
+		}
+		cfg.Descriptions = append(cfg.Descriptions, d)
+		return nil
+	})
+}
+
+func (s *memStorage) DeleteProject(ctx context.Context, id string, actor string) error {
+	return s.change(actor, "project.delete", id, func(cfg *config.ProjectsConfig) error {
+		for i, d := range cfg.Descriptions {
+			if d.ID == id {
+				cfg.Descriptions = append(cfg.Descriptions[:i:i], cfg.Descriptions[i+1:]...)
+				delete(cfg.Relations, id)
+				for dependency := range cfg.Relations {
+					cfg.Relations[dependency] = without(cfg.Relations[dependency], id)
+				}
+				return nil
+			}
+		}
+		return storage.ErrNotFound
+	})
+}
+
+func (s *memStorage) SaveRelation(ctx context.Context, dependency, dependent string, actor string) error {
+	return s.change(actor, "relation.create", dependency+" -> "+dependent, func(cfg *config.ProjectsConfig) error {
+		known := 0
+		for _, d := range cfg.Descriptions {
+			if d.ID == dependency || d.ID == dependent {
+				known++
+			}
+		}
+		if known != 2 {
+			return &storage.ConflictError{Reason: "project doesn't exist"}
+		}
+		cfg.Relations[dependency] = append(without(cfg.Relations[dependency], dependent), dependent)
+		return nil
+	})
+}
+
+func (s *memStorage) DeleteRelation(ctx context.Context, dependency, dependent string, actor string) error {
+	return s.change(actor, "relation.delete", dependency+" -> "+dependent, func(cfg *config.ProjectsConfig) error {
+		dependents := without(cfg.Relations[dependency], dependent)
+		if len(dependents) == len(cfg.Relations[dependency]) {
+			return storage.ErrNotFound
+		}
+		cfg.Relations[dependency] = dependents
+		return nil
+	})
+}
+
+func (s *memStorage) ListAuditRecords(ctx context.Context, limit int) ([]*storage.AuditRecord, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	var result []*storage.AuditRecord
+	for i := len(s.audit) - 1; i >= 0 && len(result) < limit; i-- {
+		result = append(result, s.audit[i])
+	}
+	return result, nil
+}
+
+func without(ids []string, id string) []string {
+	var result []string
+	for _, item := range ids {
+		if item != id {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+func (s *memStorage) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*storage.Delivery, error) {
+	return nil, nil
+}
+
+func (s *memStorage) CountDeliveries(ctx context.Context, state string) (int, error) {
+	return 0, nil
+}
+
+func (s *memStorage) PruneDeliveries(ctx context.Context, before time.Time) (int, error) {
+	return 0, nil
+}
+
+func (s *memStorage) SaveWebhookRequest(ctx context.Context, r *storage.WebhookRequest) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	r.ID = len(s.requests) + 1
+	s.requests = append(s.requests, r)
+	return nil
+}
+
+func (s *memStorage) GetWebhookRequest(ctx context.Context, id common.ObjectID) (*storage.WebhookRequest, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if id < 1 || id > len(s.requests) {
+		return nil, storage.ErrNotFound
+	}
+	return s.requests[id-1], nil
+}
+
+func (s *memStorage) PruneWebhookRequests(ctx context.Context, before time.Time) (int, error) {
+	return 0, nil
+}
+
+func (s *memStorage) CheckHealth(ctx context.Context) error { return nil }
+
+func (s *memStorage) Stop() {}
+
+// newTestServer serves API with services built from test config on top of the storage;
+// config may be adjusted before the services are started
+func newTestServer(t *testing.T, s storage.Storage, adjust ...func(cfg *config.Config)) *httptest.Server {
+	cfg, err := config.NewConfig("./test/config.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range adjust {
+		f(cfg)
+	}
+
+	logger := logrus.New()
+	logger.Out = ioutil.Discard
+	services, err := service.NewCollectionWithStorage(logger, cfg, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ts := httptest.NewServer((&server{cfg: cfg.Webserver, services: services}).handler())
+	t.Cleanup(func() {
+		ts.Close()
+		services.Stop()
+	})
+	return ts
+}
+
+// call sends request with API key (if any) and JSON body (if any)
+func call(t *testing.T, ts *httptest.Server, method, path, key string, body interface{}) *http.Response {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, ts.URL+path, reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key != "" {
+		req.Header.Set(auth.APIKeyHeader, key)
+	}
+
+	resp, err := ts.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+// decode reads JSON reply into out
+func decode(t *testing.T, resp *http.Response, out interface{}) {
+	if ct := resp.Header.Get("Content-Type"); ct != "application/json" {
+		t.Fatalf("%s %s: unexpected reply %d (%s): %s",
+			resp.Request.Method, resp.Request.URL.Path, resp.StatusCode, ct, readBody(t, resp))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// readBody returns the whole reply as string
+func readBody(t *testing.T, resp *http.Response) string {
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
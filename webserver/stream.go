@@ -0,0 +1,174 @@
+package webserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/vitalyisaev2/buildgraph/config"
+	"github.com/vitalyisaev2/buildgraph/pubsub"
+)
+
+const (
+	// period of keep-alive messages sent to idle subscribers
+	streamHeartbeatPeriod = 15 * time.Second
+	// maximal time of sending a single message to WebSocket subscriber
+	streamWriteTimeout = 10 * time.Second
+	// delay before SSE client reconnects after disconnection
+	sseRetryDelay = 3 * time.Second
+)
+
+// dashboards are served from other origins; the stream is read-only,
+// so cross-origin WebSocket connections are allowed
+var upgrader = websocket.Upgrader{
+	CheckOrigin: func(*http.Request) bool { return true },
+}
+
+// EventStream sends messages of the event hub as Server-Sent Events;
+// subscription can be resumed with the Last-Event-ID header
+func (s *server) EventStream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", 500)
+		return
+	}
+
+	f, lastID, err := parseSubscription(r)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+
+	sub := s.services.Hub.Subscribe(f, lastID)
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(200)
+	fmt.Fprintf(w, "retry: %d\n\n", sseRetryDelay/time.Millisecond)
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeatPeriod)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			if _, err = fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		case m, ok := <-sub.Messages():
+			if !ok {
+				// client is expected to reconnect with the last received ID
+				fmt.Fprintf(w, "event: error\ndata: %s\n\n", sub.Err())
+				flusher.Flush()
+				return
+			}
+			if !principal(r).Can(config.RoleViewer, m.Namespace) {
+				continue
+			}
+			data, err := json.Marshal(m)
+			if err != nil {
+				s.services.Logger.WithError(err).Error("failed to encode message")
+				continue
+			}
+			if _, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", m.ID, m.Type, data); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+// EventSocket sends messages of the event hub as WebSocket text frames;
+// subscription can be resumed with the 'last_event_id' parameter
+func (s *server) EventSocket(w http.ResponseWriter, r *http.Request) {
+	f, lastID, err := parseSubscription(r)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+
+	// upgrader replies with error itself
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	sub := s.services.Hub.Subscribe(f, lastID)
+	defer sub.Close()
+
+	// messages from client are ignored, reading is needed
+	// to process control frames and to notice disconnection
+	disconnected := make(chan struct{})
+	go func() {
+		defer close(disconnected)
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	heartbeat := time.NewTicker(streamHeartbeatPeriod)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-disconnected:
+			return
+		case <-heartbeat.C:
+			if err = conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(streamWriteTimeout)); err != nil {
+				return
+			}
+		case m, ok := <-sub.Messages():
+			if !ok {
+				code := websocket.CloseGoingAway
+				if sub.Err() == pubsub.ErrSlowSubscriber {
+					code = websocket.CloseTryAgainLater
+				}
+				msg := websocket.FormatCloseMessage(code, fmt.Sprint(sub.Err()))
+				conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(streamWriteTimeout))
+				return
+			}
+			if !principal(r).Can(config.RoleViewer, m.Namespace) {
+				continue
+			}
+			conn.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+			if err = conn.WriteJSON(m); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// parseSubscription reads filter from 'project' and 'namespace' parameters
+// (both may be repeated) and ID of the last received message
+func parseSubscription(r *http.Request) (*pubsub.Filter, uint64, error) {
+	query := r.URL.Query()
+	f := &pubsub.Filter{
+		Projects:   query["project"],
+		Namespaces: query["namespace"],
+	}
+
+	value := r.Header.Get("Last-Event-ID")
+	if value == "" {
+		value = query.Get("last_event_id")
+	}
+	if value == "" {
+		return f, 0, nil
+	}
+	lastID, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid last event ID '%s'", value)
+	}
+	return f, lastID, nil
+}
@@ -0,0 +1,97 @@
+package webserver
+
+import (
+	"crypto/tls"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/negroni"
+
+	"github.com/vitalyisaev2/buildgraph/config"
+)
+
+const (
+	// minimal period between checks of certificate files for changes
+	certCheckPeriod = 10 * time.Second
+)
+
+// certReloader serves the latest version of certificate and key files,
+// so that certificates could be renewed without restart
+type certReloader struct {
+	certFile string
+	keyFile  string
+	logger   *logrus.Logger
+
+	mutex   sync.Mutex
+	cert    *tls.Certificate
+	modTime [2]time.Time // modification time of certificate and key files
+	checked time.Time
+}
+
+// GetCertificate is called on every TLS handshake
+func (c *certReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if time.Since(c.checked) >= certCheckPeriod {
+		c.checked = time.Now()
+		if err := c.reload(); err != nil {
+			// broken files are ignored until they are fixed
+			c.logger.WithError(err).Error("failed to reload TLS certificate, keeping the previous one")
+		}
+	}
+	return c.cert, nil
+}
+
+// reload reads files if they have been changed since the last reading
+func (c *certReloader) reload() error {
+	var modTime [2]time.Time
+	for i, path := range []string{c.certFile, c.keyFile} {
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		modTime[i] = info.ModTime()
+	}
+	if c.cert != nil && modTime == c.modTime {
+		return nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(c.certFile, c.keyFile)
+	if err != nil {
+		return err
+	}
+	if c.cert != nil {
+		c.logger.WithField("path", c.certFile).Info("TLS certificate reloaded")
+	}
+	c.cert, c.modTime = &cert, modTime
+	return nil
+}
+
+func newCertReloader(cfg *config.TLSServerConfig, logger *logrus.Logger) (*certReloader, error) {
+	c := &certReloader{certFile: cfg.CertFile, keyFile: cfg.KeyFile, logger: logger}
+	if err := c.reload(); err != nil {
+		return nil, err
+	}
+	c.checked = time.Now()
+	return c, nil
+}
+
+// requireClientCert makes negroni middleware rejecting requests to admin routes
+// that were sent without client certificate signed by trusted CA
+func requireClientCert(router *mux.Router) negroni.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+		if routeName(router, r) == routeAdmin {
+			// chains are only built for certificates verified against client CA
+			if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 {
+				http.Error(w, "trusted client certificate is required", 403)
+				return
+			}
+		}
+		next(w, r)
+	}
+}
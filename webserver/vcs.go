@@ -0,0 +1,261 @@
+package webserver
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/vitalyisaev2/buildgraph/common"
+	"github.com/vitalyisaev2/buildgraph/config"
+	"github.com/vitalyisaev2/buildgraph/storage"
+	"github.com/vitalyisaev2/buildgraph/vcs"
+)
+
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 500
+)
+
+// pageView is a part of the list along with the cursor of the next part
+type pageView struct {
+	Items      interface{} `json:"items"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+}
+
+type projectView struct {
+	ID        common.ObjectID `json:"id"`
+	Provider  string          `json:"provider"`
+	Namespace string          `json:"namespace"`
+	Name      string          `json:"name"`
+	HTTPURL   string          `json:"http_url"`
+}
+
+type pushEventView struct {
+	ID        common.ObjectID `json:"id"`
+	ProjectID common.ObjectID `json:"project_id"`
+	Ref       string          `json:"ref"`
+	Received  time.Time       `json:"received"`
+	Commits   []*commitView   `json:"commits"`
+}
+
+type commitView struct {
+	ID       common.ObjectID `json:"id"`
+	Hash     string          `json:"hash"`
+	Message  string          `json:"message"`
+	Time     time.Time       `json:"time"`
+	URL      string          `json:"url"`
+	Author   *authorView     `json:"author"`
+	Added    []string        `json:"added"`
+	Modified []string        `json:"modified"`
+	Removed  []string        `json:"removed"`
+}
+
+type authorView struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+func newProjectView(p vcs.Project) *projectView {
+	return &projectView{
+		ID:        p.GetObjectID(),
+		Provider:  p.GetProvider(),
+		Namespace: p.GetNamespace(),
+		Name:      p.GetName(),
+		HTTPURL:   p.GetHTTPURL(),
+	}
+}
+
+func newPushEventView(e storage.StoredPushEvent) *pushEventView {
+	v := &pushEventView{
+		ID:        e.GetObjectID(),
+		ProjectID: e.GetProject().GetObjectID(),
+		Ref:       e.GetRef(),
+		Received:  e.GetReceived(),
+		Commits:   []*commitView{},
+	}
+	for _, c := range e.GetCommits() {
+		v.Commits = append(v.Commits, newCommitView(c))
+	}
+	return v
+}
+
+func newCommitView(c vcs.Commit) *commitView {
+	return &commitView{
+		ID:       c.GetObjectID(),
+		Hash:     c.GetHash(),
+		Message:  c.GetMessage(),
+		Time:     c.GetTimestamp(),
+		URL:      c.GetURL(),
+		Author:   &authorView{Name: c.GetAuthor().GetName(), Email: c.GetAuthor().GetEmail()},
+		Added:    c.GetAdded(),
+		Modified: c.GetModified(),
+		Removed:  c.GetRemoved(),
+	}
+}
+
+// ListVCSProjects replies with projects that events have been received for
+func (s *server) ListVCSProjects(w http.ResponseWriter, r *http.Request) {
+	page, err := parsePage(r)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+
+	projects, err := s.services.Storage.ListVCSProjects(r.Context(), page)
+	if err != nil {
+		s.replyStorageError(w, err)
+		return
+	}
+
+	// invisible projects are skipped, so the page may be shorter than requested
+	items := make([]*projectView, 0, len(projects))
+	var last common.Model
+	for _, p := range projects {
+		if principal(r).Can(config.RoleViewer, p.GetNamespace()) {
+			items = append(items, newProjectView(p))
+		}
+		last = p
+	}
+	s.writeJSON(w, &pageView{Items: items, NextCursor: nextCursor(page, len(projects), last)})
+}
+
+// GetVCSProject replies with a single project
+func (s *server) GetVCSProject(w http.ResponseWriter, r *http.Request) {
+	p, ok := s.getVCSProject(w, r)
+	if !ok {
+		return
+	}
+	s.writeJSON(w, newProjectView(p))
+}
+
+// ListPushEvents replies with push events of the project
+func (s *server) ListPushEvents(w http.ResponseWriter, r *http.Request) {
+	filter, page, ok := s.parseEventQuery(w, r)
+	if !ok {
+		return
+	}
+
+	events, err := s.services.Storage.ListPushEvents(r.Context(), filter, page)
+	if err != nil {
+		s.replyStorageError(w, err)
+		return
+	}
+
+	items := make([]*pushEventView, 0, len(events))
+	var last common.Model
+	for _, e := range events {
+		items = append(items, newPushEventView(e))
+		last = e
+	}
+	s.writeJSON(w, &pageView{Items: items, NextCursor: nextCursor(page, len(items), last)})
+}
+
+// ListCommits replies with commits of the project
+func (s *server) ListCommits(w http.ResponseWriter, r *http.Request) {
+	filter, page, ok := s.parseEventQuery(w, r)
+	if !ok {
+		return
+	}
+
+	commits, err := s.services.Storage.ListCommits(r.Context(), filter, page)
+	if err != nil {
+		s.replyStorageError(w, err)
+		return
+	}
+
+	items := make([]*commitView, 0, len(commits))
+	var last common.Model
+	for _, c := range commits {
+		items = append(items, newCommitView(c))
+		last = c
+	}
+	s.writeJSON(w, &pageView{Items: items, NextCursor: nextCursor(page, len(items), last)})
+}
+
+// getVCSProject finds project by ID from URL; replies with error if there is no such project
+func (s *server) getVCSProject(w http.ResponseWriter, r *http.Request) (vcs.Project, bool) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "invalid project ID", 400)
+		return nil, false
+	}
+
+	p, err := s.services.Storage.GetVCSProject(r.Context(), id)
+	if err != nil {
+		s.replyStorageError(w, err)
+		return nil, false
+	}
+	if !authorize(w, r, config.RoleViewer, p.GetNamespace()) {
+		return nil, false
+	}
+	return p, true
+}
+
+// parseEventQuery reads filter and page of event or commit list from request;
+// replies with error if request is invalid
+func (s *server) parseEventQuery(w http.ResponseWriter, r *http.Request) (*storage.EventFilter, storage.Page, bool) {
+	p, ok := s.getVCSProject(w, r)
+	if !ok {
+		return nil, storage.Page{}, false
+	}
+
+	page, err := parsePage(r)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return nil, storage.Page{}, false
+	}
+
+	query := r.URL.Query()
+	filter := &storage.EventFilter{
+		ProjectID: p.GetObjectID(),
+		Author:    query.Get("author"),
+		Ref:       query.Get("ref"),
+		Path:      query.Get("path"),
+	}
+	for name, dst := range map[string]*time.Time{"since": &filter.Since, "until": &filter.Until} {
+		if value := query.Get(name); value != "" {
+			if *dst, err = time.Parse(time.RFC3339, value); err != nil {
+				http.Error(w, fmt.Sprintf("invalid %s value (RFC3339 expected): %s", name, value), 400)
+				return nil, storage.Page{}, false
+			}
+		}
+	}
+	return filter, page, true
+}
+
+// parsePage reads cursor and limit from request
+func parsePage(r *http.Request) (storage.Page, error) {
+	page := storage.Page{Limit: defaultPageLimit}
+	query := r.URL.Query()
+
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 || limit > maxPageLimit {
+			return page, fmt.Errorf("invalid limit value (1-%d expected): %s", maxPageLimit, value)
+		}
+		page.Limit = limit
+	}
+
+	if value := query.Get("cursor"); value != "" {
+		data, err := base64.RawURLEncoding.DecodeString(value)
+		if err == nil {
+			page.Before, err = strconv.Atoi(string(data))
+		}
+		if err != nil || page.Before <= 0 {
+			return page, fmt.Errorf("invalid cursor value: %s", value)
+		}
+	}
+	return page, nil
+}
+
+// nextCursor returns cursor of the next page, or empty string if the list is over
+func nextCursor(page storage.Page, count int, last common.Model) string {
+	if count < page.Limit || last == nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(last.GetObjectID())))
+}
@@ -0,0 +1,220 @@
+package webserver
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vitalyisaev2/buildgraph/common"
+	"github.com/vitalyisaev2/buildgraph/storage"
+	"github.com/vitalyisaev2/buildgraph/vcs"
+	"github.com/vitalyisaev2/buildgraph/vcs/gitlab"
+)
+
+// storedPushEvent is a push event along with the time it was received at
+type storedPushEvent struct {
+	*gitlab.PushEvent
+	received time.Time
+}
+
+func (e *storedPushEvent) GetReceived() time.Time { return e.received }
+
+// vcsStorage additionally serves VCS projects, push events and commits;
+// filters of the requested lists are recorded
+type vcsStorage struct {
+	memStorage
+
+	vcsProjects []vcs.Project // newest first
+	events      []storage.StoredPushEvent
+	filters     []*storage.EventFilter
+}
+
+func (s *vcsStorage) ListVCSProjects(ctx context.Context, page storage.Page) ([]vcs.Project, error) {
+	var result []vcs.Project
+	for _, p := range s.vcsProjects {
+		if (page.Before == 0 || p.GetObjectID() < page.Before) && len(result) < page.Limit {
+			result = append(result, p)
+		}
+	}
+	return result, nil
+}
+
+func (s *vcsStorage) GetVCSProject(ctx context.Context, id common.ObjectID) (vcs.Project, error) {
+	for _, p := range s.vcsProjects {
+		if p.GetObjectID() == id {
+			return p, nil
+		}
+	}
+	return nil, storage.ErrNotFound
+}
+
+func (s *vcsStorage) ListPushEvents(
+	ctx context.Context,
+	filter *storage.EventFilter,
+	page storage.Page,
+) ([]storage.StoredPushEvent, error) {
+	s.filters = append(s.filters, filter)
+	var result []storage.StoredPushEvent
+	for _, e := range s.events {
+		if e.GetProject().GetObjectID() == filter.ProjectID && len(result) < page.Limit {
+			result = append(result, e)
+		}
+	}
+	return result, nil
+}
+
+func (s *vcsStorage) ListCommits(ctx context.Context, filter *storage.EventFilter, page storage.Page) ([]vcs.Commit, error) {
+	s.filters = append(s.filters, filter)
+	var result []vcs.Commit
+	for _, e := range s.events {
+		if e.GetProject().GetObjectID() == filter.ProjectID {
+			result = append(result, e.GetCommits()...)
+		}
+	}
+	return result, nil
+}
+
+func newVCSStorage(t *testing.T) *vcsStorage {
+	f, err := os.Open("../vcs/gitlab/test/push.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	event, err := gitlab.DecodeEvent(gitlab.PushHook, f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	push := event.(*gitlab.PushEvent)
+	push.SetObjectID(1)
+	push.Project.SetObjectID(1)
+	push.Commits[0].SetObjectID(1)
+
+	s := &vcsStorage{events: []storage.StoredPushEvent{&storedPushEvent{PushEvent: push, received: time.Now()}}}
+	for _, p := range []*gitlab.Project{
+		{Namespace: "namespace2", Name: "project1"},
+		{Namespace: "namespace1", Name: "project2"},
+		push.Project,
+	} {
+		p.SetObjectID(3 - len(s.vcsProjects))
+		s.vcsProjects = append(s.vcsProjects, p)
+	}
+	return s
+}
+
+func TestVCSAPI(t *testing.T) {
+	s := newVCSStorage(t)
+	ts := newTestServer(t, s)
+
+	// projects of the other namespaces are skipped, but paging goes on
+	var projects struct {
+		Items      []*projectView `json:"items"`
+		NextCursor string         `json:"next_cursor"`
+	}
+	decode(t, call(t, ts, "GET", "/vcs/projects?limit=2", "viewer-key", nil), &projects)
+	if assert.Len(t, projects.Items, 1) {
+		assert.Equal(t, "project2", projects.Items[0].Name)
+	}
+	assert.NotEmpty(t, projects.NextCursor)
+	cursor := projects.NextCursor
+	projects.NextCursor = ""
+	decode(t, call(t, ts, "GET", "/vcs/projects?limit=2&cursor="+cursor, "viewer-key", nil), &projects)
+	if assert.Len(t, projects.Items, 1) {
+		assert.Equal(t, gitlab.ProviderName, projects.Items[0].Provider)
+		assert.Equal(t, "project1", projects.Items[0].Name)
+	}
+	assert.Empty(t, projects.NextCursor)
+
+	var project projectView
+	decode(t, call(t, ts, "GET", "/vcs/projects/1", "viewer-key", nil), &project)
+	assert.Equal(t, "http://example.com/namespace1/project1.git", project.HTTPURL)
+	assert.Equal(t, 403, call(t, ts, "GET", "/vcs/projects/3", "viewer-key", nil).StatusCode)
+	assert.Equal(t, 404, call(t, ts, "GET", "/vcs/projects/4", "viewer-key", nil).StatusCode)
+
+	var events struct {
+		Items      []*pushEventView `json:"items"`
+		NextCursor string           `json:"next_cursor"`
+	}
+	decode(t, call(t, ts, "GET", "/vcs/projects/1/events?ref=refs/heads/master&since=2012-01-01T00:00:00Z", "viewer-key", nil), &events)
+	if assert.Len(t, events.Items, 1) && assert.Len(t, events.Items[0].Commits, 1) {
+		assert.Equal(t, "refs/heads/master", events.Items[0].Ref)
+		assert.Equal(t, "john@example.com", events.Items[0].Commits[0].Author.Email)
+	}
+	assert.Empty(t, events.NextCursor)
+	if assert.Len(t, s.filters, 1) {
+		assert.Equal(t, &storage.EventFilter{
+			ProjectID: 1,
+			Ref:       "refs/heads/master",
+			Since:     time.Date(2012, 1, 1, 0, 0, 0, 0, time.UTC),
+		}, s.filters[0])
+	}
+
+	var commits struct {
+		Items []*commitView `json:"items"`
+	}
+	decode(t, call(t, ts, "GET", "/vcs/projects/1/commits?author=john@example.com&path=CHANGELOG", "viewer-key", nil), &commits)
+	if assert.Len(t, commits.Items, 1) {
+		assert.Equal(t, []string{"CHANGELOG"}, commits.Items[0].Added)
+	}
+	if assert.Len(t, s.filters, 2) {
+		assert.Equal(t, "john@example.com", s.filters[1].Author)
+		assert.Equal(t, "CHANGELOG", s.filters[1].Path)
+	}
+
+	// invalid queries don't reach the storage
+	for _, path := range []string{
+		"/vcs/projects/1/events?since=yesterday",
+		"/vcs/projects/1/commits?until=2012-01-01",
+		"/vcs/projects/1/commits?limit=0",
+		"/vcs/projects?cursor=invalid",
+	} {
+		assert.Equal(t, 400, call(t, ts, "GET", path, "viewer-key", nil).StatusCode, path)
+	}
+	assert.Len(t, s.filters, 2)
+}
+
+func TestParsePage(t *testing.T) {
+	cursor := func(value string) string { return base64.RawURLEncoding.EncodeToString([]byte(value)) }
+
+	for query, expected := range map[string]storage.Page{
+		"":                               {Limit: defaultPageLimit},
+		"limit=1":                        {Limit: 1},
+		"limit=500":                      {Limit: maxPageLimit},
+		"cursor=" + cursor("7"):          {Before: 7, Limit: defaultPageLimit},
+		"limit=5&cursor=" + cursor("12"): {Before: 12, Limit: 5},
+	} {
+		page, err := parsePage(httptest.NewRequest("GET", "/vcs/projects?"+query, nil))
+		if assert.NoError(t, err, query) {
+			assert.Equal(t, expected, page, query)
+		}
+	}
+
+	for _, query := range []string{
+		"limit=0",
+		"limit=-1",
+		"limit=501",
+		"limit=ten",
+		"cursor=7",
+		"cursor=" + cursor("0"),
+		"cursor=" + cursor("-7"),
+		"cursor=" + cursor("seven"),
+	} {
+		_, err := parsePage(httptest.NewRequest("GET", "/vcs/projects?"+query, nil))
+		assert.Error(t, err, query)
+	}
+
+	// cursor is given only if the page is full, and it leads to the next page
+	last := &gitlab.Project{}
+	last.SetObjectID(42)
+	page := storage.Page{Limit: 2}
+	assert.Empty(t, nextCursor(page, 1, last))
+	assert.Empty(t, nextCursor(page, 2, nil))
+	next, err := parsePage(httptest.NewRequest("GET", "/vcs/projects?limit=2&cursor="+nextCursor(page, 2, last), nil))
+	if assert.NoError(t, err) {
+		assert.Equal(t, storage.Page{Before: 42, Limit: 2}, next)
+	}
+}
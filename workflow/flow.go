@@ -1 +1,160 @@
 package workflow
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+
+	"github.com/vitalyisaev2/buildgraph/config"
+	"github.com/vitalyisaev2/buildgraph/graph"
+	"github.com/vitalyisaev2/buildgraph/metrics"
+	"github.com/vitalyisaev2/buildgraph/projects"
+	"github.com/vitalyisaev2/buildgraph/pubsub"
+	"github.com/vitalyisaev2/buildgraph/vcs"
+)
+
+// Build states (see states.dot)
+const (
+	StatePlanned  = "planned"
+	StatePending  = "pending"
+	StateRunning  = "running"
+	StateSuccess  = "success"
+	StateFailed   = "failed"
+	StateCanceled = "canceled"
+)
+
+// BuildState is published every time the build of project changes its state
+type BuildState struct {
+	Project    string     `json:"project"` // ID of the project
+	State      string     `json:"state"`
+	Ref        string     `json:"ref,omitempty"`
+	Commit     string     `json:"commit,omitempty"`
+	PipelineID int        `json:"pipeline_id,omitempty"`
+	Job        string     `json:"job,omitempty"`
+	Phases     [][]string `json:"phases,omitempty"` // rebuild plan of the projects depending on this one
+}
+
+var _ Manager = (*defaultManager)(nil)
+
+type defaultManager struct {
+	logger   *logrus.Logger
+	projects projects.Registry
+	hub      pubsub.Hub
+}
+
+// RegisterVCSPushEvent plans rebuilding of the pushed project and its dependents
+// if the push is relevant for the project build
+func (m *defaultManager) RegisterVCSPushEvent(e vcs.PushEvent) error {
+	var (
+		paths  []string
+		commit string
+	)
+	for _, c := range e.GetCommits() {
+		paths = append(paths, c.GetAdded()...)
+		paths = append(paths, c.GetModified()...)
+		paths = append(paths, c.GetRemoved()...)
+		commit = c.GetHash()
+	}
+
+	for _, d := range m.describe(e.GetProject()) {
+		if d.Build == nil || !d.Build.MatchBranch(e.GetRef()) || !d.Build.MatchPaths(paths) {
+			continue
+		}
+
+		timer := prometheus.NewTimer(metrics.PlanDuration)
+		pts, err := m.projects.Graph().PhasicTopologicalSortFromNode(d.ID)
+		timer.ObserveDuration()
+		if err != nil {
+			return fmt.Errorf("failed to plan rebuilding of project %s: %v", d.ID, err)
+		}
+
+		m.logger.WithFields(logrus.Fields{"project": d.ID, "ref": e.GetRef()}).Info("rebuild planned")
+		m.publish(d, &BuildState{
+			Project: d.ID,
+			State:   StatePlanned,
+			Ref:     e.GetRef(),
+			Commit:  commit,
+			Phases:  graph.PhaseNames(pts, nil),
+		})
+	}
+	return nil
+}
+
+// RegisterVCSTagPushEvent does nothing yet: releases don't trigger builds
+func (m *defaultManager) RegisterVCSTagPushEvent(vcs.TagPushEvent) error { return nil }
+
+// RegisterVCSMergeRequestEvent does nothing yet: merge requests don't trigger builds
+func (m *defaultManager) RegisterVCSMergeRequestEvent(vcs.MergeRequestEvent) error { return nil }
+
+// RegisterCIPipelineEvent follows the state of pipeline of the described project
+func (m *defaultManager) RegisterCIPipelineEvent(e vcs.PipelineEvent) error {
+	for _, d := range m.describe(e.GetProject()) {
+		m.publish(d, &BuildState{
+			Project:    d.ID,
+			State:      ciState(e.GetStatus()),
+			Ref:        e.GetRef(),
+			Commit:     e.GetCommit(),
+			PipelineID: e.GetPipelineID(),
+		})
+	}
+	return nil
+}
+
+// RegisterCIJobEvent follows the state of pipeline jobs of the described project
+func (m *defaultManager) RegisterCIJobEvent(e vcs.JobEvent) error {
+	for _, d := range m.describe(e.GetProject()) {
+		m.publish(d, &BuildState{
+			Project:    d.ID,
+			State:      ciState(e.GetStatus()),
+			Ref:        e.GetRef(),
+			Commit:     e.GetCommit(),
+			PipelineID: e.GetPipelineID(),
+			Job:        e.GetName(),
+		})
+	}
+	return nil
+}
+
+// describe returns descriptions of the registered projects matching VCS project
+func (m *defaultManager) describe(p vcs.Project) []*config.Description {
+	var result []*config.Description
+	for _, d := range m.projects.Config().Descriptions {
+		if d.Namespace == p.GetNamespace() && d.Name == p.GetName() {
+			result = append(result, d)
+		}
+	}
+	return result
+}
+
+func (m *defaultManager) publish(d *config.Description, state *BuildState) {
+	m.hub.Publish(&pubsub.Message{
+		Type:      pubsub.TypeBuildState,
+		Namespace: d.Namespace,
+		Project:   d.Namespace + "/" + d.Name,
+		Data:      state,
+	})
+}
+
+// ciState maps CI statuses onto build states
+func ciState(status string) string {
+	switch status {
+	case "created", "pending", "waiting_for_resource", "preparing", "scheduled", "manual":
+		return StatePending
+	case "running":
+		return StateRunning
+	case "success":
+		return StateSuccess
+	case "failed":
+		return StateFailed
+	case "canceled", "skipped":
+		return StateCanceled
+	default:
+		return status
+	}
+}
+
+// NewManager creates workflow manager publishing build states to hub
+func NewManager(logger *logrus.Logger, registry projects.Registry, hub pubsub.Hub) Manager {
+	return &defaultManager{logger: logger, projects: registry, hub: hub}
+}
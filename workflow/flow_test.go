@@ -0,0 +1,144 @@
+package workflow
+
+import (
+	"io/ioutil"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vitalyisaev2/buildgraph/config"
+	"github.com/vitalyisaev2/buildgraph/projects"
+	"github.com/vitalyisaev2/buildgraph/pubsub"
+	"github.com/vitalyisaev2/buildgraph/vcs"
+	"github.com/vitalyisaev2/buildgraph/vcs/gitlab"
+)
+
+// memHub keeps published messages; calls of the other hub methods panic
+type memHub struct {
+	pubsub.Hub
+
+	mutex    sync.Mutex
+	messages []*pubsub.Message
+}
+
+func (h *memHub) Publish(m *pubsub.Message) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.messages = append(h.messages, m)
+}
+
+// states returns build states published since the previous call
+func (h *memHub) states() []*BuildState {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	var result []*BuildState
+	for _, m := range h.messages {
+		result = append(result, m.Data.(*BuildState))
+	}
+	h.messages = nil
+	return result
+}
+
+// readEvent decodes Gitlab event from test data
+func readEvent(t *testing.T, kind, path string) vcs.Event {
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	event, err := gitlab.DecodeEvent(kind, f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return event
+}
+
+func newTestManager(t *testing.T, build *config.BuildSpec) (Manager, *memHub) {
+	registry, err := projects.NewRegistry(&config.ProjectsConfig{
+		Descriptions: []*config.Description{
+			{ID: "n1_p1", Namespace: "namespace1", Name: "project1", Build: build},
+			{ID: "n1_p2", Namespace: "namespace1", Name: "project2"},
+			{ID: "n1_p3", Namespace: "namespace1", Name: "project3"},
+			{ID: "n2_p1", Namespace: "namespace2", Name: "project1"},
+		},
+		Relations: map[string][]string{
+			"n1_p1": {"n1_p2", "n1_p3"},
+			"n1_p2": {"n1_p3"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logger := logrus.New()
+	logger.Out = ioutil.Discard
+	hub := &memHub{}
+	return NewManager(logger, registry, hub), hub
+}
+
+func TestRegisterVCSPushEvent(t *testing.T) {
+	push := readEvent(t, gitlab.PushHook, "../vcs/gitlab/test/push.json").(vcs.PushEvent)
+
+	// rebuilding of the project and its dependents is planned
+	m, hub := newTestManager(t, &config.BuildSpec{Command: "make", Branches: []string{"master"}})
+	assert.NoError(t, m.RegisterVCSPushEvent(push))
+	states := hub.states()
+	if assert.Len(t, states, 1) {
+		assert.Equal(t, "n1_p1", states[0].Project)
+		assert.Equal(t, StatePlanned, states[0].State)
+		assert.Equal(t, push.GetRef(), states[0].Ref)
+		assert.NotEmpty(t, states[0].Commit)
+		assert.Equal(t, [][]string{{"n1_p1"}, {"n1_p2"}, {"n1_p3"}}, states[0].Phases)
+	}
+
+	// pushes irrelevant for the build are ignored
+	for _, build := range []*config.BuildSpec{
+		nil,
+		{Command: "make", Branches: []string{"release/*"}},
+		{Command: "make", Paths: &config.PathFilter{Include: []string{"docs/**"}}},
+	} {
+		m, hub = newTestManager(t, build)
+		assert.NoError(t, m.RegisterVCSPushEvent(push))
+		assert.Empty(t, hub.states(), "%+v", build)
+	}
+}
+
+func TestRegisterCIEvents(t *testing.T) {
+	m, hub := newTestManager(t, nil)
+
+	pipeline := readEvent(t, gitlab.PipelineHook, "../vcs/gitlab/test/pipeline.json").(vcs.PipelineEvent)
+	assert.NoError(t, m.RegisterCIPipelineEvent(pipeline))
+	states := hub.states()
+	if assert.Len(t, states, 1) {
+		assert.Equal(t, "n1_p1", states[0].Project)
+		assert.Equal(t, StateSuccess, states[0].State)
+		assert.Equal(t, pipeline.GetPipelineID(), states[0].PipelineID)
+	}
+
+	job := readEvent(t, gitlab.JobHook, "../vcs/gitlab/test/job.json").(vcs.JobEvent)
+	assert.NoError(t, m.RegisterCIJobEvent(job))
+	states = hub.states()
+	if assert.Len(t, states, 1) {
+		assert.Equal(t, StateFailed, states[0].State)
+		assert.Equal(t, job.GetName(), states[0].Job)
+	}
+}
+
+func TestCIState(t *testing.T) {
+	for status, state := range map[string]string{
+		"created":  StatePending,
+		"manual":   StatePending,
+		"running":  StateRunning,
+		"success":  StateSuccess,
+		"failed":   StateFailed,
+		"skipped":  StateCanceled,
+		"canceled": StateCanceled,
+		"unknown":  "unknown",
+	} {
+		assert.Equal(t, state, ciState(status), status)
+	}
+}
@@ -4,4 +4,8 @@ import "github.com/vitalyisaev2/buildgraph/vcs"
 
 type Manager interface {
 	RegisterVCSPushEvent(vcs.PushEvent) error
+	RegisterVCSTagPushEvent(vcs.TagPushEvent) error
+	RegisterVCSMergeRequestEvent(vcs.MergeRequestEvent) error
+	RegisterCIPipelineEvent(vcs.PipelineEvent) error
+	RegisterCIJobEvent(vcs.JobEvent) error
 }